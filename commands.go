@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"contact-management/src/app"
+	"contact-management/src/apps"
+	"contact-management/src/config"
+	"contact-management/src/helpers"
+	"contact-management/src/repositories"
+	"contact-management/src/utils"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const usage = `usage: contact-management <command> [args]
+
+commands:
+  serve                                   jalankan HTTP API (default)
+  migrate up | down [N|all] | status | force VERSION
+  seed                                    isi data awal (kategori)
+  user create --username U --role ROLE    buat user; password dibaca dari stdin bila --password kosong
+  user reset-password --username U        ganti password dan cabut semua sesi user
+  user unlock --username U                buka kunci login user setelah terlalu banyak percobaan gagal
+  tokens revoke-all --username U | --all  cabut semua sesi user tertentu atau semua user`
+
+var errUsage = errors.New(usage)
+
+func openDatabase(cfg *config.Config) (*sql.DB, error) {
+	db, err := apps.Connect(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("database connection failed: %w", err)
+	}
+	return db, nil
+}
+
+func closeDatabase(db *sql.DB, logger *logrus.Logger) {
+	if err := db.Close(); err != nil {
+		logger.Error("Failed to close database: ", err)
+	}
+}
+
+// repositoryDatabase membungkus koneksi untuk repository dengan dialect dan
+// DB_QUERY_TIMEOUT yang sama seperti saat serve.
+func repositoryDatabase(cfg *config.Config, db *sql.DB) *repositories.Database {
+	return repositories.NewDatabase(db, repositories.Dialect(cfg.Database.Driver), cfg.Database.QueryTimeout)
+}
+
+// openTokenService membangun TokenService beserta client Redis-nya lewat
+// app.OpenTokenStore. Client yang dikembalikan ditutup dengan closeRedis.
+func openTokenService(cfg *config.Config, logger *logrus.Logger) (*utils.TokenService, *redis.Client, error) {
+	tokenStore, redisClient, err := app.OpenTokenStore(cfg, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokenService, err := utils.NewTokenService(cfg, tokenStore, logger)
+	if err != nil {
+		closeRedis(redisClient, logger)
+		return nil, nil, fmt.Errorf("JWT setup failed: %w", err)
+	}
+
+	return tokenService, redisClient, nil
+}
+
+// requireSharedStore menolak perintah yang mengubah sesi atau kunci login milik server
+// yang sedang berjalan bila store-nya bukan Redis. Store memory hanya hidup di dalam
+// proses CLI ini, jadi perubahannya tidak akan pernah terlihat oleh server.
+func requireSharedStore(cfg *config.Config) error {
+	if cfg.Session.Store != "redis" {
+		return fmt.Errorf("perintah ini membutuhkan SESSION_STORE=redis; store %q tidak dibagi dengan server yang berjalan", cfg.Session.Store)
+	}
+	return nil
+}
+
+func closeRedis(client *redis.Client, logger *logrus.Logger) {
+	if err := client.Close(); err != nil {
+		logger.Error("Failed to close Redis: ", err)
+	}
+}
+
+// describeError menampilkan pesan validasi per field, bukan hanya "validation error".
+func describeError(err error) string {
+	var validationErrors helpers.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err.Error()
+	}
+
+	fields := make([]string, 0, len(validationErrors.Messages))
+	for field, message := range validationErrors.Messages {
+		fields = append(fields, field+": "+message)
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, "\n")
+}
+
+// newFlagSet membuat FlagSet yang mengembalikan error alih-alih keluar dari proses,
+// sehingga main yang menentukan exit code.
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	return flags
+}
+
+// readPassword membaca password dari stdin agar tidak tercatat di riwayat shell
+// maupun daftar proses.
+func readPassword(in io.Reader) (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("password tidak boleh kosong")
+	}
+	return password, nil
+}
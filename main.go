@@ -3,69 +3,51 @@ package main
 import (
 	"contact-management/src/apps"
 	"contact-management/src/config"
-	"contact-management/src/controllers"
-	"contact-management/src/middlewares"
-	"contact-management/src/repositories"
-	"contact-management/src/services"
-	"net/http"
-
-	"github.com/julienschmidt/httprouter"
+	"contact-management/src/helpers"
+	"fmt"
+	"log"
+	"os"
 )
 
 func main() {
 	cfg := config.LoadConfig()
 
-	logger := apps.LoggingApp()
-	logger.Info("Application started")
-
-	db, err := apps.Connect(cfg)
+	logger, err := apps.NewLogger(cfg.Log)
 	if err != nil {
-		logger.Fatal("Database connection failed: ", err)
+		log.Fatal("Logger setup failed: ", err)
 	}
-	defer db.Close()
-
-	userRepo := repositories.NewUserRepository(db)
-
-	authService := services.NewAuthService(userRepo)
-	authController := controllers.NewAuthController(authService)
-
-	router := httprouter.New()
-
-	router.POST("/register", authController.Register)
-	router.POST("/login", authController.Login)
-	router.GET("/me", middlewares.AuthMiddleware(authController.Me))
-	router.POST("/logout", middlewares.AuthMiddleware(authController.Logout))
-
-	userService := services.NewUserService(userRepo)
-	userController := controllers.NewUserController(userService)
+	helpers.SetLogger(logger)
 
-	router.GET("/users", middlewares.AuthMiddleware(userController.GetUser))
-	router.POST("/users", middlewares.AuthMiddleware(userController.CreateUser))
-	router.GET("/users/:username", middlewares.AuthMiddleware(userController.GetUserByUsername))
-	router.PUT("/users/:username", middlewares.AuthMiddleware(userController.UpdateUser))
-	router.DELETE("/users/:username", middlewares.AuthMiddleware(userController.DeleteUser))
-
-	categoryRepo := repositories.NewCategoryRepository(db)
-	categoryService := services.NewCategoryService(categoryRepo)
-	categoryController := controllers.NewCategoryController(categoryService)
-
-	router.GET("/categories", middlewares.AuthMiddleware(categoryController.GetAllCategories))
-	router.POST("/categories", middlewares.AuthMiddleware(categoryController.CreateCategory))
-	router.GET("/categories/:id", middlewares.AuthMiddleware(categoryController.GetCategoryByID))
-	router.PUT("/categories/:id", middlewares.AuthMiddleware(categoryController.UpdateCategory))
-	router.DELETE("/categories/:id", middlewares.AuthMiddleware(categoryController.DeleteCategory))
-	
-	brandProductRepo := repositories.NewBrandProductRepository(db)
-	brandProductService := services.NewBrandProductService(brandProductRepo)
-	brandProductController := controllers.NewBrandProductController(brandProductService)
+	command, args := "serve", []string{}
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
+	}
 
-	router.GET("/brand-products", middlewares.AuthMiddleware(brandProductController.GetAllBrandProducts))
-	router.POST("/brand-products", middlewares.AuthMiddleware(brandProductController.CreateBrandProduct))
-	router.GET("/brand-products/:id", middlewares.AuthMiddleware(brandProductController.GetBrandProductByID))
-	router.PUT("/brand-products/:id", middlewares.AuthMiddleware(brandProductController.UpdateBrandProduct))
-	router.DELETE("/brand-products/:id", middlewares.AuthMiddleware(brandProductController.DeleteBrandProduct))
+	switch command {
+	case "serve":
+		logger.Info("Application started")
+		err = runServe(cfg, logger)
+	case "migrate":
+		err = runMigrate(cfg, logger, args)
+	case "seed":
+		err = runSeed(cfg, logger)
+	case "user":
+		err = runUser(cfg, logger, args)
+	case "tokens":
+		err = runTokens(cfg, logger, args)
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return
+	default:
+		err = errUsage
+	}
 
-	port := ":8080"
-	logger.Info("Server running on port " + port)
-	logger.Fatal(http.ListenAndServe(port, router))
+	if err != nil {
+		logger.WithField("command", command).Error(err)
+		fmt.Fprintln(os.Stderr, describeError(err))
+		os.Exit(1)
+	}
+	if command == "serve" {
+		logger.Info("Application stopped")
+	}
 }
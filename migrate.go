@@ -0,0 +1,83 @@
+package main
+
+import (
+	"contact-management/src/apps"
+	"contact-management/src/config"
+	"contact-management/src/migrations"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+const migrateUsage = "usage: migrate up | down [N] | status | force VERSION"
+
+// runMigrate menjalankan subcommand migrate dengan migrasi yang ter-embed di binary.
+func runMigrate(cfg *config.Config, logger *logrus.Logger, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db, logger)
+
+	migrator, err := apps.NewMigrator(db, cfg.Database.Driver, migrations.ForDriver(cfg.Database.Driver), logger)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up(ctx)
+		fmt.Printf("%d migrasi dijalankan\n", count)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if args[1] == "all" {
+				steps = 0
+			} else if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return errors.New(migrateUsage)
+			}
+		}
+		count, err := migrator.Down(ctx, steps)
+		fmt.Printf("%d migrasi dibatalkan\n", count)
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if status.Dirty {
+				state += " (dirty)"
+			}
+			if status.Modified {
+				state += " (modified)"
+			}
+			fmt.Printf("%d  %-45s %s\n", status.Version, status.Name, state)
+		}
+		return nil
+	case "force":
+		if len(args) < 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return errors.New(migrateUsage)
+		}
+		return migrator.Force(ctx, version)
+	default:
+		return errors.New(migrateUsage)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"contact-management/src/config"
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"contact-management/src/services"
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// seedCategories adalah kategori awal katalog. Seed aman dijalankan berulang kali:
+// kategori yang namanya sudah ada dilewati.
+var seedCategories = []string{
+	"Streaming Video",
+	"Streaming Musik",
+	"Produktivitas",
+	"Desain",
+	"VPN",
+}
+
+func runSeed(cfg *config.Config, logger *logrus.Logger) error {
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db, logger)
+
+	ctx := context.Background()
+	database := repositoryDatabase(cfg, db)
+	auditService := services.NewAuditService(repositories.NewAuditLogRepository(database))
+	categoryService := services.NewCategoryService(repositories.NewCategoryRepository(database), database, auditService)
+
+	existing, err := categoryService.GetAllCategories(ctx)
+	if err != nil {
+		return err
+	}
+	names := make(map[string]bool, len(existing))
+	for _, category := range existing {
+		names[category.Name] = true
+	}
+
+	created := 0
+	for _, name := range seedCategories {
+		if names[name] {
+			continue
+		}
+		if err := categoryService.CreateCategory(ctx, &models.Category{Name: name}); err != nil {
+			return err
+		}
+		created++
+	}
+
+	logger.WithField("categories", created).Info("Seed selesai")
+	fmt.Printf("%d kategori ditambahkan\n", created)
+	return nil
+}
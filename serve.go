@@ -0,0 +1,31 @@
+package main
+
+import (
+	"contact-management/src/app"
+	"contact-management/src/apps"
+	"contact-management/src/config"
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runServe menjalankan HTTP API sampai SIGINT/SIGTERM. Seluruh dependency dan route
+// dirakit oleh app.New; Close menghentikan worker dulu, baru koneksi Redis dan DB.
+func runServe(cfg *config.Config, logger *logrus.Logger) error {
+	application, err := app.New(cfg, app.WithLogger(logger))
+	if err != nil {
+		return err
+	}
+	defer application.Close()
+
+	application.StartWorkers()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := apps.NewServer(cfg.App, application.Handler)
+	return apps.RunServer(ctx, server, cfg.App.ShutdownTimeout, logger)
+}
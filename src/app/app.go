@@ -0,0 +1,286 @@
+// Package app merakit seluruh dependency aplikasi beserta router-nya dari Config.
+// main dan test memakai builder yang sama, sehingga test menguji route yang persis
+// sama dengan produksi.
+package app
+
+import (
+	"contact-management/src/apps"
+	"contact-management/src/config"
+	"contact-management/src/gateways"
+	"contact-management/src/helpers"
+	"contact-management/src/middlewares"
+	"contact-management/src/repositories"
+	"contact-management/src/services"
+	"contact-management/src/utils"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+type options struct {
+	db          *sql.DB
+	logger      *logrus.Logger
+	tokenStore  utils.TokenStore
+	gateway     gateways.PaymentGateway
+	clock       func() time.Time
+	rateLimiter utils.RateLimiter
+}
+
+// Option mengganti salah satu dependency yang biasanya dibangun dari Config.
+type Option func(*options)
+
+// WithDB memakai koneksi database yang sudah ada. Koneksi ini tidak ditutup oleh Close.
+func WithDB(db *sql.DB) Option {
+	return func(o *options) { o.db = db }
+}
+
+// WithLogger memakai logger yang sudah ada alih-alih membangunnya dari LogConfig.
+func WithLogger(logger *logrus.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// WithTokenStore memakai token store tertentu; Redis tidak disentuh sama sekali.
+func WithTokenStore(store utils.TokenStore) Option {
+	return func(o *options) { o.tokenStore = store }
+}
+
+// WithGateway memakai payment gateway tertentu, misalnya FakeGateway di test.
+func WithGateway(gateway gateways.PaymentGateway) Option {
+	return func(o *options) { o.gateway = gateway }
+}
+
+// WithClock mengganti sumber waktu token dan sesi, termasuk TTL token store dan
+// hitungan login gagal in-memory.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) { o.clock = now }
+}
+
+// WithRateLimiter memakai rate limiter tertentu bila RATE_LIMIT_ENABLED aktif,
+// misalnya MemoryRateLimiter dengan clock palsu di test.
+func WithRateLimiter(limiter utils.RateLimiter) Option {
+	return func(o *options) { o.rateLimiter = limiter }
+}
+
+type App struct {
+	Config *config.Config
+	Logger *logrus.Logger
+	DB     *sql.DB
+	Redis  *redis.Client
+
+	TokenService        *utils.TokenService
+	AuditService        *services.AuditService
+	AuthService         *services.AuthService
+	UserService         *services.UserService
+	CategoryService     *services.CategoryService
+	BrandProductService *services.BrandProductService
+	ProductService      *services.ProductService
+	CredentialService   *services.CredentialService
+	OrderService        *services.OrderService
+	PaymentService      *services.PaymentService
+	OrderExpiryWorker   *services.OrderExpiryWorker
+	// RateLimiter bernilai nil bila rate limit dinonaktifkan.
+	RateLimiter utils.RateLimiter
+
+	// Router berisi semua route API; Handler adalah Router yang dibungkus
+	// middleware tingkat server seperti request id dan access log.
+	Router  *httprouter.Router
+	Handler http.Handler
+
+	ownsDB        bool
+	workerStarted bool
+}
+
+// New membangun App dari cfg. Dependency yang tidak diganti lewat Option dibangun
+// dari Config; yang dibuka di sini ditutup kembali oleh Close.
+func New(cfg *config.Config, opts ...Option) (*App, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	a := &App{Config: cfg, Logger: o.logger}
+	if a.Logger == nil {
+		logger, err := apps.NewLogger(cfg.Log)
+		if err != nil {
+			return nil, fmt.Errorf("logger setup failed: %w", err)
+		}
+		a.Logger = logger
+	}
+	helpers.SetLogger(a.Logger)
+
+	if err := a.build(o); err != nil {
+		a.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *App) build(o *options) error {
+	cfg := a.Config
+
+	a.DB = o.db
+	if a.DB == nil {
+		db, err := apps.Connect(cfg)
+		if err != nil {
+			return fmt.Errorf("database connection failed: %w", err)
+		}
+		a.DB, a.ownsDB = db, true
+	}
+
+	store := o.tokenStore
+	if store == nil {
+		redisStore, client, err := OpenTokenStore(cfg, a.Logger)
+		if err != nil {
+			return err
+		}
+		store, a.Redis = redisStore, client
+	}
+
+	tokenService, err := utils.NewTokenService(cfg, store, a.Logger)
+	if err != nil {
+		return fmt.Errorf("JWT setup failed: %w", err)
+	}
+	if o.clock != nil {
+		tokenService.WithClock(o.clock)
+		if memoryStore, ok := store.(*utils.MemoryTokenStore); ok {
+			memoryStore.WithClock(o.clock)
+		}
+	}
+	a.TokenService = tokenService
+
+	// Hitungan login gagal memakai backend yang sama dengan sesi; token store yang
+	// disuntikkan berarti tanpa Redis, jadi hitungan disimpan in-memory.
+	var attemptStore utils.LoginAttemptStore = utils.NewMemoryLoginAttemptStore()
+	if o.tokenStore == nil {
+		if attemptStore, err = utils.NewLoginAttemptStore(cfg.Session, a.Redis); err != nil {
+			return err
+		}
+	}
+	if memoryAttempts, ok := attemptStore.(*utils.MemoryLoginAttemptStore); ok && o.clock != nil {
+		memoryAttempts.WithClock(o.clock)
+	}
+
+	if cfg.RateLimit.Enabled {
+		a.RateLimiter = o.rateLimiter
+		if a.RateLimiter == nil {
+			if a.RateLimiter, err = a.newRateLimiter(); err != nil {
+				return err
+			}
+		}
+	}
+
+	encrypter, err := utils.NewEncrypter(cfg.Credential.EncryptionKey)
+	if err != nil {
+		return fmt.Errorf("CREDENTIAL_ENCRYPTION_KEY tidak valid: %w", err)
+	}
+
+	gateway := o.gateway
+	if gateway == nil {
+		gateway, err = gateways.NewPaymentGateway(cfg.Payment, cfg.App.Env)
+		if err != nil {
+			return fmt.Errorf("payment gateway setup failed: %w", err)
+		}
+	}
+
+	db := repositories.NewDatabase(a.DB, repositories.Dialect(cfg.Database.Driver), cfg.Database.QueryTimeout)
+	userRepo := repositories.NewUserRepository(db)
+	orderRepo := repositories.NewOrderRepository(db)
+
+	a.AuditService = services.NewAuditService(repositories.NewAuditLogRepository(db))
+	loginGuard := services.NewLoginGuard(attemptStore, cfg.Login, a.AuditService, a.Logger)
+
+	a.AuthService = services.NewAuthService(userRepo, a.TokenService, loginGuard, cfg.Password)
+	a.UserService = services.NewUserService(userRepo, repositories.NewPasswordResetRepository(db), a.TokenService, loginGuard, db, a.AuditService, cfg.Password)
+	if o.clock != nil {
+		a.UserService.WithClock(o.clock)
+	}
+	a.CategoryService = services.NewCategoryService(repositories.NewCategoryRepository(db), db, a.AuditService)
+	a.BrandProductService = services.NewBrandProductService(repositories.NewBrandProductRepository(db), db, a.AuditService)
+	a.ProductService = services.NewProductService(repositories.NewProductRepository(db), db, a.AuditService)
+	a.CredentialService = services.NewCredentialService(repositories.NewCredentialRepository(db), orderRepo, encrypter)
+	paymentRepo := repositories.NewPaymentRepository(db)
+	a.OrderService = services.NewOrderService(orderRepo, paymentRepo, a.CredentialService, db, gateway, a.Logger)
+	a.PaymentService = services.NewPaymentService(paymentRepo, orderRepo, a.OrderService, db, gateway, cfg.Order.ReservationTTL, a.Logger)
+	a.OrderExpiryWorker = services.NewOrderExpiryWorker(a.OrderService, cfg.Order)
+
+	resolver, err := helpers.NewClientIPResolver(cfg.App.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("APP_TRUSTED_PROXIES tidak valid: %w", err)
+	}
+
+	a.Router = a.routes()
+	a.Handler = middlewares.RequestLogger(a.Logger, resolver, a.Router)
+	return nil
+}
+
+// newRateLimiter memilih limiter sesuai RATE_LIMIT_STORE. Limiter Redis dibungkus
+// fallback in-memory, yang juga langsung dipakai bila App tidak memegang client Redis.
+func (a *App) newRateLimiter() (utils.RateLimiter, error) {
+	memory := utils.NewMemoryRateLimiter()
+	switch a.Config.RateLimit.Store {
+	case "memory":
+		return memory, nil
+	case "redis":
+		if a.Redis == nil {
+			a.Logger.Warn("Redis tidak tersedia untuk rate limit, memakai limiter in-memory")
+			return memory, nil
+		}
+		return utils.NewFallbackRateLimiter(utils.NewRedisRateLimiter(a.Redis), memory, a.Logger), nil
+	default:
+		return nil, fmt.Errorf("RATE_LIMIT_STORE %q tidak dikenal, gunakan redis atau memory", a.Config.RateLimit.Store)
+	}
+}
+
+// OpenTokenStore membangun client Redis dan token store sesuai SessionConfig. Redis yang
+// tidak terjangkau hanya fatal bila sesi memang disimpan di Redis. Client yang dikembalikan
+// harus ditutup pemanggil.
+func OpenTokenStore(cfg *config.Config, logger *logrus.Logger) (utils.TokenStore, *redis.Client, error) {
+	client := apps.NewRedisClient(cfg.Redis)
+
+	if err := apps.PingRedis(client, cfg.Redis.DialTimeout); err != nil {
+		if cfg.Session.Store == "redis" {
+			client.Close()
+			return nil, nil, fmt.Errorf("redis connection failed: %w", err)
+		}
+		logger.Warn("Redis is not reachable, continuing without it: ", err)
+	}
+
+	store, err := utils.NewTokenStore(cfg.Session, client)
+	if err != nil {
+		client.Close()
+		return nil, nil, fmt.Errorf("token store setup failed: %w", err)
+	}
+	return store, client, nil
+}
+
+// StartWorkers menjalankan background worker; hanya dipanggil oleh proses server.
+func (a *App) StartWorkers() {
+	a.OrderExpiryWorker.Start()
+	a.workerStarted = true
+}
+
+// Close menghentikan worker lalu menutup Redis dan database yang dibuka oleh New.
+// Urutannya penting: worker masih memakai koneksi sampai benar-benar berhenti.
+func (a *App) Close() {
+	if a.workerStarted {
+		a.OrderExpiryWorker.Stop()
+		a.workerStarted = false
+	}
+	if a.Redis != nil {
+		if err := a.Redis.Close(); err != nil {
+			a.Logger.Error("Failed to close Redis: ", err)
+		}
+		a.Redis = nil
+	}
+	if a.ownsDB && a.DB != nil {
+		if err := a.DB.Close(); err != nil {
+			a.Logger.Error("Failed to close database: ", err)
+		}
+		a.DB = nil
+	}
+}
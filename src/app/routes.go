@@ -0,0 +1,108 @@
+package app
+
+import (
+	"contact-management/src/controllers"
+	"contact-management/src/middlewares"
+	"contact-management/src/models"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// routes mendaftarkan semua endpoint API. Route yang mengubah data dibungkus
+// AuthMiddleware lalu RequirePermission sesuai permission role-nya. Setiap route
+// kecuali webhook payment dibatasi rate limit grupnya: auth, public atau api.
+func (a *App) routes() *httprouter.Router {
+	rateLimits := a.Config.RateLimit
+	authLimit := middlewares.RateLimit(a.RateLimiter, a.Logger, "auth", rateLimits.Auth)
+	publicLimit := middlewares.RateLimit(a.RateLimiter, a.Logger, "public", rateLimits.Public)
+	apiLimit := middlewares.RateLimit(a.RateLimiter, a.Logger, "api", rateLimits.API)
+
+	verifyToken := middlewares.AuthMiddleware(a.TokenService)
+	// authMiddleware dipasang di luar apiLimit agar rate limit bisa dihitung per username.
+	authMiddleware := func(next httprouter.Handle) httprouter.Handle {
+		return verifyToken(apiLimit(next))
+	}
+	catalogWrite := middlewares.RequirePermission(models.PermissionCatalogWrite)
+	manageUsers := middlewares.RequirePermission(models.PermissionUsersManage)
+	manageOrders := middlewares.RequirePermission(models.PermissionOrdersManage)
+	manageSystem := middlewares.RequirePermission(models.PermissionSystemManage)
+	readAudit := middlewares.RequirePermission(models.PermissionAuditRead)
+
+	router := httprouter.New()
+
+	authController := controllers.NewAuthController(a.AuthService)
+
+	router.POST("/register", authLimit(authController.Register))
+	router.POST("/login", authLimit(authController.Login))
+	router.POST("/auth/refresh", authLimit(authController.Refresh))
+	router.GET("/me", authMiddleware(authController.Me))
+	router.POST("/logout", authMiddleware(authController.Logout))
+	router.GET("/me/sessions", authMiddleware(authController.GetSessions))
+	router.DELETE("/me/sessions", authMiddleware(authController.LogoutEverywhere))
+	router.DELETE("/me/sessions/:id", authMiddleware(authController.RevokeSession))
+
+	logController := controllers.NewLogController(a.Logger)
+
+	router.GET("/admin/log-level", authMiddleware(manageSystem(logController.GetLogLevel)))
+	router.PUT("/admin/log-level", authMiddleware(manageSystem(logController.UpdateLogLevel)))
+
+	auditLogController := controllers.NewAuditLogController(a.AuditService)
+
+	router.GET("/audit-logs", authMiddleware(readAudit(auditLogController.GetAuditLogs)))
+
+	userController := controllers.NewUserController(a.UserService)
+
+	router.GET("/users", authMiddleware(manageUsers(userController.GetUser)))
+	router.POST("/users", authMiddleware(manageUsers(userController.CreateUser)))
+	router.GET("/users/:username", authMiddleware(manageUsers(userController.GetUserByUsername)))
+	router.PUT("/users/:username", authMiddleware(manageUsers(userController.UpdateUser)))
+	router.DELETE("/users/:username", authMiddleware(manageUsers(userController.DeleteUser)))
+	router.DELETE("/users/:username/lock", authMiddleware(manageUsers(userController.UnlockUser)))
+	router.POST("/users/:username/password-reset", authMiddleware(manageUsers(userController.IssuePasswordReset)))
+	router.PUT("/me/password", authMiddleware(userController.ChangePassword))
+	router.POST("/password-reset", authLimit(userController.ResetPassword))
+
+	categoryController := controllers.NewCategoryController(a.CategoryService)
+
+	router.GET("/categories", authMiddleware(categoryController.GetAllCategories))
+	router.POST("/categories", authMiddleware(catalogWrite(categoryController.CreateCategory)))
+	router.GET("/categories/:id", authMiddleware(categoryController.GetCategoryByID))
+	router.PUT("/categories/:id", authMiddleware(catalogWrite(categoryController.UpdateCategory)))
+	router.DELETE("/categories/:id", authMiddleware(catalogWrite(categoryController.DeleteCategory)))
+
+	brandProductController := controllers.NewBrandProductController(a.BrandProductService)
+
+	router.GET("/brand-products", authMiddleware(brandProductController.GetAllBrandProducts))
+	router.POST("/brand-products", authMiddleware(catalogWrite(brandProductController.CreateBrandProduct)))
+	router.GET("/brand-products/:id", authMiddleware(brandProductController.GetBrandProductByID))
+	router.PUT("/brand-products/:id", authMiddleware(catalogWrite(brandProductController.UpdateBrandProduct)))
+	router.DELETE("/brand-products/:id", authMiddleware(catalogWrite(brandProductController.DeleteBrandProduct)))
+
+	productController := controllers.NewProductController(a.ProductService)
+
+	router.GET("/products", authMiddleware(productController.GetAllProducts))
+	router.POST("/products", authMiddleware(catalogWrite(productController.CreateProduct)))
+	router.GET("/products/:id", authMiddleware(productController.GetProductByID))
+	router.PUT("/products/:id", authMiddleware(catalogWrite(productController.UpdateProduct)))
+	router.DELETE("/products/:id", authMiddleware(catalogWrite(productController.DeleteProduct)))
+
+	orderController := controllers.NewOrderController(a.OrderService)
+	credentialController := controllers.NewCredentialController(a.CredentialService)
+
+	router.POST("/orders", publicLimit(orderController.CreateOrder))
+	router.GET("/orders", authMiddleware(manageOrders(orderController.GetOrders)))
+	router.GET("/orders/:id", authMiddleware(manageOrders(orderController.GetOrderByID)))
+	router.PUT("/orders/:id/status", authMiddleware(manageOrders(orderController.UpdateOrderStatus)))
+	router.POST("/orders/:id/deliver", authMiddleware(manageOrders(orderController.DeliverOrder)))
+	router.POST("/products/:id/credentials", authMiddleware(catalogWrite(credentialController.UploadCredentials)))
+	router.POST("/credentials/lookup", publicLimit(credentialController.LookupCredential))
+
+	paymentController := controllers.NewPaymentController(a.PaymentService)
+
+	router.POST("/orders/:id/payments", publicLimit(paymentController.CreatePayment))
+	router.GET("/payments/:id", authMiddleware(manageOrders(paymentController.GetPaymentByID)))
+	router.POST("/payments/:id/cancel", authMiddleware(manageOrders(paymentController.CancelPayment)))
+	router.POST("/webhooks/payments/:provider", paymentController.HandleWebhook)
+
+	return router
+}
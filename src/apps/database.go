@@ -3,11 +3,17 @@ package apps
 import (
 	"contact-management/src/config"
 	"database/sql"
+	"fmt"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "modernc.org/sqlite"
 )
 
 func Connect(cfg *config.Config) (*sql.DB, error) {
+	if cfg.Database.Driver != config.DriverMySQL && cfg.Database.Driver != config.DriverSQLite {
+		return nil, fmt.Errorf("DB_DRIVER %q tidak didukung, gunakan mysql atau sqlite", cfg.Database.Driver)
+	}
+
 	db, err := sql.Open(cfg.Database.Driver, cfg.Database.DSN())
 	if err != nil {
 		return nil, err
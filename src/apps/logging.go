@@ -1,16 +1,65 @@
 package apps
 
 import (
+	"contact-management/src/config"
+	"contact-management/src/helpers"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 
 	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-func LoggingApp() *logrus.Logger {
+var ErrInvalidLogConfig = errors.New("konfigurasi log tidak valid")
+
+// NewLogger membangun satu logger untuk seluruh aplikasi dari LogConfig. Logger ini
+// dibuat sekali saat startup lalu diteruskan ke komponen yang membutuhkannya.
+// Output file dirotasi berdasarkan ukuran dan umur file oleh lumberjack.
+func NewLogger(cfg config.LogConfig) (*logrus.Logger, error) {
+	level, err := logrus.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, fmt.Errorf("%w: LOG_LEVEL %q", ErrInvalidLogConfig, cfg.Level)
+	}
+
 	logger := logrus.New()
+	logger.SetLevel(level)
+	logger.AddHook(helpers.RequestIDHook{})
+
+	switch cfg.Format {
+	case "text":
+		logger.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	case "json":
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		return nil, fmt.Errorf("%w: LOG_FORMAT %q", ErrInvalidLogConfig, cfg.Format)
+	}
+
+	var file io.Writer
+	if cfg.Output == "file" || cfg.Output == "both" {
+		if cfg.File == "" {
+			return nil, fmt.Errorf("%w: LOG_FILE kosong", ErrInvalidLogConfig)
+		}
+		file = &lumberjack.Logger{
+			Filename:   cfg.File,
+			MaxSize:    cfg.MaxSizeMB,
+			MaxAge:     cfg.MaxAgeDays,
+			MaxBackups: cfg.MaxBackups,
+			Compress:   cfg.Compress,
+		}
+	}
 
-	file, _ := os.OpenFile("app.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	logger.SetOutput(file)
+	switch cfg.Output {
+	case "stdout":
+		logger.SetOutput(os.Stdout)
+	case "file":
+		logger.SetOutput(file)
+	case "both":
+		logger.SetOutput(io.MultiWriter(os.Stdout, file))
+	default:
+		return nil, fmt.Errorf("%w: LOG_OUTPUT %q", ErrInvalidLogConfig, cfg.Output)
+	}
 
-	return logger
-}
\ No newline at end of file
+	return logger, nil
+}
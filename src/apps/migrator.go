@@ -0,0 +1,559 @@
+package apps
+
+import (
+	"contact-management/src/config"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+var ErrInvalidMigration = errors.New("file migrasi tidak valid")
+
+var ErrMigrationLocked = errors.New("migrasi sedang dijalankan oleh proses lain")
+
+var ErrMigrationDirty = errors.New("migrasi sebelumnya gagal di tengah jalan, perbaiki skema lalu jalankan force")
+
+var ErrMigrationModified = errors.New("file migrasi yang sudah dijalankan telah diubah")
+
+var ErrMigrationMissing = errors.New("migrasi yang tercatat di database tidak ditemukan")
+
+// migrationLockName adalah nama advisory lock MySQL (GET_LOCK) yang mencegah dua
+// instance aplikasi menjalankan migrasi bersamaan. SQLite tidak punya advisory lock;
+// di sana seluruh pekerjaan dijalankan dalam satu transaksi BEGIN IMMEDIATE.
+const migrationLockName = "contact_management_schema_migrations"
+
+const migrationLockTimeoutSeconds = 10
+
+const schemaMigrationsColumns = `(
+	version BIGINT PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	checksum CHAR(64) NOT NULL,
+	dirty BOOLEAN NOT NULL DEFAULT FALSE,
+	applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+)`
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Dirty     bool
+	// Modified bernilai true bila file up atau down berubah setelah migrasi dijalankan.
+	Modified bool
+}
+
+type appliedMigration struct {
+	version   int64
+	name      string
+	checksum  string
+	dirty     bool
+	appliedAt *time.Time
+}
+
+// LoadMigrations membaca pasangan file <version>_<name>.up.sql dan .down.sql dari fsys,
+// diurutkan berdasarkan version. Checksum dihitung dari isi file up dan down, sehingga
+// perubahan down milik migrasi yang sudah dijalankan juga terdeteksi.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: nama file %s", ErrInvalidMigration, entry.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(string(content)) == "" {
+			return nil, fmt.Errorf("%w: %s kosong", ErrInvalidMigration, entry.Name())
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("%w: version %d dipakai dua nama", ErrInvalidMigration, version)
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("%w: version %d harus punya file up dan down", ErrInvalidMigration, migration.Version)
+		}
+		migration.Checksum = migrationChecksum(migration.Up, migration.Down)
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// migrationChecksum menghitung sha256 dari file up dan down. Panjang file up ikut di-hash
+// agar isi yang berpindah dari up ke down tetap menghasilkan checksum berbeda.
+func migrationChecksum(up, down string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d\n%s%s", len(up), up, down)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+var (
+	blockBeginPattern = regexp.MustCompile(`(?i)\bBEGIN\b(\s*;|\s+TRANSACTION\b)?`)
+	blockEndPattern   = regexp.MustCompile(`(?i)\bEND\s*;`)
+)
+
+// splitStatements memecah isi file menjadi statement per statement, dengan ';' di akhir
+// baris sebagai pemisah, karena driver MySQL tidak menjalankan multi statement secara default.
+// ';' di dalam blok BEGIN ... END; (body trigger) tidak memisahkan statement, baik bloknya
+// satu baris maupun beberapa baris. Keterbatasan: kata BEGIN dan END; dikenali tanpa
+// memperhatikan string literal maupun komentar, dan ';' pemisah harus berada di akhir baris.
+func splitStatements(content string) []string {
+	var statements []string
+	var current strings.Builder
+	depth := 0
+
+	for _, line := range strings.Split(content, "\n") {
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		for _, match := range blockBeginPattern.FindAllStringSubmatch(line, -1) {
+			// BEGIN; dan BEGIN TRANSACTION memulai transaksi, bukan blok
+			if match[1] == "" {
+				depth++
+			}
+		}
+		depth = max(depth-len(blockEndPattern.FindAllString(line, -1)), 0)
+
+		if depth == 0 && strings.HasSuffix(strings.TrimSpace(line), ";") {
+			if statement := strings.TrimSpace(current.String()); statement != ";" {
+				statements = append(statements, statement)
+			}
+			current.Reset()
+		}
+	}
+
+	if statement := strings.TrimSpace(current.String()); statement != "" {
+		statements = append(statements, statement)
+	}
+	return statements
+}
+
+// Migrator menjalankan migrasi ter-embed dan mencatatnya di tabel schema_migrations.
+// Karena DDL MySQL tidak transaksional, setiap migrasi ditandai dirty sebelum dijalankan
+// dan baru dibersihkan setelah berhasil; migrasi dirty harus dibereskan manual lalu Force.
+// Di SQLite transaksi tetap di-commit saat gagal agar perilakunya sama dengan MySQL.
+type Migrator struct {
+	db         *sql.DB
+	driver     string
+	migrations []Migration
+	logger     *logrus.Logger
+}
+
+// NewMigrator membuat Migrator untuk driver mysql atau sqlite; fsys harus berisi
+// migrasi untuk driver yang sama.
+func NewMigrator(db *sql.DB, driver string, fsys fs.FS, logger *logrus.Logger) (*Migrator, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		driver:     driver,
+		migrations: migrations,
+		logger:     logger,
+	}, nil
+}
+
+// withLock menjalankan fn di satu koneksi yang memegang lock migrasi.
+// Lock terikat ke koneksi, jadi seluruh pekerjaan harus memakai conn yang sama.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	release, err := m.lock(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	err = m.ensureTable(ctx, conn)
+	if err == nil {
+		err = fn(conn)
+	}
+	if releaseErr := release(); err == nil {
+		err = releaseErr
+	}
+	return err
+}
+
+// lock mengambil lock migrasi di conn dan mengembalikan fungsi untuk melepasnya.
+func (m *Migrator) lock(ctx context.Context, conn *sql.Conn) (func() error, error) {
+	if m.driver == config.DriverSQLite {
+		if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+			var sqliteErr *sqlite.Error
+			if errors.As(err, &sqliteErr) && sqliteErr.Code()&0xff == sqlite3.SQLITE_BUSY {
+				return nil, ErrMigrationLocked
+			}
+			return nil, err
+		}
+		return func() error {
+			_, err := conn.ExecContext(context.Background(), "COMMIT")
+			return err
+		}, nil
+	}
+
+	var locked sql.NullInt64
+	err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", migrationLockName, migrationLockTimeoutSeconds).Scan(&locked)
+	if err != nil {
+		return nil, err
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		return nil, ErrMigrationLocked
+	}
+	return func() error {
+		var released sql.NullInt64
+		conn.QueryRowContext(context.Background(), "SELECT RELEASE_LOCK(?)", migrationLockName).Scan(&released)
+		return nil
+	}, nil
+}
+
+// tableQueries mengembalikan query jumlah tabel schema_migrations dan jumlah kolom
+// checksum-nya, yang dipakai untuk membedakan tabel baru, tabel lama, dan belum ada.
+func (m *Migrator) tableQueries() (string, string) {
+	if m.driver == config.DriverSQLite {
+		return "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'",
+			"SELECT COUNT(*) FROM pragma_table_info('schema_migrations') WHERE name = 'checksum'"
+	}
+	return `SELECT COUNT(*) FROM information_schema.tables
+		WHERE table_schema = DATABASE() AND table_name = 'schema_migrations'`,
+		`SELECT COUNT(*) FROM information_schema.columns
+		WHERE table_schema = DATABASE() AND table_name = 'schema_migrations' AND column_name = 'checksum'`
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, conn *sql.Conn) error {
+	tablesQuery, columnsQuery := m.tableQueries()
+
+	var tables, checksumColumns int
+	err := conn.QueryRowContext(ctx, tablesQuery).Scan(&tables)
+	if err != nil {
+		return err
+	}
+	err = conn.QueryRowContext(ctx, columnsQuery).Scan(&checksumColumns)
+	if err != nil {
+		return err
+	}
+
+	if tables > 0 && checksumColumns > 0 {
+		return nil
+	}
+	if tables == 0 {
+		_, err = conn.ExecContext(ctx, "CREATE TABLE schema_migrations "+schemaMigrationsColumns)
+		return err
+	}
+
+	if _, err := conn.ExecContext(ctx, "CREATE TABLE schema_migrations_new "+schemaMigrationsColumns); err != nil {
+		return err
+	}
+	if err := m.adoptLegacy(ctx, conn); err != nil {
+		return err
+	}
+	_, err = conn.ExecContext(ctx, "ALTER TABLE schema_migrations_new RENAME TO schema_migrations")
+	return err
+}
+
+// adoptLegacy memindahkan catatan dari CLI migrate lama, yang hanya menyimpan satu baris
+// (version, dirty), ke tabel baru. Semua migrasi sampai version itu dianggap sudah jalan.
+// Tabel lama disimpan sebagai schema_migrations_legacy.
+func (m *Migrator) adoptLegacy(ctx context.Context, conn *sql.Conn) error {
+	var version int64
+	var dirty bool
+	err := conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			break
+		}
+		_, err := conn.ExecContext(ctx, "INSERT INTO schema_migrations_new (version, name, checksum, dirty) VALUES (?, ?, ?, ?)",
+			migration.Version, migration.Name, migration.Checksum, dirty && migration.Version == version)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := conn.ExecContext(ctx, "ALTER TABLE schema_migrations RENAME TO schema_migrations_legacy"); err != nil {
+		return err
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"version": version,
+		"dirty":   dirty,
+	}).Warn("Tabel schema_migrations lama diadopsi, salinannya disimpan di schema_migrations_legacy")
+	return nil
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, checksum, dirty, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]appliedMigration{}
+	for rows.Next() {
+		var row appliedMigration
+		var appliedAt sql.NullTime
+		if err := rows.Scan(&row.version, &row.name, &row.checksum, &row.dirty, &appliedAt); err != nil {
+			return nil, err
+		}
+		if appliedAt.Valid {
+			row.appliedAt = &appliedAt.Time
+		}
+		applied[row.version] = row
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+// verify menolak melanjutkan bila ada migrasi dirty, migrasi yang filenya hilang,
+// atau file up yang isinya berbeda dari saat dijalankan.
+func (m *Migrator) verify(applied map[int64]appliedMigration) error {
+	for version, row := range applied {
+		if row.dirty {
+			return fmt.Errorf("%w: version %d", ErrMigrationDirty, version)
+		}
+		migration, ok := m.find(version)
+		if !ok {
+			return fmt.Errorf("%w: version %d", ErrMigrationMissing, version)
+		}
+		if migration.Checksum != row.checksum {
+			return fmt.Errorf("%w: version %d (%s)", ErrMigrationModified, version, migration.Name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) exec(ctx context.Context, conn *sql.Conn, content string) error {
+	for _, statement := range splitStatements(content) {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Up menjalankan semua migrasi yang belum tercatat, berurutan dari version terkecil,
+// dan mengembalikan jumlah migrasi yang dijalankan.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			_, err := conn.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, checksum, dirty) VALUES (?, ?, ?, TRUE)",
+				migration.Version, migration.Name, migration.Checksum)
+			if err != nil {
+				return err
+			}
+			if err := m.exec(ctx, conn, migration.Up); err != nil {
+				return fmt.Errorf("migrasi %d_%s gagal: %w", migration.Version, migration.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx, "UPDATE schema_migrations SET dirty = FALSE, applied_at = CURRENT_TIMESTAMP WHERE version = ?", migration.Version); err != nil {
+				return err
+			}
+
+			m.logger.Infof("Migrasi %d_%s dijalankan", migration.Version, migration.Name)
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down membatalkan steps migrasi terakhir yang sudah dijalankan. steps <= 0 berarti
+// semua migrasi dibatalkan.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if steps > 0 && count >= steps {
+				break
+			}
+
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			if _, err := conn.ExecContext(ctx, "UPDATE schema_migrations SET dirty = TRUE WHERE version = ?", migration.Version); err != nil {
+				return err
+			}
+			if err := m.exec(ctx, conn, migration.Down); err != nil {
+				return fmt.Errorf("rollback %d_%s gagal: %w", migration.Version, migration.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = ?", migration.Version); err != nil {
+				return err
+			}
+
+			m.logger.Infof("Migrasi %d_%s dibatalkan", migration.Version, migration.Name)
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Status mengembalikan keadaan setiap migrasi ter-embed, ditambah migrasi yang tercatat
+// di database tetapi filenya sudah tidak ada.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if row, ok := applied[migration.Version]; ok {
+				status.Applied = true
+				status.AppliedAt = row.appliedAt
+				status.Dirty = row.dirty
+				status.Modified = row.checksum != migration.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+
+		for version, row := range applied {
+			if _, ok := m.find(version); !ok {
+				statuses = append(statuses, MigrationStatus{
+					Version:   version,
+					Name:      row.name,
+					Applied:   true,
+					AppliedAt: row.appliedAt,
+					Dirty:     row.dirty,
+				})
+			}
+		}
+		sort.Slice(statuses, func(i, j int) bool {
+			return statuses[i].Version < statuses[j].Version
+		})
+		return nil
+	})
+	return statuses, err
+}
+
+// Force mencatat skema berada tepat di version tanpa menjalankan SQL apa pun: migrasi
+// sampai version ditandai bersih dengan checksum file saat ini, sisanya dihapus dari
+// catatan. Dipakai setelah memperbaiki migrasi dirty atau file yang sengaja diubah.
+// version 0 menghapus semua catatan.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if _, ok := m.find(version); !ok && version != 0 {
+		return fmt.Errorf("%w: version %d tidak ada", ErrInvalidMigration, version)
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if _, err := conn.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version > ?", version); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				_, err = conn.ExecContext(ctx, "UPDATE schema_migrations SET name = ?, checksum = ?, dirty = FALSE WHERE version = ?",
+					migration.Name, migration.Checksum, migration.Version)
+			} else {
+				_, err = conn.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, checksum, dirty) VALUES (?, ?, ?, FALSE)",
+					migration.Version, migration.Name, migration.Checksum)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		m.logger.Warnf("Versi migrasi dipaksa ke %d", version)
+		return nil
+	})
+}
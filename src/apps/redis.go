@@ -1,13 +1,63 @@
 package apps
 
-import "github.com/redis/go-redis/v9"
+import (
+	"contact-management/src/config"
+	"context"
+	"crypto/tls"
+	"time"
 
-func RedisClient() *redis.Client {
-	client := redis.NewClient(&redis.Options{
-		Addr: "localhost:6379",
-		Password: "",
-		DB: 0,
+	"github.com/redis/go-redis/v9"
+)
+
+// NewRedisClient membangun satu client Redis untuk seluruh aplikasi dari RedisConfig.
+// Bila SentinelMaster diisi, client memakai Sentinel dan mengikuti failover master.
+// Client harus ditutup dengan Close saat aplikasi berhenti.
+func NewRedisClient(cfg config.RedisConfig) *redis.Client {
+	var tlsConfig *tls.Config
+	if cfg.TLS {
+		tlsConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ServerName: cfg.TLSServerName,
+		}
+	}
+
+	if cfg.SentinelMaster != "" {
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.SentinelMaster,
+			SentinelAddrs:    cfg.SentinelAddrs,
+			SentinelPassword: cfg.SentinelPassword,
+			Username:         cfg.Username,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			PoolSize:         cfg.PoolSize,
+			MinIdleConns:     cfg.MinIdleConns,
+			DialTimeout:      cfg.DialTimeout,
+			ReadTimeout:      cfg.ReadTimeout,
+			WriteTimeout:     cfg.WriteTimeout,
+			PoolTimeout:      cfg.PoolTimeout,
+			TLSConfig:        tlsConfig,
+		})
+	}
+
+	return redis.NewClient(&redis.Options{
+		Addr:         cfg.Addr(),
+		Username:     cfg.Username,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		MinIdleConns: cfg.MinIdleConns,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		PoolTimeout:  cfg.PoolTimeout,
+		TLSConfig:    tlsConfig,
 	})
-	
-	return client
-}
\ No newline at end of file
+}
+
+// PingRedis memastikan Redis dapat dijangkau, dibatasi oleh timeout.
+func PingRedis(client *redis.Client, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return client.Ping(ctx).Err()
+}
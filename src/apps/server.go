@@ -0,0 +1,57 @@
+package apps
+
+import (
+	"contact-management/src/config"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NewServer membangun http.Server dari AppConfig lengkap dengan timeout dan batas
+// ukuran header, sehingga koneksi lambat tidak bisa menahan server selamanya.
+func NewServer(cfg config.AppConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+}
+
+// RunServer menjalankan server sampai ctx selesai (di main: saat SIGINT atau SIGTERM
+// diterima), lalu berhenti menerima koneksi baru dan menunggu request yang sedang berjalan
+// paling lama shutdownTimeout. Error dikembalikan bila server gagal start atau draining
+// melewati batas waktu.
+func RunServer(ctx context.Context, server *http.Server, shutdownTimeout time.Duration, logger *logrus.Logger) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("Server running on " + server.Addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Infof("Shutdown signal received, draining connections (timeout %s)", shutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		server.Close()
+		return err
+	}
+	return nil
+}
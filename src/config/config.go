@@ -1,52 +1,180 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	App      AppConfig
-	Log      LogConfig
+	Database   DatabaseConfig
+	Redis      RedisConfig
+	JWT        JWTConfig
+	Session    SessionConfig
+	App        AppConfig
+	Log        LogConfig
+	Payment    PaymentConfig
+	Order      OrderConfig
+	Credential CredentialConfig
+	RateLimit  RateLimitConfig
+	Login      LoginConfig
+	Password   PasswordConfig
 }
 
+const (
+	DriverMySQL  = "mysql"
+	DriverSQLite = "sqlite"
+)
+
 type DatabaseConfig struct {
+	// Driver adalah mysql atau sqlite. Untuk sqlite, Name berisi path file database
+	// dan Host, Port, User serta Password diabaikan.
 	Driver   string
 	Host     string
 	Port     int
 	User     string
 	Password string
 	Name     string
+	// QueryTimeout adalah batas waktu satu operasi repository. Nol berarti hanya
+	// mengikuti context request.
+	QueryTimeout time.Duration
 }
 
 type RedisConfig struct {
-	Host     string
-	Port     int
-	Password string
-	DB       int
+	Host          string
+	Port          int
+	Username      string
+	Password      string
+	DB            int
+	PoolSize      int
+	MinIdleConns  int
+	DialTimeout   time.Duration
+	ReadTimeout   time.Duration
+	WriteTimeout  time.Duration
+	PoolTimeout   time.Duration
+	TLS           bool
+	TLSServerName string
+	// SentinelMaster mengaktifkan mode failover; Host dan Port diabaikan bila diisi.
+	SentinelMaster   string
+	SentinelAddrs    []string
+	SentinelPassword string
 }
 
 type JWTConfig struct {
-	Secret     string
-	Expiration string
+	Secret            string
+	Expiration        string
+	RefreshExpiration string
+	Issuer            string
+	Audience          string
+	Keys              string
+	ActiveKeyID       string
+}
+
+type SessionConfig struct {
+	MaxPerUser int
+	Store      string
 }
 
 type AppConfig struct {
-	Env  string
-	Port int
-	Name string
+	Env               string
+	Port              int
+	Name              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	// ShutdownTimeout adalah batas waktu menunggu request yang sedang berjalan
+	// selesai setelah SIGINT/SIGTERM diterima.
+	ShutdownTimeout time.Duration
+	// TrustedProxies berisi IP atau CIDR reverse proxy yang X-Forwarded-For-nya dipercaya.
+	// Kosong berarti IP klien selalu diambil dari alamat koneksi.
+	TrustedProxies []string
 }
 
 type LogConfig struct {
-	File  string
-	Level string
+	File   string
+	Level  string
+	Format string
+	// Output menentukan tujuan log: stdout, file, atau both.
+	Output     string
+	MaxSizeMB  int
+	MaxAgeDays int
+	MaxBackups int
+	Compress   bool
+}
+
+type OrderConfig struct {
+	ReservationTTL time.Duration
+	ExpiryInterval time.Duration
+}
+
+type CredentialConfig struct {
+	EncryptionKey string
+}
+
+// RateLimitRule mengizinkan Limit request dalam Window terakhir. Limit nol
+// menonaktifkan aturan tersebut.
+type RateLimitRule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// RateLimitGroup adalah aturan satu grup route, dihitung terpisah per IP klien
+// dan per username.
+type RateLimitGroup struct {
+	IP       RateLimitRule
+	Username RateLimitRule
+}
+
+type RateLimitConfig struct {
+	Enabled bool
+	// Store adalah redis atau memory. Dengan redis, limiter in-memory tetap dipakai
+	// selama Redis tidak dapat dihubungi.
+	Store string
+	// Auth berlaku untuk /login, /register dan /auth/refresh, Public untuk endpoint
+	// tanpa login lainnya, dan API untuk endpoint yang membutuhkan token.
+	Auth   RateLimitGroup
+	Public RateLimitGroup
+	API    RateLimitGroup
+}
+
+// LoginConfig mengatur perlindungan brute-force login. MaxFailures dan
+// MaxFailuresPerIP bernilai nol menonaktifkan penguncian masing-masing.
+type LoginConfig struct {
+	// MaxFailures adalah jumlah login gagal per username sebelum akun dikunci.
+	MaxFailures int
+	// MaxFailuresPerIP adalah jumlah login gagal dari satu IP sebelum IP itu diblokir.
+	MaxFailuresPerIP int
+	// FailureWindow adalah masa berlaku hitungan gagal sejak kegagalan terakhir.
+	FailureWindow   time.Duration
+	LockoutDuration time.Duration
+	// DelayBase adalah jeda sebelum memeriksa password setelah satu kegagalan; jeda
+	// berlipat dua untuk setiap kegagalan berikutnya hingga DelayMax.
+	DelayBase time.Duration
+	DelayMax  time.Duration
+}
+
+// PasswordConfig mengatur kebijakan password baru dan token reset password dari admin.
+type PasswordConfig struct {
+	MinLength int
+	// ResetTokenTTL adalah masa berlaku token reset password sejak diterbitkan admin.
+	ResetTokenTTL time.Duration
+}
+
+type PaymentConfig struct {
+	Provider        string
+	BaseURL         string
+	SecretKey       string
+	CallbackToken   string
+	InvoiceDuration time.Duration
+	RequestTimeout  time.Duration
 }
 
 func LoadConfig() *Config {
@@ -56,54 +184,224 @@ func LoadConfig() *Config {
 	}
 
 	dbPort, _ := strconv.Atoi(getEnv("DB_PORT", "3306"))
+	dbQueryTimeout, _ := time.ParseDuration(getEnv("DB_QUERY_TIMEOUT", "5s"))
 	redisPort, _ := strconv.Atoi(getEnv("REDIS_PORT", "6379"))
 	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
+	redisPoolSize, _ := strconv.Atoi(getEnv("REDIS_POOL_SIZE", "20"))
+	redisMinIdle, _ := strconv.Atoi(getEnv("REDIS_MIN_IDLE_CONNS", "2"))
+	redisDialTimeout, _ := time.ParseDuration(getEnv("REDIS_DIAL_TIMEOUT", "5s"))
+	redisReadTimeout, _ := time.ParseDuration(getEnv("REDIS_READ_TIMEOUT", "3s"))
+	redisWriteTimeout, _ := time.ParseDuration(getEnv("REDIS_WRITE_TIMEOUT", "3s"))
+	redisPoolTimeout, _ := time.ParseDuration(getEnv("REDIS_POOL_TIMEOUT", "4s"))
+	redisTLS, _ := strconv.ParseBool(getEnv("REDIS_TLS", "false"))
 	appPort, _ := strconv.Atoi(getEnv("APP_PORT", "8080"))
+	appReadTimeout, _ := time.ParseDuration(getEnv("APP_READ_TIMEOUT", "15s"))
+	appReadHeaderTimeout, _ := time.ParseDuration(getEnv("APP_READ_HEADER_TIMEOUT", "5s"))
+	appWriteTimeout, _ := time.ParseDuration(getEnv("APP_WRITE_TIMEOUT", "30s"))
+	appIdleTimeout, _ := time.ParseDuration(getEnv("APP_IDLE_TIMEOUT", "60s"))
+	appMaxHeaderBytes, _ := strconv.Atoi(getEnv("APP_MAX_HEADER_BYTES", "1048576"))
+	appShutdownTimeout, _ := time.ParseDuration(getEnv("APP_SHUTDOWN_TIMEOUT", "20s"))
+	maxSessions, _ := strconv.Atoi(getEnv("SESSION_MAX_PER_USER", "5"))
+	logMaxSize, _ := strconv.Atoi(getEnv("LOG_MAX_SIZE_MB", "100"))
+	logMaxAge, _ := strconv.Atoi(getEnv("LOG_MAX_AGE_DAYS", "30"))
+	logMaxBackups, _ := strconv.Atoi(getEnv("LOG_MAX_BACKUPS", "10"))
+	logCompress, _ := strconv.ParseBool(getEnv("LOG_COMPRESS", "true"))
+	invoiceDuration, _ := time.ParseDuration(getEnv("PAYMENT_INVOICE_DURATION", "24h"))
+	paymentTimeout, _ := time.ParseDuration(getEnv("PAYMENT_REQUEST_TIMEOUT", "10s"))
+	reservationTTL, _ := time.ParseDuration(getEnv("ORDER_RESERVATION_TTL", "24h"))
+	expiryInterval, _ := time.ParseDuration(getEnv("ORDER_EXPIRY_INTERVAL", "1m"))
+	rateLimitEnabled, _ := strconv.ParseBool(getEnv("RATE_LIMIT_ENABLED", "true"))
+	loginMaxFailures, _ := strconv.Atoi(getEnv("LOGIN_MAX_FAILURES", "5"))
+	loginMaxFailuresPerIP, _ := strconv.Atoi(getEnv("LOGIN_MAX_FAILURES_PER_IP", "20"))
+	loginFailureWindow, _ := time.ParseDuration(getEnv("LOGIN_FAILURE_WINDOW", "15m"))
+	loginLockoutDuration, _ := time.ParseDuration(getEnv("LOGIN_LOCKOUT_DURATION", "15m"))
+	loginDelayBase, _ := time.ParseDuration(getEnv("LOGIN_DELAY_BASE", "250ms"))
+	loginDelayMax, _ := time.ParseDuration(getEnv("LOGIN_DELAY_MAX", "4s"))
+	passwordMinLength, _ := strconv.Atoi(getEnv("PASSWORD_MIN_LENGTH", "8"))
+	passwordResetTokenTTL, _ := time.ParseDuration(getEnv("PASSWORD_RESET_TOKEN_TTL", "1h"))
 
 	return &Config{
 		Database: DatabaseConfig{
-			Driver:   getEnv("DB_DRIVER", "mysql"),
-			Host:     getEnv("DB_HOST", "127.0.0.1"),
-			Port:     dbPort,
-			User:     getEnv("DB_USER", "root"),
-			Password: getEnv("DB_PASSWORD", ""),
-			Name:     getEnv("DB_NAME", "proapps"),
+			Driver:       getEnv("DB_DRIVER", DriverMySQL),
+			Host:         getEnv("DB_HOST", "127.0.0.1"),
+			Port:         dbPort,
+			User:         getEnv("DB_USER", "root"),
+			Password:     getEnv("DB_PASSWORD", ""),
+			Name:         getEnv("DB_NAME", "proapps"),
+			QueryTimeout: dbQueryTimeout,
 		},
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "127.0.0.1"),
-			Port:     redisPort,
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       redisDB,
+			Host:             getEnv("REDIS_HOST", "127.0.0.1"),
+			Port:             redisPort,
+			Username:         getEnv("REDIS_USERNAME", ""),
+			Password:         getEnv("REDIS_PASSWORD", ""),
+			DB:               redisDB,
+			PoolSize:         redisPoolSize,
+			MinIdleConns:     redisMinIdle,
+			DialTimeout:      redisDialTimeout,
+			ReadTimeout:      redisReadTimeout,
+			WriteTimeout:     redisWriteTimeout,
+			PoolTimeout:      redisPoolTimeout,
+			TLS:              redisTLS,
+			TLSServerName:    getEnv("REDIS_TLS_SERVER_NAME", ""),
+			SentinelMaster:   getEnv("REDIS_SENTINEL_MASTER", ""),
+			SentinelAddrs:    splitList(getEnv("REDIS_SENTINEL_ADDRS", "")),
+			SentinelPassword: getEnv("REDIS_SENTINEL_PASSWORD", ""),
 		},
 		JWT: JWTConfig{
-			Secret:     getEnv("JWT_SECRET", "default-secret-key"),
-			Expiration: getEnv("JWT_EXPIRATION", "24h"),
+			Secret:            getEnv("JWT_SECRET", ""),
+			Expiration:        getEnv("JWT_EXPIRATION", "15m"),
+			RefreshExpiration: getEnv("JWT_REFRESH_EXPIRATION", "720h"),
+			Issuer:            getEnv("JWT_ISSUER", "contact-management"),
+			Audience:          getEnv("JWT_AUDIENCE", "contact-management-api"),
+			Keys:              getEnv("JWT_KEYS", ""),
+			ActiveKeyID:       getEnv("JWT_ACTIVE_KEY_ID", ""),
+		},
+		Session: SessionConfig{
+			MaxPerUser: maxSessions,
+			Store:      getEnv("SESSION_STORE", "redis"),
 		},
 		App: AppConfig{
-			Env:  getEnv("APP_ENV", "development"),
-			Port: appPort,
-			Name: getEnv("APP_NAME", "Contact Management API"),
+			Env:               getEnv("APP_ENV", "development"),
+			Port:              appPort,
+			Name:              getEnv("APP_NAME", "Contact Management API"),
+			ReadTimeout:       appReadTimeout,
+			ReadHeaderTimeout: appReadHeaderTimeout,
+			WriteTimeout:      appWriteTimeout,
+			IdleTimeout:       appIdleTimeout,
+			MaxHeaderBytes:    appMaxHeaderBytes,
+			ShutdownTimeout:   appShutdownTimeout,
+			TrustedProxies:    splitList(getEnv("APP_TRUSTED_PROXIES", "")),
 		},
 		Log: LogConfig{
-			File:  getEnv("LOG_FILE", "app.log"),
-			Level: getEnv("LOG_LEVEL", "info"),
+			File:       getEnv("LOG_FILE", "app.log"),
+			Level:      getEnv("LOG_LEVEL", "info"),
+			Format:     getEnv("LOG_FORMAT", "text"),
+			Output:     getEnv("LOG_OUTPUT", "file"),
+			MaxSizeMB:  logMaxSize,
+			MaxAgeDays: logMaxAge,
+			MaxBackups: logMaxBackups,
+			Compress:   logCompress,
+		},
+		Payment: PaymentConfig{
+			Provider:        getEnv("PAYMENT_PROVIDER", "fake"),
+			BaseURL:         getEnv("PAYMENT_BASE_URL", "https://api.xendit.co"),
+			SecretKey:       getEnv("PAYMENT_SECRET_KEY", ""),
+			CallbackToken:   getEnv("PAYMENT_CALLBACK_TOKEN", ""),
+			InvoiceDuration: invoiceDuration,
+			RequestTimeout:  paymentTimeout,
+		},
+		Order: OrderConfig{
+			ReservationTTL: reservationTTL,
+			ExpiryInterval: expiryInterval,
+		},
+		Credential: CredentialConfig{
+			EncryptionKey: getEnv("CREDENTIAL_ENCRYPTION_KEY", ""),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: rateLimitEnabled,
+			Store:   getEnv("RATE_LIMIT_STORE", "redis"),
+			Auth: RateLimitGroup{
+				IP:       getRateLimitRule("RATE_LIMIT_AUTH_IP", "20/1m"),
+				Username: getRateLimitRule("RATE_LIMIT_AUTH_USERNAME", "10/1m"),
+			},
+			Public: RateLimitGroup{
+				IP: getRateLimitRule("RATE_LIMIT_PUBLIC_IP", "30/1m"),
+			},
+			API: RateLimitGroup{
+				IP:       getRateLimitRule("RATE_LIMIT_API_IP", "600/1m"),
+				Username: getRateLimitRule("RATE_LIMIT_API_USERNAME", "300/1m"),
+			},
+		},
+		Login: LoginConfig{
+			MaxFailures:      loginMaxFailures,
+			MaxFailuresPerIP: loginMaxFailuresPerIP,
+			FailureWindow:    loginFailureWindow,
+			LockoutDuration:  loginLockoutDuration,
+			DelayBase:        loginDelayBase,
+			DelayMax:         loginDelayMax,
+		},
+		Password: PasswordConfig{
+			MinLength:     passwordMinLength,
+			ResetTokenTTL: passwordResetTokenTTL,
 		},
 	}
 }
 
+// sqlitePragmas dipasang di setiap koneksi SQLite. WAL dan busy_timeout membuat pembaca
+// tidak terblokir penulis, _txlock=immediate membuat transaksi langsung memegang lock
+// tulis sebagai pengganti SELECT ... FOR UPDATE, dan foreign key disamakan dengan MySQL.
+const sqlitePragmas = "_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_txlock=immediate&_time_format=sqlite"
+
+// mysqlTimeParams membuat NOW() dan CURRENT_TIMESTAMP di setiap koneksi MySQL bernilai UTC
+// dan waktu yang dibaca diartikan sebagai UTC, sama dengan SQLite dan utcTime, apa pun
+// zona waktu server.
+const mysqlTimeParams = "parseTime=true&loc=UTC&time_zone=%27%2B00%3A00%27"
+
 func (c *DatabaseConfig) DSN() string {
-	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true",
+	if c.Driver == DriverSQLite {
+		return "file:" + c.Name + "?" + sqlitePragmas
+	}
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?%s",
 		c.User,
 		c.Password,
 		c.Host,
 		c.Port,
 		c.Name,
+		mysqlTimeParams,
 	)
 }
 
+func (c *AppConfig) Addr() string {
+	return fmt.Sprintf(":%d", c.Port)
+}
+
+func (c *RedisConfig) Addr() string {
+	return fmt.Sprintf("%s:%d", c.Host, c.Port)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+// getRateLimitRule membaca aturan berformat <limit>/<window>, misalnya 20/1m.
+// Nilai yang tidak valid diganti default agar salah ketik tidak mematikan rate limit.
+func getRateLimitRule(key, defaultValue string) RateLimitRule {
+	rule, err := ParseRateLimitRule(getEnv(key, defaultValue))
+	if err != nil {
+		log.Printf("Warning: %s tidak valid (%v), memakai %s", key, err, defaultValue)
+		rule, _ = ParseRateLimitRule(defaultValue)
+	}
+	return rule
+}
+
+// ParseRateLimitRule mengurai aturan berformat <limit>/<window>. Limit 0 berarti nonaktif.
+func ParseRateLimitRule(value string) (RateLimitRule, error) {
+	limitText, windowText, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return RateLimitRule{}, errors.New("format harus <limit>/<window>, contoh 20/1m")
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(limitText))
+	if err != nil || limit < 0 {
+		return RateLimitRule{}, fmt.Errorf("limit %q bukan bilangan bulat non-negatif", limitText)
+	}
+	window, err := time.ParseDuration(strings.TrimSpace(windowText))
+	if err != nil || window <= 0 {
+		return RateLimitRule{}, fmt.Errorf("window %q bukan durasi positif", windowText)
+	}
+	return RateLimitRule{Limit: limit, Window: window}, nil
+}
+
+// splitList memecah daftar dipisah koma dan membuang entri kosong.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
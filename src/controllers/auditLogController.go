@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"contact-management/src/helpers"
+	"contact-management/src/models"
+	"contact-management/src/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type AuditLogController struct {
+	AuditService *services.AuditService
+}
+
+func NewAuditLogController(auditService *services.AuditService) *AuditLogController {
+	return &AuditLogController{AuditService: auditService}
+}
+
+// GetAuditLogs menampilkan audit log terbaru lebih dulu. Query actor, action, entity_type
+// dan entity_id menyaring nilai yang sama persis; from dan to dalam format RFC3339.
+func (ac *AuditLogController) GetAuditLogs(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	query := r.URL.Query()
+	filter := models.AuditLogFilter{
+		Actor:      query.Get("actor"),
+		Action:     query.Get("action"),
+		EntityType: query.Get("entity_type"),
+		EntityID:   query.Get("entity_id"),
+		Page:       1,
+		Limit:      10,
+	}
+	var err error
+
+	if pageStr := query.Get("page"); pageStr != "" {
+		filter.Page, err = strconv.Atoi(pageStr)
+		if err != nil || filter.Page <= 0 {
+			helpers.BadRequestResponse(w, "Parameter page harus berupa angka positif", err)
+			return
+		}
+	}
+	if limitStr := query.Get("per_page"); limitStr != "" {
+		filter.Limit, err = strconv.Atoi(limitStr)
+		if err != nil || filter.Limit <= 0 || filter.Limit > 100 {
+			helpers.BadRequestResponse(w, "Parameter per_page harus berupa angka antara 1 dan 100", err)
+			return
+		}
+	}
+	for name, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			helpers.BadRequestResponse(w, "Parameter "+name+" harus berupa waktu RFC3339", err.Error())
+			return
+		}
+		*target = &parsed
+	}
+
+	auditLogs, err := ac.AuditService.GetAuditLogs(r.Context(), filter)
+	if err != nil {
+		helpers.ServerErrorResponse(w, "Gagal mendapatkan audit log", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mendapatkan audit log", auditLogs)
+}
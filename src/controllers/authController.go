@@ -4,12 +4,14 @@ import (
 	"contact-management/src/helpers"
 	"contact-management/src/models"
 	"contact-management/src/services"
+	"contact-management/src/utils"
 	"time"
 
-	// "contact-management/src/utils"
 	"encoding/json"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/julienschmidt/httprouter"
 )
@@ -19,9 +21,11 @@ type AuthController struct {
 }
 
 type userResponse struct {
-	UserId    int    `json:"user_id"`
-	Username  string `json:"username"`
-	CreatedAt time.Time `json:"created_at"`
+	UserId      int                 `json:"user_id"`
+	Username    string              `json:"username"`
+	Role        models.Role         `json:"role"`
+	Permissions []models.Permission `json:"permissions,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
 }
 
 func NewAuthController(authService *services.AuthService) *AuthController {
@@ -36,7 +40,7 @@ func (a *AuthController) Register(w http.ResponseWriter, r *http.Request, ps htt
 		return
 	}
 
-	err = a.AuthService.Register(&user)
+	err = a.AuthService.Register(r.Context(), &user)
 	if err != nil {
 		if validationErrs, ok := err.(helpers.ValidationErrors); ok {
 			helpers.ValidationErrorResponse(w, "Validasi gagal", validationErrs.Messages)
@@ -46,13 +50,14 @@ func (a *AuthController) Register(w http.ResponseWriter, r *http.Request, ps htt
 			helpers.ConflictResponse(w, "Username sudah digunakan")
 			return
 		}
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal mendaftar user", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal mendaftar user", err)
 		return
 	}
 
 	result := userResponse{
 		UserId:    user.UserId,
 		Username:  user.Username,
+		Role:      user.Role,
 		CreatedAt: user.CreatedAt,
 	}
 
@@ -69,7 +74,13 @@ func (a *AuthController) Login(w http.ResponseWriter, r *http.Request, ps httpro
 		return
 	}
 
-	token, err := a.AuthService.Login(user)
+	meta := models.SessionMeta{
+		Device:    r.Header.Get("X-Device-Name"),
+		IP:        helpers.ClientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+
+	tokens, err := a.AuthService.Login(r.Context(), user, meta)
 	if err != nil {
 		if validationErrs, ok := err.(helpers.ValidationErrors); ok {
 			helpers.ValidationErrorResponse(w, "Validasi gagal", validationErrs.Messages)
@@ -79,34 +90,114 @@ func (a *AuthController) Login(w http.ResponseWriter, r *http.Request, ps httpro
 			helpers.ErrorResponse(w, http.StatusUnauthorized, "Username atau password salah", err.Error())
 			return
 		}
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal login", err.Error())
+		var lockedErr *services.LoginLockedError
+		if errors.As(err, &lockedErr) {
+			w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(lockedErr.RetryAfter.Seconds())))))
+			if lockedErr.Scope == services.LockScopeIP {
+				helpers.TooManyRequestsResponse(w, "Terlalu banyak percobaan login gagal, coba lagi nanti")
+				return
+			}
+			helpers.ErrorResponse(w, http.StatusLocked, "Akun dikunci sementara, coba lagi nanti", err.Error())
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal login", err)
 		return
 	}
-	helpers.SuccessResponse(w, http.StatusOK, "Berhasil login", map[string]string{"token": token})
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil login", tokens)
 	return
 }
 
+func (a *AuthController) Refresh(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var request models.RefreshTokenRequest
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helpers.ErrorResponse(w, http.StatusBadRequest, "Gagal memproses input", err.Error())
+		return
+	}
+
+	tokens, err := a.AuthService.Refresh(r.Context(), &request)
+	if err != nil {
+		if validationErrs, ok := err.(helpers.ValidationErrors); ok {
+			helpers.ValidationErrorResponse(w, "Validasi gagal", validationErrs.Messages)
+			return
+		}
+		if errors.Is(err, utils.ErrRefreshTokenReused) {
+			helpers.UnauthorizedResponse(w, "Refresh token sudah pernah dipakai, sesi terkait telah dicabut")
+			return
+		}
+		if errors.Is(err, utils.ErrInvalidRefreshToken) {
+			helpers.UnauthorizedResponse(w, "Refresh token tidak valid atau sudah kedaluwarsa")
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal memperbarui token", err)
+		return
+	}
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil memperbarui token", tokens)
+}
+
 func (a *AuthController) Me(w http.ResponseWriter, r *http.Request, ps httprouter.Params)  {
 
 	username := r.Context().Value("username").(string)
-	user, err := a.AuthService.Me(username)
+	user, err := a.AuthService.Me(r.Context(), username)
 	if err != nil {
 		helpers.ErrorResponse(w, http.StatusUnauthorized, "Gagal mengambil informasi user", err.Error())
 		return
 	}
 	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mengambil informasi user", userResponse{
-		UserId:    user.UserId,
-		Username:  user.Username,
-		CreatedAt: user.CreatedAt,
+		UserId:      user.UserId,
+		Username:    user.Username,
+		Role:        user.Role,
+		Permissions: user.Role.Permissions(),
+		CreatedAt:   user.CreatedAt,
 	})
 }
 
 func (a *AuthController) Logout(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	username := r.Context().Value("username").(string)
-	err := a.AuthService.Logout(username, r.Header.Get("Authorization"))
+	token := r.Context().Value("token").(string)
+	err := a.AuthService.Logout(r.Context(), username, token)
 	if err != nil {
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal logout", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal logout", err)
 		return
 	}
 	helpers.SuccessResponse(w, http.StatusOK, "Berhasil logout", nil)
 }
+
+func (a *AuthController) GetSessions(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	username := r.Context().Value("username").(string)
+	sessionID := r.Context().Value("session_id").(string)
+
+	sessions, err := a.AuthService.ListSessions(r.Context(), username, sessionID)
+	if err != nil {
+		helpers.ServerErrorResponse(w, "Gagal mengambil data sesi", err)
+		return
+	}
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mengambil data sesi", sessions)
+}
+
+func (a *AuthController) RevokeSession(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	username := r.Context().Value("username").(string)
+
+	err := a.AuthService.RevokeSession(r.Context(), username, ps.ByName("id"))
+	if err != nil {
+		if errors.Is(err, utils.ErrSessionNotFound) {
+			helpers.NotFoundResponse(w, "Sesi tidak ditemukan")
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal mengakhiri sesi", err)
+		return
+	}
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mengakhiri sesi", nil)
+}
+
+func (a *AuthController) LogoutEverywhere(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	username := r.Context().Value("username").(string)
+
+	err := a.AuthService.LogoutEverywhere(r.Context(), username)
+	if err != nil {
+		helpers.ServerErrorResponse(w, "Gagal logout dari semua perangkat", err)
+		return
+	}
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil logout dari semua perangkat", nil)
+}
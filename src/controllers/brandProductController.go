@@ -30,13 +30,13 @@ func (bpc *BrandProductController) CreateBrandProduct(w http.ResponseWriter, r *
 		return
 	}
 
-	err = bpc.brandProductService.CreateBrandProduct(&brandProduct)
+	err = bpc.brandProductService.CreateBrandProduct(r.Context(), &brandProduct)
 	if err != nil {
 		if validationErr, ok := err.(helpers.ValidationErrors); ok {
 			helpers.BadRequestResponse(w, "Gagal membuat brand product", validationErr.Messages)
 			return
 		}
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal membuat brand product", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal membuat brand product", err)
 		return
 	}
 
@@ -45,9 +45,9 @@ func (bpc *BrandProductController) CreateBrandProduct(w http.ResponseWriter, r *
 }
 
 func (bpc *BrandProductController) GetAllBrandProducts(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	brandProducts, err := bpc.brandProductService.GetAllBrandProducts()
+	brandProducts, err := bpc.brandProductService.GetAllBrandProducts(r.Context())
 	if err != nil {
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal mendapatkan data brand product", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal mendapatkan data brand product", err)
 		return
 	}
 	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mendapatkan data brand product", brandProducts)
@@ -61,13 +61,13 @@ func (bpc *BrandProductController) GetBrandProductByID(w http.ResponseWriter, r
 		return
 	}
 
-	brandProduct, err := bpc.brandProductService.GetBrandProductByID(id)
+	brandProduct, err := bpc.brandProductService.GetBrandProductByID(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, repositories.ErrorBrandProductNotFound) {
 			helpers.NotFoundResponse(w, "Brand product tidak ditemukan")
 			return
 		}
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal mendapatkan data brand product", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal mendapatkan data brand product", err)
 		return
 	}
 
@@ -90,7 +90,7 @@ func (bpc *BrandProductController) UpdateBrandProduct(w http.ResponseWriter, r *
 		return
 	}
 
-	err = bpc.brandProductService.UpdateBrandProduct(id, &brandProduct)
+	err = bpc.brandProductService.UpdateBrandProduct(r.Context(), id, &brandProduct)
 	if err != nil {
 		if errors.Is(err, repositories.ErrorBrandProductNotFound) {
 			helpers.NotFoundResponse(w, "Brand product tidak ditemukan")
@@ -104,7 +104,7 @@ func (bpc *BrandProductController) UpdateBrandProduct(w http.ResponseWriter, r *
 			helpers.BadRequestResponse(w, "Gagal memperbarui brand product", validationErr.Messages)
 			return
 		}
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal memperbarui brand product", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal memperbarui brand product", err)
 		return
 	}
 
@@ -119,13 +119,13 @@ func (bpc *BrandProductController) DeleteBrandProduct(w http.ResponseWriter, r *
 		return
 	}
 
-	err = bpc.brandProductService.DeleteBrandProduct(id)
+	err = bpc.brandProductService.DeleteBrandProduct(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, repositories.ErrorBrandProductNotFound) {
 			helpers.NotFoundResponse(w, "Brand product tidak ditemukan")
 			return
 		}
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal menghapus brand product", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal menghapus brand product", err)
 		return
 	}
 
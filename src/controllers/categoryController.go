@@ -1,7 +1,6 @@
 package controllers
 
 import (
-	"contact-management/src/apps"
 	"contact-management/src/helpers"
 	"contact-management/src/models"
 	"contact-management/src/repositories"
@@ -19,14 +18,13 @@ type CategoryController struct {
 }
 
 func NewCategoryController(categoryService *services.CategoryService) *CategoryController {
-	apps.LoggingApp().Info("Ini adalah service dari memory", categoryService)
 	return &CategoryController{categoryService: categoryService}
 }
 
 func (c *CategoryController) GetAllCategories(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	categories, err := c.categoryService.GetAllCategories()
+	categories, err := c.categoryService.GetAllCategories(r.Context())
 	if err != nil {
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal mengambil data kategori", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal mengambil data kategori", err)
 		return
 	}
 	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mengambil data kategori", categories)
@@ -42,13 +40,13 @@ func(c *CategoryController) CreateCategory(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	err = c.categoryService.CreateCategory(&category)
+	err = c.categoryService.CreateCategory(r.Context(), &category)
 	if err != nil {
 		if validationErr, ok := err.(helpers.ValidationErrors); ok {
 			helpers.BadRequestResponse(w, "Gagal membuat kategori", validationErr.Messages)
 			return
 		}
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal membuat kategori", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal membuat kategori", err)
 		return
 	}
 
@@ -63,13 +61,13 @@ func (c *CategoryController) GetCategoryByID(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	category, err := c.categoryService.GetCategoryByID(id)
+	category, err := c.categoryService.GetCategoryByID(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, repositories.ErrorCategoryNotFound) {
 			helpers.NotFoundResponse(w, "Kategori tidak ditemukan")
 			return
 		}
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal mendapatkan data kategori", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal mendapatkan data kategori", err)
 		return
 	}
 
@@ -92,7 +90,7 @@ func (c *CategoryController) UpdateCategory(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	err = c.categoryService.UpdateCategory(&category, id)
+	err = c.categoryService.UpdateCategory(r.Context(), &category, id)
 	if err != nil {
 		if errors.Is(err, repositories.ErrorCategoryNotFound) {
 			helpers.NotFoundResponse(w, "Kategori tidak ditemukan")
@@ -104,7 +102,7 @@ func (c *CategoryController) UpdateCategory(w http.ResponseWriter, r *http.Reque
 			return
 		}
 		
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal memperbarui kategori", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal memperbarui kategori", err)
 		return
 	}
 
@@ -119,13 +117,13 @@ func (c *CategoryController) DeleteCategory(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	err = c.categoryService.DeleteCategory(id)
+	err = c.categoryService.DeleteCategory(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, repositories.ErrorCategoryNotFound) {
 			helpers.NotFoundResponse(w, "Kategori tidak ditemukan")
 			return
 		}
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal menghapus kategori", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal menghapus kategori", err)
 		return
 	}
 
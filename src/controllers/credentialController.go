@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"contact-management/src/helpers"
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"contact-management/src/services"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type CredentialController struct {
+	credentialService *services.CredentialService
+}
+
+func NewCredentialController(credentialService *services.CredentialService) *CredentialController {
+	return &CredentialController{credentialService: credentialService}
+}
+
+func (cc *CredentialController) UploadCredentials(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	productID, err := strconv.Atoi(ps.ByName("id"))
+	if err != nil {
+		helpers.BadRequestResponse(w, "ID harus berupa angka", err)
+		return
+	}
+
+	var request models.CredentialUploadRequest
+
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helpers.BadRequestResponse(w, "Gagal memproses input", err)
+		return
+	}
+
+	result, err := cc.credentialService.UploadCredentials(r.Context(), productID, &request)
+	if err != nil {
+		if validationErr, ok := err.(helpers.ValidationErrors); ok {
+			helpers.BadRequestResponse(w, "Gagal mengunggah credential", validationErr.Messages)
+			return
+		}
+		if errors.Is(err, repositories.ErrorProductNotFound) {
+			helpers.NotFoundResponse(w, "Produk tidak ditemukan")
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal mengunggah credential", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusCreated, "Berhasil mengunggah credential", result)
+	return
+}
+
+func (cc *CredentialController) LookupCredential(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var request models.CredentialLookupRequest
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helpers.BadRequestResponse(w, "Gagal memproses input", err)
+		return
+	}
+
+	credential, err := cc.credentialService.LookupCredential(r.Context(), &request)
+	if err != nil {
+		if validationErr, ok := err.(helpers.ValidationErrors); ok {
+			helpers.BadRequestResponse(w, "Gagal mengambil credential", validationErr.Messages)
+			return
+		}
+		if errors.Is(err, repositories.ErrorOrderNotFound) {
+			helpers.NotFoundResponse(w, "Order tidak ditemukan")
+			return
+		}
+		if errors.Is(err, services.ErrCredentialNotDelivered) {
+			helpers.ConflictResponse(w, err.Error())
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal mengambil credential", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mengambil credential", credential)
+	return
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"contact-management/src/helpers"
+	"contact-management/src/models"
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/sirupsen/logrus"
+)
+
+// LogController mengatur level logger aplikasi saat runtime tanpa restart.
+type LogController struct {
+	logger *logrus.Logger
+}
+
+func NewLogController(logger *logrus.Logger) *LogController {
+	return &LogController{logger: logger}
+}
+
+func (lc *LogController) GetLogLevel(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mengambil level log", models.LogLevelResponse{
+		Level: lc.logger.GetLevel().String(),
+	})
+}
+
+func (lc *LogController) UpdateLogLevel(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var request models.LogLevelRequest
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helpers.BadRequestResponse(w, "Gagal memproses input", err)
+		return
+	}
+
+	validate := helpers.Validator()
+	if err := validate.Struct(request); err != nil {
+		helpers.ValidationErrorResponse(w, "Validasi gagal", helpers.FormatValidationError(err))
+		return
+	}
+
+	level, _ := logrus.ParseLevel(request.Level)
+	previous := lc.logger.GetLevel()
+	lc.logger.SetLevel(level)
+	lc.logger.WithFields(logrus.Fields{
+		"from":     previous.String(),
+		"to":       level.String(),
+		"username": r.Context().Value("username"),
+	}).Warn("Log level changed")
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mengubah level log", models.LogLevelResponse{
+		Level: level.String(),
+	})
+}
@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"contact-management/src/helpers"
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"contact-management/src/services"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type OrderController struct {
+	orderService *services.OrderService
+}
+
+func NewOrderController(orderService *services.OrderService) *OrderController {
+	return &OrderController{orderService: orderService}
+}
+
+func (oc *OrderController) CreateOrder(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var order models.Order
+
+	err := json.NewDecoder(r.Body).Decode(&order)
+	if err != nil {
+		helpers.BadRequestResponse(w, "Gagal memproses input", err)
+		return
+	}
+
+	err = oc.orderService.CreateOrder(r.Context(), &order)
+	if err != nil {
+		if validationErr, ok := err.(helpers.ValidationErrors); ok {
+			helpers.BadRequestResponse(w, "Gagal membuat order", validationErr.Messages)
+			return
+		}
+		if errors.Is(err, repositories.ErrorProductNotFound) {
+			helpers.BadRequestResponse(w, "Gagal membuat order", map[string]string{"product_id": "Produk tidak ditemukan"})
+			return
+		}
+		if errors.Is(err, repositories.ErrorOutOfStock) {
+			helpers.ConflictResponse(w, "Stok produk habis")
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal membuat order", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusCreated, "Berhasil membuat order", order)
+	return
+}
+
+func (oc *OrderController) GetOrders(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	query := r.URL.Query()
+	filter := models.OrderFilter{
+		Status: query.Get("status"),
+		Page:   1,
+		Limit:  10,
+	}
+	var err error
+
+	if pageStr := query.Get("page"); pageStr != "" {
+		filter.Page, err = strconv.Atoi(pageStr)
+		if err != nil || filter.Page <= 0 {
+			helpers.BadRequestResponse(w, "Parameter page harus berupa angka positif", err)
+			return
+		}
+	}
+	if limitStr := query.Get("per_page"); limitStr != "" {
+		filter.Limit, err = strconv.Atoi(limitStr)
+		if err != nil || filter.Limit <= 0 {
+			helpers.BadRequestResponse(w, "Parameter per_page harus berupa angka positif", err)
+			return
+		}
+	}
+
+	orders, err := oc.orderService.GetOrders(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownOrderStatus) {
+			helpers.BadRequestResponse(w, "Parameter status tidak valid", err.Error())
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal mendapatkan data order", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mendapatkan data order", orders)
+	return
+}
+
+func (oc *OrderController) GetOrderByID(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, err := strconv.Atoi(ps.ByName("id"))
+	if err != nil {
+		helpers.BadRequestResponse(w, "ID harus berupa angka", err)
+		return
+	}
+
+	order, err := oc.orderService.GetOrderByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrorOrderNotFound) {
+			helpers.NotFoundResponse(w, "Order tidak ditemukan")
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal mendapatkan data order", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mendapatkan data order", order)
+	return
+}
+
+func (oc *OrderController) UpdateOrderStatus(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, err := strconv.Atoi(ps.ByName("id"))
+	if err != nil {
+		helpers.BadRequestResponse(w, "ID harus berupa angka", err)
+		return
+	}
+
+	var request models.OrderStatusRequest
+
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helpers.BadRequestResponse(w, "Gagal memproses input", err)
+		return
+	}
+
+	order, err := oc.orderService.TransitionOrder(r.Context(), id, request.Status)
+	if err != nil {
+		var transitionErr *services.InvalidTransitionError
+		if errors.As(err, &transitionErr) {
+			helpers.ConflictResponse(w, transitionErr.Error())
+			return
+		}
+		if errors.Is(err, services.ErrOrderStatusChanged) {
+			helpers.ConflictResponse(w, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrUnknownOrderStatus) {
+			helpers.BadRequestResponse(w, "Status order tidak valid", err.Error())
+			return
+		}
+		if errors.Is(err, repositories.ErrorOrderNotFound) {
+			helpers.NotFoundResponse(w, "Order tidak ditemukan")
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal memperbarui status order", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil memperbarui status order", order)
+	return
+}
+
+func (oc *OrderController) DeliverOrder(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, err := strconv.Atoi(ps.ByName("id"))
+	if err != nil {
+		helpers.BadRequestResponse(w, "ID harus berupa angka", err)
+		return
+	}
+
+	order, err := oc.orderService.DeliverOrder(r.Context(), id)
+	if err != nil {
+		var transitionErr *services.InvalidTransitionError
+		if errors.As(err, &transitionErr) {
+			helpers.ConflictResponse(w, transitionErr.Error())
+			return
+		}
+		if errors.Is(err, repositories.ErrorCredentialUnavailable) {
+			helpers.ConflictResponse(w, "Credential untuk produk ini habis")
+			return
+		}
+		if errors.Is(err, repositories.ErrorOrderNotFound) {
+			helpers.NotFoundResponse(w, "Order tidak ditemukan")
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal mengirim credential", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mengirim credential", order)
+	return
+}
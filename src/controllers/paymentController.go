@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"contact-management/src/gateways"
+	"contact-management/src/helpers"
+	"contact-management/src/repositories"
+	"contact-management/src/services"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const maxWebhookBodySize = 1 << 20
+
+type PaymentController struct {
+	paymentService *services.PaymentService
+}
+
+func NewPaymentController(paymentService *services.PaymentService) *PaymentController {
+	return &PaymentController{paymentService: paymentService}
+}
+
+func (pc *PaymentController) CreatePayment(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	orderID, err := strconv.Atoi(ps.ByName("id"))
+	if err != nil {
+		helpers.BadRequestResponse(w, "ID harus berupa angka", err)
+		return
+	}
+
+	payment, err := pc.paymentService.CreatePayment(r.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrorOrderNotFound) {
+			helpers.NotFoundResponse(w, "Order tidak ditemukan")
+			return
+		}
+		if errors.Is(err, services.ErrOrderNotPayable) {
+			helpers.ConflictResponse(w, err.Error())
+			return
+		}
+		if errors.Is(err, repositories.ErrorProductNotFound) {
+			helpers.NotFoundResponse(w, "Produk tidak ditemukan")
+			return
+		}
+		var gatewayErr *gateways.GatewayError
+		if errors.As(err, &gatewayErr) {
+			helpers.ErrorResponse(w, http.StatusBadGateway, "Gagal membuat pembayaran", err.Error())
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal membuat pembayaran", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusCreated, "Berhasil membuat pembayaran", payment)
+	return
+}
+
+func (pc *PaymentController) GetPaymentByID(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, err := strconv.Atoi(ps.ByName("id"))
+	if err != nil {
+		helpers.BadRequestResponse(w, "ID harus berupa angka", err)
+		return
+	}
+
+	payment, err := pc.paymentService.GetPaymentByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrorPaymentNotFound) {
+			helpers.NotFoundResponse(w, "Pembayaran tidak ditemukan")
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal mendapatkan data pembayaran", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mendapatkan data pembayaran", payment)
+	return
+}
+
+func (pc *PaymentController) CancelPayment(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, err := strconv.Atoi(ps.ByName("id"))
+	if err != nil {
+		helpers.BadRequestResponse(w, "ID harus berupa angka", err)
+		return
+	}
+
+	payment, err := pc.paymentService.CancelPayment(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrorPaymentNotFound) {
+			helpers.NotFoundResponse(w, "Pembayaran tidak ditemukan")
+			return
+		}
+		if errors.Is(err, services.ErrPaymentNotCancellable) {
+			helpers.ConflictResponse(w, err.Error())
+			return
+		}
+		var gatewayErr *gateways.GatewayError
+		if errors.As(err, &gatewayErr) {
+			helpers.ErrorResponse(w, http.StatusBadGateway, "Gagal membatalkan pembayaran", err.Error())
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal membatalkan pembayaran", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil membatalkan pembayaran", payment)
+	return
+}
+
+// HandleWebhook menerima callback dari payment provider. Endpoint ini tidak memakai
+// AuthMiddleware; keaslian request diverifikasi lewat token atau signature provider.
+func (pc *PaymentController) HandleWebhook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		helpers.BadRequestResponse(w, "Gagal membaca payload webhook", err.Error())
+		return
+	}
+
+	applied, err := pc.paymentService.HandleWebhook(r.Context(), ps.ByName("provider"), r.Header, body)
+	if err != nil {
+		if errors.Is(err, gateways.ErrUnknownProvider) {
+			helpers.NotFoundResponse(w, "Payment provider tidak dikenal")
+			return
+		}
+		if errors.Is(err, gateways.ErrInvalidSignature) {
+			helpers.UnauthorizedResponse(w, "Signature webhook tidak valid")
+			return
+		}
+		if errors.Is(err, gateways.ErrInvalidWebhookPayload) {
+			helpers.BadRequestResponse(w, "Payload webhook tidak valid", err.Error())
+			return
+		}
+		if errors.Is(err, repositories.ErrorPaymentNotFound) {
+			helpers.NotFoundResponse(w, "Pembayaran tidak ditemukan")
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal memproses webhook", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Webhook diterima", map[string]bool{"applied": applied})
+	return
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"contact-management/src/helpers"
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"contact-management/src/services"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type ProductController struct {
+	productService *services.ProductService
+}
+
+func NewProductController(productService *services.ProductService) *ProductController {
+	return &ProductController{productService: productService}
+}
+
+func (pc *ProductController) CreateProduct(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var product models.Product
+
+	err := json.NewDecoder(r.Body).Decode(&product)
+	if err != nil {
+		helpers.BadRequestResponse(w, "Gagal memproses input", err)
+		return
+	}
+
+	err = pc.productService.CreateProduct(r.Context(), &product)
+	if err != nil {
+		if validationErr, ok := err.(helpers.ValidationErrors); ok {
+			helpers.BadRequestResponse(w, "Gagal membuat produk", validationErr.Messages)
+			return
+		}
+		if errors.Is(err, repositories.ErrorBrandProductNotFound) {
+			helpers.BadRequestResponse(w, "Gagal membuat produk", map[string]string{"brand_product_id": "Brand product tidak ditemukan"})
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal membuat produk", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusCreated, "Berhasil membuat produk", product)
+	return
+}
+
+func (pc *ProductController) GetAllProducts(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	products, err := pc.productService.GetAllProducts(r.Context())
+	if err != nil {
+		helpers.ServerErrorResponse(w, "Gagal mendapatkan data produk", err)
+		return
+	}
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mendapatkan data produk", products)
+	return
+}
+
+func (pc *ProductController) GetProductByID(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, err := strconv.Atoi(ps.ByName("id"))
+	if err != nil {
+		helpers.BadRequestResponse(w, "ID harus berupa angka", err)
+		return
+	}
+
+	product, err := pc.productService.GetProductByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrorProductNotFound) {
+			helpers.NotFoundResponse(w, "Produk tidak ditemukan")
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal mendapatkan data produk", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mendapatkan data produk", product)
+	return
+}
+
+func (pc *ProductController) UpdateProduct(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, err := strconv.Atoi(ps.ByName("id"))
+	if err != nil {
+		helpers.BadRequestResponse(w, "ID harus berupa angka", err)
+		return
+	}
+
+	var product models.Product
+
+	err = json.NewDecoder(r.Body).Decode(&product)
+	if err != nil {
+		helpers.BadRequestResponse(w, "Gagal memproses input", err)
+		return
+	}
+
+	err = pc.productService.UpdateProduct(r.Context(), id, &product)
+	if err != nil {
+		if errors.Is(err, repositories.ErrorProductNotFound) {
+			helpers.NotFoundResponse(w, "Produk tidak ditemukan")
+			return
+		}
+		if validationErr, ok := err.(helpers.ValidationErrors); ok {
+			helpers.BadRequestResponse(w, "Gagal memperbarui produk", validationErr.Messages)
+			return
+		}
+		if errors.Is(err, repositories.ErrorBrandProductNotFound) {
+			helpers.BadRequestResponse(w, "Gagal memperbarui produk", map[string]string{"brand_product_id": "Brand product tidak ditemukan"})
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal memperbarui produk", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil memperbarui produk", product)
+	return
+}
+
+func (pc *ProductController) DeleteProduct(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, err := strconv.Atoi(ps.ByName("id"))
+	if err != nil {
+		helpers.BadRequestResponse(w, "ID harus berupa angka", err)
+		return
+	}
+
+	err = pc.productService.DeleteProduct(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrorProductNotFound) {
+			helpers.NotFoundResponse(w, "Produk tidak ditemukan")
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal menghapus produk", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil menghapus produk", nil)
+	return
+}
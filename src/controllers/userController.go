@@ -44,9 +44,9 @@ func (uc *UserController) GetUser(w http.ResponseWriter, r *http.Request, ps htt
 		}
 	}
 
-	users, err := uc.UserService.GetUsers(page, limit)
+	users, err := uc.UserService.GetUsers(r.Context(), page, limit)
 	if err != nil {
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal mendapatkan data user", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal mendapatkan data user", err)
 		return
 	}
 
@@ -62,23 +62,30 @@ func (uc *UserController) CreateUser(w http.ResponseWriter, r *http.Request, ps
 		return
 	}
 
-	err = uc.UserService.CreateUser(&user)
+	actor, _ := r.Context().Value("role").(models.Role)
+
+	err = uc.UserService.CreateUser(r.Context(), &user, actor)
 	if err != nil {
 		if validationErr, ok := err.(helpers.ValidationErrors); ok {
 			helpers.BadRequestResponse(w, "Gagal membuat user", validationErr.Messages)
 			return
 		}
+		if errors.Is(err, services.ErrRoleForbidden) {
+			helpers.ForbiddenResponse(w, "Hanya admin yang dapat memberikan role admin atau staff")
+			return
+		}
 		if errors.Is(err, services.ErrUsernameTaken) {
 			helpers.BadRequestResponse(w, "Username sudah digunakan", err)
 			return
 		}
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal membuat user", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal membuat user", err)
 		return
 	}
 
 	result := userResponse{
 		UserId: user.UserId,
 		Username: user.Username,
+		Role: user.Role,
 		CreatedAt: user.CreatedAt,
 	}
 
@@ -90,19 +97,20 @@ func (uc *UserController) CreateUser(w http.ResponseWriter, r *http.Request, ps
 func (uc *UserController) GetUserByUsername(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	username := ps.ByName("username")
 
-	user, err := uc.UserService.GetUserByUsername(username)
+	user, err := uc.UserService.GetUserByUsername(r.Context(), username)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			helpers.NotFoundResponse(w, "User tidak ditemukan")
 			return
 		}
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal mendapatkan data user", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal mendapatkan data user", err)
 		return
 	}
 
 	result := userResponse{
 		UserId: user.UserId,
 		Username: user.Username,
+		Role: user.Role,
 		CreatedAt: user.CreatedAt,
 	}
 
@@ -120,17 +128,27 @@ func (uc *UserController) UpdateUser(w http.ResponseWriter, r *http.Request, ps
 		return
 	}
 
-	err = uc.UserService.UpdateUser(username, &user)
+	actor, _ := r.Context().Value("role").(models.Role)
+
+	err = uc.UserService.UpdateUser(r.Context(), username, &user, actor)
 	if err != nil {
 		if validationErr, ok := err.(helpers.ValidationErrors); ok {
 			helpers.BadRequestResponse(w, "Gagal memperbarui user", validationErr.Messages)
 			return
 		}
+		if errors.Is(err, services.ErrRoleForbidden) {
+			helpers.ForbiddenResponse(w, "Hanya admin yang dapat mengubah role maupun akun admin dan staff")
+			return
+		}
+		if errors.Is(err, services.ErrUsernameTaken) {
+			helpers.BadRequestResponse(w, "Username sudah digunakan", err)
+			return
+		}
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			helpers.NotFoundResponse(w, "User tidak ditemukan")
 			return
 		}
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal memperbarui user", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal memperbarui user", err)
 		return
 	}
 
@@ -141,16 +159,123 @@ func (uc *UserController) UpdateUser(w http.ResponseWriter, r *http.Request, ps
 func (uc *UserController) DeleteUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	username := ps.ByName("username")
 
-	err := uc.UserService.DeleteUser(username)
+	actor, _ := r.Context().Value("role").(models.Role)
+
+	err := uc.UserService.DeleteUser(r.Context(), username, actor)
 	if err != nil {
+		if errors.Is(err, services.ErrRoleForbidden) {
+			helpers.ForbiddenResponse(w, "Hanya admin yang dapat menghapus akun admin dan staff")
+			return
+		}
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			helpers.NotFoundResponse(w, "User tidak ditemukan")
 			return
 		}
-		helpers.ErrorResponse(w, http.StatusInternalServerError, "Gagal menghapus user", err.Error())
+		helpers.ServerErrorResponse(w, "Gagal menghapus user", err)
 		return
 	}
 
 	helpers.SuccessResponse(w, http.StatusOK, "Berhasil menghapus user", nil)
 	return
-}
\ No newline at end of file
+}
+func (uc *UserController) UnlockUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	username := ps.ByName("username")
+
+	actor, _ := r.Context().Value("role").(models.Role)
+
+	err := uc.UserService.UnlockUser(r.Context(), username, actor)
+	if err != nil {
+		if errors.Is(err, services.ErrRoleForbidden) {
+			helpers.ForbiddenResponse(w, "Hanya admin yang dapat membuka kunci akun admin dan staff")
+			return
+		}
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			helpers.NotFoundResponse(w, "User tidak ditemukan")
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal membuka kunci user", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil membuka kunci user", nil)
+	return
+}
+
+func (uc *UserController) ChangePassword(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var request models.ChangePasswordRequest
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helpers.BadRequestResponse(w, "Gagal memproses input", err)
+		return
+	}
+
+	username, _ := r.Context().Value("username").(string)
+
+	err = uc.UserService.ChangePassword(r.Context(), username, &request)
+	if err != nil {
+		if validationErr, ok := err.(helpers.ValidationErrors); ok {
+			helpers.ValidationErrorResponse(w, "Validasi gagal", validationErr.Messages)
+			return
+		}
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			helpers.NotFoundResponse(w, "User tidak ditemukan")
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal mengganti password", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mengganti password, silakan login kembali", nil)
+	return
+}
+
+func (uc *UserController) IssuePasswordReset(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	username := ps.ByName("username")
+
+	actor, _ := r.Context().Value("role").(models.Role)
+
+	result, err := uc.UserService.IssuePasswordReset(r.Context(), username, actor)
+	if err != nil {
+		if errors.Is(err, services.ErrRoleForbidden) {
+			helpers.ForbiddenResponse(w, "Hanya admin yang dapat mereset password akun admin dan staff")
+			return
+		}
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			helpers.NotFoundResponse(w, "User tidak ditemukan")
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal membuat token reset password", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusCreated, "Berhasil membuat token reset password", result)
+	return
+}
+
+func (uc *UserController) ResetPassword(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var request models.ResetPasswordRequest
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		helpers.BadRequestResponse(w, "Gagal memproses input", err)
+		return
+	}
+
+	err = uc.UserService.ResetPasswordWithToken(r.Context(), &request)
+	if err != nil {
+		if validationErr, ok := err.(helpers.ValidationErrors); ok {
+			helpers.ValidationErrorResponse(w, "Validasi gagal", validationErr.Messages)
+			return
+		}
+		if errors.Is(err, repositories.ErrInvalidResetToken) {
+			helpers.BadRequestResponse(w, "Token reset password tidak valid atau sudah kedaluwarsa", nil)
+			return
+		}
+		helpers.ServerErrorResponse(w, "Gagal mereset password", err)
+		return
+	}
+
+	helpers.SuccessResponse(w, http.StatusOK, "Berhasil mereset password, silakan login kembali", nil)
+	return
+}
@@ -0,0 +1,135 @@
+package gateways
+
+import (
+	"contact-management/src/config"
+	"contact-management/src/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// FakeGateway adalah provider in-process untuk development dan test.
+// Invoice hanya disimpan di memory dan statusnya diubah lewat SetStatus.
+type FakeGateway struct {
+	mu       sync.Mutex
+	invoices map[string]*Invoice
+	duration time.Duration
+	secret   string
+}
+
+func NewFakeGateway(cfg config.PaymentConfig) *FakeGateway {
+	return &FakeGateway{
+		invoices: make(map[string]*Invoice),
+		duration: cfg.InvoiceDuration,
+		secret:   cfg.CallbackToken,
+	}
+}
+
+func (f *FakeGateway) Name() string {
+	return "fake"
+}
+
+func (f *FakeGateway) CreateInvoice(ctx context.Context, req CreateInvoiceRequest) (*Invoice, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	invoice := &Invoice{
+		ExternalID: req.ExternalID,
+		Status:     models.PaymentStatusPending,
+		PaymentURL: "https://fake-payment.local/invoices/" + req.ExternalID,
+		ExpiresAt:  time.Now().Add(req.Duration(f.duration)),
+	}
+	f.invoices[req.ExternalID] = invoice
+
+	result := *invoice
+	return &result, nil
+}
+
+func (f *FakeGateway) GetInvoiceStatus(ctx context.Context, externalID string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	invoice, ok := f.invoices[externalID]
+	if !ok {
+		return "", ErrInvoiceNotFound
+	}
+	return invoice.Status, nil
+}
+
+// GetInvoice mengembalikan salinan invoice, dipakai test untuk memeriksa masa berlakunya.
+func (f *FakeGateway) GetInvoice(externalID string) (*Invoice, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	invoice, ok := f.invoices[externalID]
+	if !ok {
+		return nil, ErrInvoiceNotFound
+	}
+	result := *invoice
+	return &result, nil
+}
+
+func (f *FakeGateway) CancelInvoice(ctx context.Context, externalID string) error {
+	return f.SetStatus(externalID, models.PaymentStatusCancelled)
+}
+
+// SetStatus mensimulasikan perubahan status invoice di sisi provider.
+func (f *FakeGateway) SetStatus(externalID, status string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	invoice, ok := f.invoices[externalID]
+	if !ok {
+		return ErrInvoiceNotFound
+	}
+	invoice.Status = status
+	return nil
+}
+
+type fakeWebhookPayload struct {
+	EventID    string `json:"event_id"`
+	ExternalID string `json:"external_id"`
+	Status     string `json:"status"`
+}
+
+// ParseWebhook memverifikasi header X-Signature berisi HMAC-SHA256 (hex) dari body
+// dengan PAYMENT_CALLBACK_TOKEN sebagai key. Tanpa key semua webhook ditolak.
+func (f *FakeGateway) ParseWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	signature, err := hex.DecodeString(header.Get("X-Signature"))
+	if err != nil || f.secret == "" || !hmac.Equal(signature, f.sign(body)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var payload fakeWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil || payload.EventID == "" || payload.ExternalID == "" {
+		return nil, ErrInvalidWebhookPayload
+	}
+
+	return &WebhookEvent{
+		EventID:    payload.EventID,
+		ExternalID: payload.ExternalID,
+		Status:     payload.Status,
+	}, nil
+}
+
+// BuildWebhook membuat body dan signature callback seperti yang akan dikirim provider,
+// berguna untuk mensimulasikan pembayaran di development dan test.
+func (f *FakeGateway) BuildWebhook(eventID, externalID, status string) ([]byte, string) {
+	body, _ := json.Marshal(fakeWebhookPayload{
+		EventID:    eventID,
+		ExternalID: externalID,
+		Status:     status,
+	})
+	return body, hex.EncodeToString(f.sign(body))
+}
+
+func (f *FakeGateway) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(f.secret))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,105 @@
+package gateways
+
+import (
+	"contact-management/src/config"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var ErrInvoiceNotFound = errors.New("invoice tidak ditemukan di payment gateway")
+
+var ErrUnknownProvider = errors.New("payment provider tidak dikenal")
+
+var ErrMissingCallbackToken = errors.New("PAYMENT_CALLBACK_TOKEN wajib diisi")
+
+var ErrFakeProviderNotAllowed = errors.New("payment provider fake hanya boleh dipakai di APP_ENV development atau test")
+
+var ErrInvalidSignature = errors.New("signature webhook tidak valid")
+
+var ErrInvalidWebhookPayload = errors.New("payload webhook tidak valid")
+
+// GatewayError membungkus kegagalan berkomunikasi dengan payment provider agar bisa
+// dibedakan dari kegagalan database.
+type GatewayError struct {
+	Provider string
+	Err      error
+}
+
+func (e *GatewayError) Error() string {
+	return fmt.Sprintf("payment gateway %s: %v", e.Provider, e.Err)
+}
+
+func (e *GatewayError) Unwrap() error {
+	return e.Err
+}
+
+type CreateInvoiceRequest struct {
+	ExternalID  string
+	Amount      int
+	Name        string
+	Email       string
+	Phone       string
+	Method      string
+	Description string
+	// MaxDuration membatasi masa berlaku invoice, misalnya sisa reservasi order.
+	// Nol berarti memakai PAYMENT_INVOICE_DURATION apa adanya.
+	MaxDuration time.Duration
+}
+
+// Duration mengembalikan masa berlaku invoice: configured, atau MaxDuration bila lebih pendek.
+func (r CreateInvoiceRequest) Duration(configured time.Duration) time.Duration {
+	if r.MaxDuration > 0 && r.MaxDuration < configured {
+		return r.MaxDuration
+	}
+	return configured
+}
+
+// WebhookEvent adalah hasil parsing callback provider yang signature-nya sudah diverifikasi.
+// EventID dipakai untuk memastikan satu callback tidak diproses dua kali.
+type WebhookEvent struct {
+	EventID    string
+	ExternalID string
+	Status     string
+}
+
+type Invoice struct {
+	ExternalID string
+	Status     string
+	PaymentURL string
+	ExpiresAt  time.Time
+}
+
+// PaymentGateway adalah kontrak untuk provider hosted invoice
+// (Xendit, Midtrans, atau fake provider untuk development).
+// Status yang dikembalikan selalu memakai konstanta models.PaymentStatus*.
+type PaymentGateway interface {
+	Name() string
+	CreateInvoice(ctx context.Context, req CreateInvoiceRequest) (*Invoice, error)
+	GetInvoiceStatus(ctx context.Context, externalID string) (string, error)
+	CancelInvoice(ctx context.Context, externalID string) error
+	ParseWebhook(header http.Header, body []byte) (*WebhookEvent, error)
+}
+
+// NewPaymentGateway memilih provider sesuai PAYMENT_PROVIDER. Tanpa callback token siapa
+// pun bisa memalsukan webhook, jadi provider tidak dibuat bila token kosong. Provider fake
+// menerima webhook buatan sendiri sehingga hanya boleh dipakai di development dan test.
+func NewPaymentGateway(cfg config.PaymentConfig, env string) (PaymentGateway, error) {
+	if cfg.CallbackToken == "" {
+		return nil, ErrMissingCallbackToken
+	}
+
+	switch cfg.Provider {
+	case "fake":
+		if env != "development" && env != "test" {
+			return nil, fmt.Errorf("%w: %s", ErrFakeProviderNotAllowed, env)
+		}
+		return NewFakeGateway(cfg), nil
+	case "xendit":
+		return NewXenditGateway(cfg), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, cfg.Provider)
+	}
+}
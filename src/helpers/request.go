@@ -0,0 +1,119 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RequestIDHeader dipakai untuk menerima request id dari klien atau proxy
+// dan mengembalikannya di response.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID mengambil request id yang disimpan middleware RequestLogger di context.
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value("request_id").(string)
+	return id
+}
+
+// RequestIDHook menambahkan field request_id ke setiap log yang ditulis dengan
+// logger.WithContext(ctx), sehingga service tidak perlu menambahkannya sendiri.
+type RequestIDHook struct{}
+
+func (RequestIDHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (RequestIDHook) Fire(entry *logrus.Entry) error {
+	if id := RequestID(entry.Context); id != "" {
+		entry.Data["request_id"] = id
+	}
+	return nil
+}
+
+// ClientIP mengambil IP klien yang sudah ditentukan ClientIPResolver di middleware
+// RequestLogger. Di luar middleware tersebut alamat koneksi yang dipakai, tanpa
+// mempercayai X-Forwarded-For.
+func ClientIP(r *http.Request) string {
+	if ip, _ := r.Context().Value("ip").(string); ip != "" {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ClientIPResolver menentukan IP klien. X-Forwarded-For hanya dibaca bila koneksi datang
+// dari proxy terpercaya, dan dibaca dari kanan: hop pertama yang bukan proxy terpercaya
+// adalah IP klien, karena entri di sebelah kirinya bisa diisi sendiri oleh klien.
+type ClientIPResolver struct {
+	trusted []*net.IPNet
+}
+
+// NewClientIPResolver menerima daftar IP atau CIDR proxy terpercaya.
+func NewClientIPResolver(proxies []string) (*ClientIPResolver, error) {
+	resolver := &ClientIPResolver{}
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("proxy %q bukan IP atau CIDR", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxy = fmt.Sprintf("%s/%d", ip, bits)
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("proxy %q bukan IP atau CIDR", proxy)
+		}
+		resolver.trusted = append(resolver.trusted, network)
+	}
+	return resolver, nil
+}
+
+func (c *ClientIPResolver) isTrusted(ip net.IP) bool {
+	for _, network := range c.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve mengembalikan IP klien request r.
+func (c *ClientIPResolver) Resolve(r *http.Request) string {
+	client := remoteIP(r)
+	if ip := net.ParseIP(client); ip == nil || !c.isTrusted(ip) {
+		return client
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(hops[i]))
+		if ip == nil {
+			// hop yang tidak valid tidak bisa dipercaya; berhenti di hop terakhir yang sah
+			break
+		}
+		client = ip.String()
+		if !c.isTrusted(ip) {
+			break
+		}
+	}
+	return client
+}
@@ -1,13 +1,22 @@
 package helpers
 
 import (
-	"contact-management/src/apps"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/sirupsen/logrus"
 )
 
+var logger = logrus.StandardLogger()
+
+// SetLogger mengganti logger yang dipakai helpers dengan logger aplikasi.
+// Dipanggil sekali saat startup sebelum server menerima request.
+func SetLogger(l *logrus.Logger) {
+	logger = l
+}
+
 type Response struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
@@ -37,14 +46,38 @@ func ErrorResponse(w http.ResponseWriter, statusCode int, message string, err an
 		Error:   err,
 	}
 
-	apps.LoggingApp().WithFields(logrus.Fields{
-		"status" : statusCode,
-		"message" : message,
-	}).Info("Success response")
+	entry := logger.WithFields(logrus.Fields{
+		"status":  statusCode,
+		"message": message,
+		"error":   err,
+	})
+	// Middleware RequestLogger sudah memasang header ini sebelum handler berjalan.
+	if id := w.Header().Get(RequestIDHeader); id != "" {
+		entry = entry.WithField("request_id", id)
+	}
+	if statusCode >= http.StatusInternalServerError {
+		entry.Error("Error response")
+	} else {
+		entry.Info("Error response")
+	}
 
 	json.NewEncoder(w).Encode(response)
 }
 
+// ServerErrorResponse menulis error yang bukan kesalahan client. Operasi yang melewati
+// batas waktunya dijawab 504 dan request yang dibatalkan (client putus atau server
+// sedang shutdown) dijawab 503, selain itu 500.
+func ServerErrorResponse(w http.ResponseWriter, message string, err error) {
+	statusCode := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		statusCode = http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		statusCode = http.StatusServiceUnavailable
+	}
+	ErrorResponse(w, statusCode, message, err.Error())
+}
+
 func UnauthorizedResponse(w http.ResponseWriter, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusUnauthorized)
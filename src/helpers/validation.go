@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"strings"
+	"sync"
 
 	"github.com/go-playground/locales/id"
 	ut "github.com/go-playground/universal-translator"
@@ -10,13 +11,25 @@ import (
 )
 
 var (
-	uni      *ut.UniversalTranslator
-	trans    ut.Translator
-	validate *validator.Validate
+	uni           *ut.UniversalTranslator
+	trans         ut.Translator
+	validate      *validator.Validate
+	validatorOnce sync.Once
 )
 
+// Validator mengembalikan validator bersama yang hanya dibangun sekali, sehingga aman
+// dipakai dari banyak request sekaligus.
+func Validator() *validator.Validate {
+	validatorOnce.Do(buildValidator)
+	return validate
+}
+
 // InitValidator inisialisasi validator dengan bahasa Indonesia
 func InitValidator() *validator.Validate {
+	return Validator()
+}
+
+func buildValidator() {
 	// Setup translator bahasa Indonesia
 	indonesian := id.New()
 	uni = ut.New(indonesian, indonesian)
@@ -56,8 +69,6 @@ func InitValidator() *validator.Validate {
 		t, _ := ut.T("email", fe.Field())
 		return t
 	})
-
-	return validate
 }
 
 func FormatValidationError(err error) map[string]string {
@@ -9,28 +9,38 @@ import (
 	"github.com/julienschmidt/httprouter"
 )
 
-func AuthMiddleware(next httprouter.Handle) httprouter.Handle {
-	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+// AuthMiddleware membuat wrapper yang memverifikasi bearer token dengan tokenService
+// lalu menyimpan username, token mentah, id sesi, role dan permission-nya di context request.
+func AuthMiddleware(tokenService *utils.TokenService) func(httprouter.Handle) httprouter.Handle {
+	return func(next httprouter.Handle) httprouter.Handle {
+		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			helpers.UnauthorizedResponse(w, "Unauthorized")
-			return
-		}
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				helpers.UnauthorizedResponse(w, "Unauthorized")
+				return
+			}
 
-		token := authHeader
-		if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-			token = authHeader[7:]
-		}
+			token := authHeader
+			if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
+				token = authHeader[7:]
+			}
 
-		username, err := utils.VerifyToken(token)
-		if err != nil {
-			helpers.UnauthorizedResponse(w, "Unauthorized")
-			return
-		}
+			claims, err := tokenService.VerifyToken(r.Context(), token)
+			if err != nil {
+				helpers.UnauthorizedResponse(w, "Unauthorized")
+				return
+			}
 
-		ctx := context.WithValue(r.Context(), "username", username)
-		r = r.WithContext(ctx)
-		next(w, r, ps)
+			setAccessLogUsername(r, claims.Username)
+
+			ctx := context.WithValue(r.Context(), "username", claims.Username)
+			ctx = context.WithValue(ctx, "token", token)
+			ctx = context.WithValue(ctx, "session_id", claims.ID)
+			ctx = context.WithValue(ctx, "role", claims.Role)
+			ctx = context.WithValue(ctx, "permissions", claims.Role.Permissions())
+			r = r.WithContext(ctx)
+			next(w, r, ps)
+		}
 	}
 }
@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"contact-management/src/helpers"
+	"contact-management/src/models"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// RequirePermission membuat wrapper yang hanya meneruskan request bila role di context
+// memiliki permission tersebut. Wrapper ini dipasang di dalam AuthMiddleware:
+//
+//	authMiddleware(middlewares.RequirePermission(models.PermissionCatalogWrite)(handler))
+func RequirePermission(permission models.Permission) func(httprouter.Handle) httprouter.Handle {
+	return func(next httprouter.Handle) httprouter.Handle {
+		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+			if !HasPermission(r, permission) {
+				helpers.ForbiddenResponse(w, "Anda tidak memiliki akses untuk melakukan aksi ini")
+				return
+			}
+			next(w, r, ps)
+		}
+	}
+}
+
+// HasPermission memeriksa permission yang disimpan AuthMiddleware di context request.
+func HasPermission(r *http.Request, permission models.Permission) bool {
+	permissions, _ := r.Context().Value("permissions").([]models.Permission)
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package middlewares
+
+import (
+	"bytes"
+	"contact-management/src/config"
+	"contact-management/src/helpers"
+	"contact-management/src/utils"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/sirupsen/logrus"
+)
+
+// rateLimitBodyLimit membatasi bagian body yang dibaca untuk mencari username.
+const rateLimitBodyLimit = 64 << 10
+
+type rateLimitCheck struct {
+	key  string
+	rule config.RateLimitRule
+}
+
+// RateLimit membuat wrapper yang membatasi request ke satu grup route per IP klien dan
+// per username sesuai rules. Username diambil dari context AuthMiddleware, atau dari
+// field username di body JSON untuk route seperti /login. Response selalu membawa header
+// RateLimit-Limit, RateLimit-Remaining dan RateLimit-Reset untuk aturan yang paling
+// ketat; request yang ditolak dijawab 429 dengan Retry-After. limiter nil berarti rate
+// limit nonaktif. Bila limiter gagal, request tetap diteruskan.
+func RateLimit(limiter utils.RateLimiter, logger *logrus.Logger, group string, rules config.RateLimitGroup) func(httprouter.Handle) httprouter.Handle {
+	return func(next httprouter.Handle) httprouter.Handle {
+		if limiter == nil {
+			return next
+		}
+		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+			var checks []rateLimitCheck
+			if rules.IP.Limit > 0 {
+				checks = append(checks, rateLimitCheck{key: group + ":ip:" + helpers.ClientIP(r), rule: rules.IP})
+			}
+			if rules.Username.Limit > 0 {
+				if username := rateLimitUsername(r); username != "" {
+					checks = append(checks, rateLimitCheck{key: group + ":user:" + username, rule: rules.Username})
+				}
+			}
+
+			var tightest *utils.RateLimitResult
+			for _, check := range checks {
+				result, err := limiter.Allow(r.Context(), check.key, check.rule.Limit, check.rule.Window)
+				if err != nil {
+					logger.WithContext(r.Context()).Error("Gagal memeriksa rate limit: ", err)
+					continue
+				}
+				if tightest == nil || !result.Allowed || result.Remaining < tightest.Remaining {
+					tightest = &result
+				}
+				if !result.Allowed {
+					break
+				}
+			}
+
+			if tightest != nil {
+				w.Header().Set("RateLimit-Limit", strconv.Itoa(tightest.Limit))
+				w.Header().Set("RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+				w.Header().Set("RateLimit-Reset", ceilSeconds(tightest.Reset))
+				if !tightest.Allowed {
+					w.Header().Set("Retry-After", ceilSeconds(tightest.Reset))
+					helpers.TooManyRequestsResponse(w, "Terlalu banyak permintaan, silakan coba lagi nanti")
+					return
+				}
+			}
+
+			next(w, r, ps)
+		}
+	}
+}
+
+// rateLimitUsername mengambil username dari context AuthMiddleware atau dari body JSON.
+// Body dikembalikan utuh sehingga handler tetap bisa membacanya.
+func rateLimitUsername(r *http.Request) string {
+	if username, ok := r.Context().Value("username").(string); ok {
+		return username
+	}
+	if r.Body == nil {
+		return ""
+	}
+
+	prefix, err := io.ReadAll(io.LimitReader(r.Body, rateLimitBodyLimit))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), r.Body), r.Body}
+	if err != nil {
+		return ""
+	}
+
+	var body struct {
+		Username string `json:"username"`
+	}
+	if json.Unmarshal(prefix, &body) != nil {
+		return ""
+	}
+	return strings.TrimSpace(body.Username)
+}
+
+// ceilSeconds membulatkan d ke atas dalam detik, minimal 1, untuk header yang
+// memakai satuan detik.
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(max(1, int(math.Ceil(d.Seconds()))))
+}
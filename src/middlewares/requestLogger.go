@@ -0,0 +1,119 @@
+package middlewares
+
+import (
+	"contact-management/src/helpers"
+	"contact-management/src/utils"
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/sirupsen/logrus"
+)
+
+// validRequestID membatasi request id dari klien agar aman ditulis ke log dan header.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// accessLog diisi oleh handler di bawah RequestLogger. AuthMiddleware membuat request
+// baru dengan context turunan, jadi username dicatat lewat pointer ini.
+type accessLog struct {
+	username string
+}
+
+// statusRecorder mencatat status dan jumlah byte yang ditulis handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += n
+	return n, err
+}
+
+// RequestLogger membungkus router: menerima X-Request-ID dari klien atau membuat yang
+// baru, menyimpannya di context dan header response, lalu menulis satu baris access log
+// per request berisi method, pola route, status, bytes, latency dan username. IP klien
+// dari resolver juga disimpan di context untuk rate limit, login dan audit log.
+func RequestLogger(logger *logrus.Logger, resolver *helpers.ClientIPResolver, router *httprouter.Router) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(helpers.RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			generated, err := utils.RandomHex(16)
+			if err != nil {
+				logger.Error("Failed to generate request id: ", err)
+			}
+			requestID = generated
+		}
+		w.Header().Set(helpers.RequestIDHeader, requestID)
+
+		entry := &accessLog{}
+		ctx := context.WithValue(r.Context(), "request_id", requestID)
+		ctx = context.WithValue(ctx, "ip", resolver.Resolve(r))
+		ctx = context.WithValue(ctx, "access_log", entry)
+		r = r.WithContext(ctx)
+
+		recorder := &statusRecorder{ResponseWriter: w}
+		router.ServeHTTP(recorder, r)
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+
+		logger.WithContext(ctx).WithFields(logrus.Fields{
+			"method":     r.Method,
+			"route":      routePattern(router, r),
+			"status":     recorder.status,
+			"bytes":      recorder.bytes,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"username":   entry.username,
+			"ip":         helpers.ClientIP(r),
+		}).Info("HTTP request")
+	})
+}
+
+// setAccessLogUsername mencatat username yang sudah terautentikasi untuk access log.
+func setAccessLogUsername(r *http.Request, username string) {
+	if entry, ok := r.Context().Value("access_log").(*accessLog); ok {
+		entry.username = username
+	}
+}
+
+// routePattern menyusun ulang pola route (mis. /orders/:id/status) dari parameter hasil
+// Lookup, supaya access log bisa dikelompokkan per route, bukan per id. Parameter dicocokkan
+// dari segmen paling kanan karena di router ini setiap parameter mengisi satu segmen penuh.
+func routePattern(router *httprouter.Router, r *http.Request) string {
+	handle, params, _ := router.Lookup(r.Method, r.URL.Path)
+	if handle == nil {
+		return "-"
+	}
+
+	segments := strings.Split(r.URL.Path, "/")
+	last := len(segments)
+	for i := len(params) - 1; i >= 0; i-- {
+		for j := last - 1; j >= 0; j-- {
+			if segments[j] == params[i].Value {
+				segments[j] = ":" + params[i].Key
+				last = j
+				break
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,10 @@
+CREATE TABLE payment_webhook_events (
+    webhook_event_id INT PRIMARY KEY AUTO_INCREMENT,
+    provider VARCHAR(50) NOT NULL,
+    event_id VARCHAR(191) NOT NULL,
+    payment_id INT,
+    FOREIGN KEY (payment_id) REFERENCES payments (payment_id),
+    status VARCHAR(50) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_payment_webhook_events_provider_event (provider, event_id)
+);
\ No newline at end of file
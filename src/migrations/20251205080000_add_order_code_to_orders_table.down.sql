@@ -0,0 +1 @@
+ALTER TABLE orders DROP COLUMN order_code;
\ No newline at end of file
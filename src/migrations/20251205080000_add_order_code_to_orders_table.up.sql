@@ -0,0 +1 @@
+ALTER TABLE orders ADD COLUMN order_code VARCHAR(32) NULL UNIQUE AFTER order_id;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE product_credentials (
+    credential_id INT PRIMARY KEY AUTO_INCREMENT,
+    product_id INT NOT NULL,
+    FOREIGN KEY (product_id) REFERENCES products (product_id),
+    secret_ciphertext TEXT NOT NULL,
+    order_id INT DEFAULT NULL UNIQUE,
+    FOREIGN KEY (order_id) REFERENCES orders (order_id),
+    assigned_at DATETIME DEFAULT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at DATETIME DEFAULT NULL,
+    INDEX idx_product_credentials_available (product_id, order_id)
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE audit_logs;
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE audit_logs (
+    audit_log_id INT PRIMARY KEY AUTO_INCREMENT,
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    before_data TEXT DEFAULT NULL,
+    after_data TEXT DEFAULT NULL,
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    INDEX idx_audit_logs_entity (entity_type, entity_id),
+    INDEX idx_audit_logs_actor (actor),
+    INDEX idx_audit_logs_created_at (created_at)
+);
\ No newline at end of file
@@ -0,0 +1,29 @@
+// Package migrations menyimpan file SQL skema database. File ikut ter-embed ke binary
+// sehingga migrasi bisa dijalankan tanpa CLI migrate eksternal.
+package migrations
+
+import (
+	"contact-management/src/config"
+	"embed"
+	"io/fs"
+)
+
+// FS berisi pasangan file <version>_<name>.up.sql dan <version>_<name>.down.sql untuk MySQL.
+//
+//go:embed *.sql
+var FS embed.FS
+
+// sqliteFS berisi skema yang sama untuk SQLite dengan version dan nama yang sama,
+// sehingga catatan schema_migrations kedua driver bisa dibandingkan langsung.
+//
+//go:embed sqlite/*.sql
+var sqliteFS embed.FS
+
+// ForDriver mengembalikan file migrasi untuk DB_DRIVER tertentu.
+func ForDriver(driver string) fs.FS {
+	if driver == config.DriverSQLite {
+		sub, _ := fs.Sub(sqliteFS, "sqlite")
+		return sub
+	}
+	return FS
+}
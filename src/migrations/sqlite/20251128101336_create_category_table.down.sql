@@ -0,0 +1 @@
+DROP TABLE category;
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE category (
+    category_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME DEFAULT NULL
+);
+CREATE TRIGGER trg_category_updated_at AFTER UPDATE ON category FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at BEGIN UPDATE category SET updated_at = CURRENT_TIMESTAMP WHERE category_id = NEW.category_id; END;
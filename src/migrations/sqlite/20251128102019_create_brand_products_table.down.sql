@@ -0,0 +1 @@
+DROP TABLE brand_products;
\ No newline at end of file
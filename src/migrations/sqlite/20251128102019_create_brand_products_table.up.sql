@@ -0,0 +1,9 @@
+CREATE TABLE brand_products (
+    brand_product_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    category_id INT REFERENCES category (category_id),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME DEFAULT NULL
+);
+CREATE TRIGGER trg_brand_products_updated_at AFTER UPDATE ON brand_products FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at BEGIN UPDATE brand_products SET updated_at = CURRENT_TIMESTAMP WHERE brand_product_id = NEW.brand_product_id; END;
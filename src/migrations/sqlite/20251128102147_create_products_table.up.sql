@@ -0,0 +1,13 @@
+CREATE TABLE products (
+    product_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    brand_product_id INT REFERENCES brand_products (brand_product_id),
+    price INT,
+    description TEXT,
+    duration TEXT,
+    stock INT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME DEFAULT NULL
+);
+CREATE TRIGGER trg_products_updated_at AFTER UPDATE ON products FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at BEGIN UPDATE products SET updated_at = CURRENT_TIMESTAMP WHERE product_id = NEW.product_id; END;
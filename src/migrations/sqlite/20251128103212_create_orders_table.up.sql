@@ -0,0 +1,13 @@
+CREATE TABLE orders (
+    order_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INT REFERENCES products (product_id),
+    name VARCHAR(100) NOT NULL,
+    email VARCHAR(100) NOT NULL,
+    phone VARCHAR(15),
+    method VARCHAR(50) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME DEFAULT NULL
+);
+CREATE TRIGGER trg_orders_updated_at AFTER UPDATE ON orders FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at BEGIN UPDATE orders SET updated_at = CURRENT_TIMESTAMP WHERE order_id = NEW.order_id; END;
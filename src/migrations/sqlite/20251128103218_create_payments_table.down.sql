@@ -0,0 +1 @@
+DROP TABLE payments;
\ No newline at end of file
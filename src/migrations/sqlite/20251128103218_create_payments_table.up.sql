@@ -0,0 +1,17 @@
+CREATE TABLE payments (
+    payment_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INT REFERENCES products (product_id),
+    order_id INT REFERENCES orders (order_id),
+    amount INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    email VARCHAR(100) NOT NULL,
+    phone VARCHAR(15),
+    method VARCHAR(50) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    external_id VARCHAR(100) NOT NULL,
+    payment_url TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME DEFAULT NULL
+);
+CREATE TRIGGER trg_payments_updated_at AFTER UPDATE ON payments FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at BEGIN UPDATE payments SET updated_at = CURRENT_TIMESTAMP WHERE payment_id = NEW.payment_id; END;
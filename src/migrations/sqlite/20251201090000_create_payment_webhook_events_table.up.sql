@@ -0,0 +1,9 @@
+CREATE TABLE payment_webhook_events (
+    webhook_event_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    provider VARCHAR(50) NOT NULL,
+    event_id VARCHAR(191) NOT NULL,
+    payment_id INT REFERENCES payments (payment_id),
+    status VARCHAR(50) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_payment_webhook_events_provider_event UNIQUE (provider, event_id)
+);
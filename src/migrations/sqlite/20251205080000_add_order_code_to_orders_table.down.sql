@@ -0,0 +1,2 @@
+DROP INDEX uq_orders_order_code;
+ALTER TABLE orders DROP COLUMN order_code;
@@ -0,0 +1,2 @@
+ALTER TABLE orders ADD COLUMN order_code VARCHAR(32) NULL;
+CREATE UNIQUE INDEX uq_orders_order_code ON orders (order_code);
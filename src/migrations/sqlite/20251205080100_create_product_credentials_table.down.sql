@@ -0,0 +1 @@
+DROP TABLE product_credentials;
\ No newline at end of file
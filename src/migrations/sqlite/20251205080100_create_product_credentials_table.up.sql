@@ -0,0 +1,12 @@
+CREATE TABLE product_credentials (
+    credential_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INT NOT NULL REFERENCES products (product_id),
+    secret_ciphertext TEXT NOT NULL,
+    order_id INT DEFAULT NULL UNIQUE REFERENCES orders (order_id),
+    assigned_at DATETIME DEFAULT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME DEFAULT NULL
+);
+CREATE INDEX idx_product_credentials_available ON product_credentials (product_id, order_id);
+CREATE TRIGGER trg_product_credentials_updated_at AFTER UPDATE ON product_credentials FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at BEGIN UPDATE product_credentials SET updated_at = CURRENT_TIMESTAMP WHERE credential_id = NEW.credential_id; END;
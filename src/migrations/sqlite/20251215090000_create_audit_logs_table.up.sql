@@ -0,0 +1,15 @@
+CREATE TABLE audit_logs (
+    audit_log_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    before_data TEXT DEFAULT NULL,
+    after_data TEXT DEFAULT NULL,
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL
+);
+CREATE INDEX idx_audit_logs_entity ON audit_logs (entity_type, entity_id);
+CREATE INDEX idx_audit_logs_actor ON audit_logs (actor);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs (created_at);
\ No newline at end of file
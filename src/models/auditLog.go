@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Aksi yang dicatat di audit log.
+const (
+	AuditActionCreate         = "create"
+	AuditActionUpdate         = "update"
+	AuditActionDelete         = "delete"
+	AuditActionUnlock         = "unlock"
+	AuditActionPasswordReset  = "password_reset"
+	AuditActionPasswordChange = "password_change"
+	AuditActionResetIssued    = "password_reset_issued"
+	AuditActionLoginSucceeded = "login_succeeded"
+	AuditActionLoginFailed    = "login_failed"
+	AuditActionLoginLocked    = "login_locked"
+)
+
+// Jenis entitas yang dicatat di audit log.
+const (
+	AuditEntityUser         = "user"
+	AuditEntityCategory     = "category"
+	AuditEntityBrandProduct = "brand_product"
+	AuditEntityProduct      = "product"
+)
+
+// AuditLog adalah satu perubahan yang tercatat: siapa melakukan apa terhadap entitas
+// mana, beserta isi entitas sebelum dan sesudahnya dalam JSON.
+type AuditLog struct {
+	AuditLogID int             `json:"audit_log_id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	IP         string          `json:"ip"`
+	RequestID  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditLogFilter menyaring GET /audit-logs. Field kosong berarti tidak disaring.
+type AuditLogFilter struct {
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	Limit      int
+}
+
+type AuditLogResponsePagination struct {
+	AuditLogs []AuditLog `json:"audit_logs"`
+	Total     int        `json:"total"`
+	Page      int        `json:"page"`
+	Limit     int        `json:"limit"`
+}
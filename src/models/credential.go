@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type Credential struct {
+	CredentialID int        `json:"credential_id"`
+	ProductID    int        `json:"product_id"`
+	OrderID      *int       `json:"order_id,omitempty"`
+	Ciphertext   string     `json:"-"`
+	Secret       string     `json:"secret,omitempty"`
+	AssignedAt   *time.Time `json:"assigned_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type CredentialUploadRequest struct {
+	Credentials []string `json:"credentials" validate:"required,min=1,dive,required,max=1000"`
+}
+
+type CredentialUploadResponse struct {
+	ProductID int `json:"product_id"`
+	Uploaded  int `json:"uploaded"`
+	Available int `json:"available"`
+}
+
+type CredentialLookupRequest struct {
+	OrderCode string `json:"order_code" validate:"required"`
+	Email     string `json:"email" validate:"required,email"`
+}
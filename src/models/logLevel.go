@@ -0,0 +1,9 @@
+package models
+
+type LogLevelRequest struct {
+	Level string `json:"level" validate:"required,oneof=panic fatal error warn warning info debug trace"`
+}
+
+type LogLevelResponse struct {
+	Level string `json:"level"`
+}
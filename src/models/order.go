@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusPaid      = "paid"
+	OrderStatusFulfilled = "fulfilled"
+	OrderStatusExpired   = "expired"
+	OrderStatusCancelled = "cancelled"
+	OrderStatusRefunded  = "refunded"
+)
+
+type Order struct {
+	OrderID   int        `json:"order_id"`
+	OrderCode string     `json:"order_code"`
+	ProductID int        `json:"product_id" validate:"required"`
+	Name      string     `json:"name" validate:"required,max=100"`
+	Email     string     `json:"email" validate:"required,email,max=100"`
+	Phone     string     `json:"phone" validate:"max=15"`
+	Method    string     `json:"method" validate:"required,max=50"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type OrderStatusRequest struct {
+	Status string `json:"status" validate:"required"`
+}
+
+type OrderFilter struct {
+	Status string
+	Page   int
+	Limit  int
+}
+
+type OrderResponsePagination struct {
+	Orders []Order `json:"orders"`
+	Total  int     `json:"total"`
+	Page   int     `json:"page"`
+	Limit  int     `json:"limit"`
+}
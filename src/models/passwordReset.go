@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// PasswordResetToken adalah token reset password sekali pakai yang diterbitkan admin.
+// Hanya hash token yang disimpan.
+type PasswordResetToken struct {
+	UserID    int
+	TokenHash string
+	CreatedBy string
+	ExpiresAt time.Time
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+// PasswordResetResponse berisi token mentah untuk diteruskan admin ke pemilik akun.
+// Token hanya ditampilkan sekali ini.
+type PasswordResetResponse struct {
+	Username   string    `json:"username"`
+	ResetToken string    `json:"reset_token"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
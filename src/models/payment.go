@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	PaymentStatusPending   = "pending"
+	PaymentStatusPaid      = "paid"
+	PaymentStatusExpired   = "expired"
+	PaymentStatusFailed    = "failed"
+	PaymentStatusCancelled = "cancelled"
+	// PaymentStatusRefundRequired menandai uang yang sudah diterima untuk order yang
+	// tidak dapat dipenuhi lagi, misalnya dibayar setelah expired dan stok habis.
+	PaymentStatusRefundRequired = "refund_required"
+)
+
+type Payment struct {
+	PaymentID  int        `json:"payment_id"`
+	ProductID  int        `json:"product_id"`
+	OrderID    int        `json:"order_id"`
+	Amount     int        `json:"amount"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	Phone      string     `json:"phone"`
+	Method     string     `json:"method"`
+	Status     string     `json:"status"`
+	ExternalID string     `json:"external_id"`
+	PaymentURL string     `json:"payment_url"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+}
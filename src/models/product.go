@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type Product struct {
+	ProductID      int        `json:"product_id"`
+	Name           string     `json:"name" validate:"required,max=100"`
+	BrandProductID int        `json:"brand_product_id" validate:"required"`
+	Price          int        `json:"price" validate:"gte=0"`
+	Description    string     `json:"description"`
+	Duration       string     `json:"duration"`
+	Stock          int        `json:"stock" validate:"gte=0"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+}
@@ -0,0 +1,57 @@
+package models
+
+type Role string
+
+const (
+	RoleAdmin    Role = "admin"
+	RoleStaff    Role = "staff"
+	RoleCustomer Role = "customer"
+)
+
+type Permission string
+
+const (
+	PermissionCatalogWrite Permission = "catalog:write"
+	PermissionUsersManage  Permission = "users:manage"
+	PermissionRolesAssign  Permission = "roles:assign"
+	PermissionOrdersManage Permission = "orders:manage"
+	PermissionSystemManage Permission = "system:manage"
+	PermissionAuditRead    Permission = "audit:read"
+)
+
+// rolePermissions adalah satu-satunya tempat pemetaan role ke permission.
+// Customer tidak punya permission khusus: cukup login untuk endpoint miliknya sendiri.
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionCatalogWrite,
+		PermissionUsersManage,
+		PermissionRolesAssign,
+		PermissionOrdersManage,
+		PermissionSystemManage,
+		PermissionAuditRead,
+	},
+	RoleStaff: {
+		PermissionCatalogWrite,
+		PermissionUsersManage,
+		PermissionOrdersManage,
+	},
+	RoleCustomer: {},
+}
+
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+func (r Role) Permissions() []Permission {
+	return append([]Permission{}, rolePermissions[r]...)
+}
+
+func (r Role) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
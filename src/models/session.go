@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type Session struct {
+	SessionID  string    `json:"session_id"`
+	Username   string    `json:"username"`
+	Role       Role      `json:"role"`
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+// SessionMeta berisi informasi perangkat yang dicatat saat login.
+type SessionMeta struct {
+	Device    string
+	IP        string
+	UserAgent string
+}
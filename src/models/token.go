@@ -0,0 +1,14 @@
+package models
+
+// TokenPair adalah hasil login maupun refresh: access token berumur pendek
+// dan refresh token untuk menerbitkan pasangan token berikutnya.
+type TokenPair struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
@@ -6,6 +6,7 @@ type User struct {
 	UserId    int     `json:"user_id"`
 	Username  string  `json:"username" validate:"required" msg:""`
 	Password  string  `json:"password" validate:"required"`
+	Role      Role    `json:"role" validate:"omitempty,oneof=admin staff customer"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -13,6 +14,7 @@ type User struct {
 type UserResponse struct {
 	UserId    int     `json:"user_id"`
 	Username  string  `json:"username"`
+	Role      Role    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"contact-management/src/models"
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+type auditLogRepository struct {
+	db *Database
+}
+
+func NewAuditLogRepository(db *Database) *auditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+type AuditLogRepository interface {
+	CreateAuditLog(ctx context.Context, auditLog *models.AuditLog) error
+	GetAuditLogs(ctx context.Context, filter models.AuditLogFilter) ([]models.AuditLog, int, error)
+}
+
+// nullJSON menyimpan before/after kosong sebagai NULL.
+func nullJSON(data []byte) sql.NullString {
+	return sql.NullString{String: string(data), Valid: len(data) > 0}
+}
+
+func (ar *auditLogRepository) CreateAuditLog(ctx context.Context, auditLog *models.AuditLog) error {
+	ctx, cancel := ar.db.withTimeout(ctx)
+	defer cancel()
+
+	if auditLog.CreatedAt.IsZero() {
+		auditLog.CreatedAt = time.Now()
+	}
+	auditLog.CreatedAt = auditLog.CreatedAt.UTC().Truncate(time.Second)
+
+	result, err := ar.db.querier(ctx).ExecContext(ctx,
+		"INSERT INTO audit_logs (actor, action, entity_type, entity_id, before_data, after_data, ip, request_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		auditLog.Actor, auditLog.Action, auditLog.EntityType, auditLog.EntityID,
+		nullJSON(auditLog.Before), nullJSON(auditLog.After),
+		auditLog.IP, auditLog.RequestID, utcTime(auditLog.CreatedAt),
+	)
+	if err != nil {
+		return err
+	}
+
+	auditLogID, _ := result.LastInsertId()
+	auditLog.AuditLogID = int(auditLogID)
+	return nil
+}
+
+func (ar *auditLogRepository) GetAuditLogs(ctx context.Context, filter models.AuditLogFilter) ([]models.AuditLog, int, error) {
+	ctx, cancel := ar.db.withTimeout(ctx)
+	defer cancel()
+
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	var conditions []string
+	var args []any
+	for column, value := range map[string]string{
+		"actor":       filter.Actor,
+		"action":      filter.Action,
+		"entity_type": filter.EntityType,
+		"entity_id":   filter.EntityID,
+	} {
+		if value != "" {
+			conditions = append(conditions, column+" = ?")
+			args = append(args, value)
+		}
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, utcTime(*filter.From))
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, utcTime(*filter.To))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	rows, err := ar.db.querier(ctx).QueryContext(ctx,
+		"SELECT audit_log_id, actor, action, entity_type, entity_id, before_data, after_data, ip, request_id, created_at FROM audit_logs"+where+" ORDER BY audit_log_id DESC LIMIT ? OFFSET ?",
+		append(args, filter.Limit, offset)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	auditLogs := make([]models.AuditLog, 0, filter.Limit)
+	for rows.Next() {
+		var auditLog models.AuditLog
+		var before, after sql.NullString
+		if err := rows.Scan(&auditLog.AuditLogID, &auditLog.Actor, &auditLog.Action, &auditLog.EntityType, &auditLog.EntityID, &before, &after, &auditLog.IP, &auditLog.RequestID, &auditLog.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		if before.Valid {
+			auditLog.Before = []byte(before.String)
+		}
+		if after.Valid {
+			auditLog.After = []byte(after.String)
+		}
+		auditLogs = append(auditLogs, auditLog)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	err = ar.db.querier(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_logs"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return auditLogs, total, nil
+}
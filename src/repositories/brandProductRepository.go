@@ -2,31 +2,35 @@ package repositories
 
 import (
 	"contact-management/src/models"
+	"context"
 	"database/sql"
 	"errors"
 )
 
 type brandProductRepository struct {
-	db *sql.DB
+	db *Database
 }
 
-func NewBrandProductRepository(db *sql.DB) *brandProductRepository {
+func NewBrandProductRepository(db *Database) *brandProductRepository {
 	return &brandProductRepository{db: db}
 }
 
 var ErrorBrandProductNotFound = errors.New("brand product not found")
 
 type BrandProductRepository interface {
-	CreateBrandProduct(brandProduct *models.BrandProduct) error
-	GetAllBrandProducts() ([]models.BrandProduct, error)
-	GetBrandProductByID(id int) (*models.BrandProduct, error)
-	UpdateBrandProduct(id int, brandProduct *models.BrandProduct) error
-	DeleteBrandProduct(id int) error
-	GetCategoryByID(id int) (*models.Category, error)
+	CreateBrandProduct(ctx context.Context, brandProduct *models.BrandProduct) error
+	GetAllBrandProducts(ctx context.Context) ([]models.BrandProduct, error)
+	GetBrandProductByID(ctx context.Context, id int) (*models.BrandProduct, error)
+	UpdateBrandProduct(ctx context.Context, id int, brandProduct *models.BrandProduct) error
+	DeleteBrandProduct(ctx context.Context, id int) error
+	GetCategoryByID(ctx context.Context, id int) (*models.Category, error)
 }
 
-func (bpr *brandProductRepository) CreateBrandProduct(brandProduct *models.BrandProduct) error {
-	result, err := bpr.db.Exec("INSERT INTO brand_products (name, category_id) VALUES (?, ?)", brandProduct.Name, brandProduct.CategoryID)
+func (bpr *brandProductRepository) CreateBrandProduct(ctx context.Context, brandProduct *models.BrandProduct) error {
+	ctx, cancel := bpr.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := bpr.db.querier(ctx).ExecContext(ctx, "INSERT INTO brand_products (name, category_id) VALUES (?, ?)", brandProduct.Name, brandProduct.CategoryID)
 	if err != nil {
 		return err
 	}
@@ -36,8 +40,11 @@ func (bpr *brandProductRepository) CreateBrandProduct(brandProduct *models.Brand
 	return nil
 }
 
-func (bpr *brandProductRepository) GetAllBrandProducts() ([]models.BrandProduct, error) {
-	rows, err := bpr.db.Query("SELECT brand_product_id, name, category_id, created_at, updated_at, deleted_at FROM brand_products WHERE deleted_at IS NULL")
+func (bpr *brandProductRepository) GetAllBrandProducts(ctx context.Context) ([]models.BrandProduct, error) {
+	ctx, cancel := bpr.db.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := bpr.db.querier(ctx).QueryContext(ctx, "SELECT brand_product_id, name, category_id, created_at, updated_at, deleted_at FROM brand_products WHERE deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -59,8 +66,11 @@ func (bpr *brandProductRepository) GetAllBrandProducts() ([]models.BrandProduct,
 	return brandProducts, nil
 }
 
-func (bpr *brandProductRepository) GetBrandProductByID(id int) (*models.BrandProduct, error) {
-	row := bpr.db.QueryRow("SELECT brand_product_id, name, category_id, created_at, updated_at, deleted_at FROM brand_products WHERE brand_product_id = ? AND deleted_at IS NULL", id)
+func (bpr *brandProductRepository) GetBrandProductByID(ctx context.Context, id int) (*models.BrandProduct, error) {
+	ctx, cancel := bpr.db.withTimeout(ctx)
+	defer cancel()
+
+	row := bpr.db.querier(ctx).QueryRowContext(ctx, "SELECT brand_product_id, name, category_id, created_at, updated_at, deleted_at FROM brand_products WHERE brand_product_id = ? AND deleted_at IS NULL", id)
 	brandProduct := models.BrandProduct{}
 	var deletedAt sql.NullTime
 	if err := row.Scan(&brandProduct.BrandProductID, &brandProduct.Name, &brandProduct.CategoryID, &brandProduct.CreatedAt, &brandProduct.UpdatedAt, &deletedAt); err != nil {
@@ -75,8 +85,11 @@ func (bpr *brandProductRepository) GetBrandProductByID(id int) (*models.BrandPro
 	return &brandProduct, nil
 }
 
-func (bpr *brandProductRepository) UpdateBrandProduct(id int, brandProduct *models.BrandProduct) error {
-	row, err := bpr.db.Exec("UPDATE brand_products SET name = ?, category_id = ? WHERE brand_product_id = ? AND deleted_at IS NULL", brandProduct.Name, brandProduct.CategoryID, id)
+func (bpr *brandProductRepository) UpdateBrandProduct(ctx context.Context, id int, brandProduct *models.BrandProduct) error {
+	ctx, cancel := bpr.db.withTimeout(ctx)
+	defer cancel()
+
+	row, err := bpr.db.querier(ctx).ExecContext(ctx, "UPDATE brand_products SET name = ?, category_id = ? WHERE brand_product_id = ? AND deleted_at IS NULL", brandProduct.Name, brandProduct.CategoryID, id)
 	if err != nil {
 		return err
 	}
@@ -92,8 +105,11 @@ func (bpr *brandProductRepository) UpdateBrandProduct(id int, brandProduct *mode
 	return nil
 }
 
-func (bpr *brandProductRepository) DeleteBrandProduct(id int) error {
-	row, err := bpr.db.Exec("UPDATE brand_products SET deleted_at = NOW() WHERE brand_product_id = ? AND deleted_at IS NULL", id)
+func (bpr *brandProductRepository) DeleteBrandProduct(ctx context.Context, id int) error {
+	ctx, cancel := bpr.db.withTimeout(ctx)
+	defer cancel()
+
+	row, err := bpr.db.querier(ctx).ExecContext(ctx, "UPDATE brand_products SET deleted_at = "+bpr.db.Dialect.Now()+" WHERE brand_product_id = ? AND deleted_at IS NULL", id)
 	if err != nil {
 		return err
 	}
@@ -109,8 +125,11 @@ func (bpr *brandProductRepository) DeleteBrandProduct(id int) error {
 	return nil
 }
 
-func (bpr *brandProductRepository) GetCategoryByID(id int) (*models.Category, error) {
-	row := bpr.db.QueryRow("SELECT category_id, name, created_at, updated_at, deleted_at FROM category WHERE category_id = ? AND deleted_at IS NULL", id)
+func (bpr *brandProductRepository) GetCategoryByID(ctx context.Context, id int) (*models.Category, error) {
+	ctx, cancel := bpr.db.withTimeout(ctx)
+	defer cancel()
+
+	row := bpr.db.querier(ctx).QueryRowContext(ctx, "SELECT category_id, name, created_at, updated_at, deleted_at FROM category WHERE category_id = ? AND deleted_at IS NULL", id)
 	category := models.Category{}
 	var deletedAt sql.NullTime
 	if err := row.Scan(&category.CategoryID, &category.Name, &category.CreatedAt, &category.UpdatedAt, &deletedAt); err != nil {
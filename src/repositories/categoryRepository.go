@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"contact-management/src/models"
+	"context"
 	"database/sql"
 	"errors"
 )
@@ -9,23 +10,26 @@ import (
 var ErrorCategoryNotFound = errors.New("category not found")
 
 type categoryRepository struct {
-	db *sql.DB
+	db *Database
 }
 
-func NewCategoryRepository(db *sql.DB) *categoryRepository {
-	return &categoryRepository{db}
+func NewCategoryRepository(db *Database) *categoryRepository {
+	return &categoryRepository{db: db}
 }
 
 type CategoryRepository interface {
-	CreateCategory(category *models.Category) error
-	GetAllCategories() ([]*models.Category, error)
-	GetCategoryByID(id int) (*models.Category, error)
-	UpdateCategory(category *models.Category, id int) error
-	DeleteCategory(id int) error
+	CreateCategory(ctx context.Context, category *models.Category) error
+	GetAllCategories(ctx context.Context) ([]*models.Category, error)
+	GetCategoryByID(ctx context.Context, id int) (*models.Category, error)
+	UpdateCategory(ctx context.Context, category *models.Category, id int) error
+	DeleteCategory(ctx context.Context, id int) error
 }
 
-func (cr *categoryRepository) CreateCategory(category *models.Category) error {
-	result, err := cr.db.Exec("INSERT INTO category (name) VALUES (?)", category.Name)
+func (cr *categoryRepository) CreateCategory(ctx context.Context, category *models.Category) error {
+	ctx, cancel := cr.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := cr.db.querier(ctx).ExecContext(ctx, "INSERT INTO category (name) VALUES (?)", category.Name)
 	if err != nil {
 		return err
 	}
@@ -37,8 +41,11 @@ func (cr *categoryRepository) CreateCategory(category *models.Category) error {
 	return nil
 }
 
-func (cr *categoryRepository) GetAllCategories() ([]*models.Category, error) {
-	rows, err := cr.db.Query("SELECT category_id, name, created_at, updated_at, deleted_at FROM category WHERE deleted_at IS NULL")
+func (cr *categoryRepository) GetAllCategories(ctx context.Context) ([]*models.Category, error) {
+	ctx, cancel := cr.db.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := cr.db.querier(ctx).QueryContext(ctx, "SELECT category_id, name, created_at, updated_at, deleted_at FROM category WHERE deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -60,8 +67,11 @@ func (cr *categoryRepository) GetAllCategories() ([]*models.Category, error) {
 	return categories, nil
 }
 
-func (cr *categoryRepository) GetCategoryByID(id int) (*models.Category, error) {
-	row := cr.db.QueryRow("SELECT category_id, name, created_at, updated_at, deleted_at FROM category WHERE category_id = ? AND deleted_at IS NULL", id)
+func (cr *categoryRepository) GetCategoryByID(ctx context.Context, id int) (*models.Category, error) {
+	ctx, cancel := cr.db.withTimeout(ctx)
+	defer cancel()
+
+	row := cr.db.querier(ctx).QueryRowContext(ctx, "SELECT category_id, name, created_at, updated_at, deleted_at FROM category WHERE category_id = ? AND deleted_at IS NULL", id)
 	category := &models.Category{}
 	var deletedAt sql.NullTime
 	if err := row.Scan(&category.CategoryID, &category.Name, &category.CreatedAt, &category.UpdatedAt, &deletedAt); err != nil {
@@ -73,8 +83,11 @@ func (cr *categoryRepository) GetCategoryByID(id int) (*models.Category, error)
 	return category, nil
 }
 
-func (cr *categoryRepository) UpdateCategory(category *models.Category, id int) error {
-	row, err := cr.db.Exec("UPDATE category SET name = ? WHERE category_id = ? AND deleted_at IS NULL", category.Name, id)
+func (cr *categoryRepository) UpdateCategory(ctx context.Context, category *models.Category, id int) error {
+	ctx, cancel := cr.db.withTimeout(ctx)
+	defer cancel()
+
+	row, err := cr.db.querier(ctx).ExecContext(ctx, "UPDATE category SET name = ? WHERE category_id = ? AND deleted_at IS NULL", category.Name, id)
 	if err != nil {
 		return err
 	}
@@ -90,8 +103,11 @@ func (cr *categoryRepository) UpdateCategory(category *models.Category, id int)
 	return nil
 }
 
-func (cr *categoryRepository) DeleteCategory(id int) error {
-	row, err := cr.db.Exec("UPDATE category SET deleted_at = NOW() WHERE category_id = ? AND deleted_at IS NULL", id)
+func (cr *categoryRepository) DeleteCategory(ctx context.Context, id int) error {
+	ctx, cancel := cr.db.withTimeout(ctx)
+	defer cancel()
+
+	row, err := cr.db.querier(ctx).ExecContext(ctx, "UPDATE category SET deleted_at = "+cr.db.Dialect.Now()+" WHERE category_id = ? AND deleted_at IS NULL", id)
 	if err != nil {
 		return err
 	}
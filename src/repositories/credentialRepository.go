@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"contact-management/src/models"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var ErrorCredentialNotFound = errors.New("credential not found")
+
+var ErrorCredentialUnavailable = errors.New("no unused credential available")
+
+type credentialRepository struct {
+	db *Database
+}
+
+func NewCredentialRepository(db *Database) *credentialRepository {
+	return &credentialRepository{db: db}
+}
+
+type CredentialRepository interface {
+	CreateCredentials(ctx context.Context, productID int, ciphertexts []string) error
+	CountAvailableCredentials(ctx context.Context, productID int) (int, error)
+	AssignCredential(ctx context.Context, orderID, productID int) (*models.Credential, error)
+	GetCredentialByOrderID(ctx context.Context, orderID int) (*models.Credential, error)
+}
+
+const credentialColumns = "credential_id, product_id, order_id, secret_ciphertext, assigned_at, created_at"
+
+func scanCredential(row rowScanner) (*models.Credential, error) {
+	credential := models.Credential{}
+	var orderID sql.NullInt64
+	var assignedAt sql.NullTime
+	if err := row.Scan(&credential.CredentialID, &credential.ProductID, &orderID, &credential.Ciphertext, &assignedAt, &credential.CreatedAt); err != nil {
+		return nil, err
+	}
+	if orderID.Valid {
+		id := int(orderID.Int64)
+		credential.OrderID = &id
+	}
+	if assignedAt.Valid {
+		credential.AssignedAt = &assignedAt.Time
+	}
+	return &credential, nil
+}
+
+// CreateCredentials menyimpan credential terenkripsi dan menambah stok produk
+// sebanyak credential yang diunggah dalam satu transaksi.
+func (cr *credentialRepository) CreateCredentials(ctx context.Context, productID int, ciphertexts []string) error {
+	ctx, cancel := cr.db.withTimeout(ctx)
+	defer cancel()
+
+	return cr.db.WithinTx(ctx, func(ctx context.Context) error {
+		tx := cr.db.querier(ctx)
+
+		stmt, err := tx.PrepareContext(ctx, "INSERT INTO product_credentials (product_id, secret_ciphertext) VALUES (?, ?)")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, ciphertext := range ciphertexts {
+			if _, err := stmt.ExecContext(ctx, productID, ciphertext); err != nil {
+				return err
+			}
+		}
+
+		result, err := tx.ExecContext(ctx, "UPDATE products SET stock = COALESCE(stock, 0) + ? WHERE product_id = ? AND deleted_at IS NULL", len(ciphertexts), productID)
+		if err != nil {
+			return err
+		}
+
+		rowAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowAffected == 0 {
+			return ErrorProductNotFound
+		}
+		return nil
+	})
+}
+
+func (cr *credentialRepository) CountAvailableCredentials(ctx context.Context, productID int) (int, error) {
+	ctx, cancel := cr.db.withTimeout(ctx)
+	defer cancel()
+
+	var count int
+	err := cr.db.querier(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM product_credentials WHERE product_id = ? AND order_id IS NULL AND deleted_at IS NULL", productID).Scan(&count)
+	return count, err
+}
+
+// AssignCredential mengambil satu credential yang belum terpakai untuk order.
+// UPDATE ... LIMIT 1 mengunci baris yang dipilih sehingga dua order tidak pernah
+// mendapat credential yang sama, dan kolom order_id yang unik mencegah satu order
+// mendapat dua credential. Pemanggilan ulang mengembalikan credential yang sama.
+// SQLite umumnya dikompilasi tanpa UPDATE ... LIMIT, jadi baris dipilih lewat subquery;
+// satu statement SQLite sudah atomik terhadap penulis lain.
+func (cr *credentialRepository) AssignCredential(ctx context.Context, orderID, productID int) (*models.Credential, error) {
+	ctx, cancel := cr.db.withTimeout(ctx)
+	defer cancel()
+
+	existing, err := cr.GetCredentialByOrderID(ctx, orderID)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, ErrorCredentialNotFound) {
+		return nil, err
+	}
+
+	query := "UPDATE product_credentials SET order_id = ?, assigned_at = NOW() WHERE product_id = ? AND order_id IS NULL AND deleted_at IS NULL ORDER BY credential_id LIMIT 1"
+	if cr.db.Dialect == DialectSQLite {
+		query = "UPDATE product_credentials SET order_id = ?, assigned_at = CURRENT_TIMESTAMP WHERE credential_id = (SELECT credential_id FROM product_credentials WHERE product_id = ? AND order_id IS NULL AND deleted_at IS NULL ORDER BY credential_id LIMIT 1)"
+	}
+
+	result, err := cr.db.querier(ctx).ExecContext(ctx, query, orderID, productID)
+	if err != nil {
+		// order yang sama sudah mendapat credential dari request lain
+		if existing, lookupErr := cr.GetCredentialByOrderID(ctx, orderID); lookupErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowAffected == 0 {
+		return nil, ErrorCredentialUnavailable
+	}
+
+	return cr.GetCredentialByOrderID(ctx, orderID)
+}
+
+func (cr *credentialRepository) GetCredentialByOrderID(ctx context.Context, orderID int) (*models.Credential, error) {
+	ctx, cancel := cr.db.withTimeout(ctx)
+	defer cancel()
+
+	credential, err := scanCredential(cr.db.querier(ctx).QueryRowContext(ctx, "SELECT "+credentialColumns+" FROM product_credentials WHERE order_id = ? AND deleted_at IS NULL", orderID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrorCredentialNotFound
+		}
+		return nil, err
+	}
+	return credential, nil
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Database adalah koneksi yang dipakai bersama oleh semua repository, lengkap dengan
+// dialect SQL-nya dan batas waktu setiap operasi.
+type Database struct {
+	*sql.DB
+	Dialect Dialect
+	// QueryTimeout membatasi satu pemanggilan method repository, termasuk transaksi
+	// di dalamnya, dan satu percobaan WithinTx. Nol berarti hanya mengikuti deadline
+	// context pemanggil.
+	QueryTimeout time.Duration
+}
+
+func NewDatabase(db *sql.DB, dialect Dialect, queryTimeout time.Duration) *Database {
+	return &Database{DB: db, Dialect: dialect, QueryTimeout: queryTimeout}
+}
+
+// withTimeout menurunkan context untuk satu operasi repository. Deadline pemanggil
+// yang lebih dekat tetap berlaku.
+func (d *Database) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.QueryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d.QueryTimeout)
+}
+
+// utcTime memformat waktu yang ditulis atau dibandingkan dari Go, misalnya created_at
+// audit_logs dan expires_at token reset password. Waktu selalu disimpan dalam UTC sebagai
+// teks yang sama di MySQL dan SQLite, sehingga perbandingan berlaku di kedua driver.
+func utcTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// querier adalah bagian *sql.DB dan *sql.Tx yang dipakai repository.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// querier mengembalikan transaksi yang sedang berjalan di ctx bila ada, sehingga
+// repository ikut dalam transaksi yang dibuka lewat WithinTx. Tanpa transaksi query
+// langsung memakai pool koneksi.
+func (d *Database) querier(ctx context.Context) querier {
+	if tx := txFromContext(ctx); tx != nil {
+		return tx
+	}
+	return d.DB
+}
@@ -0,0 +1,45 @@
+package repositories
+
+// Dialect menentukan potongan SQL yang berbeda antara MySQL dan SQLite. Nilainya sama
+// dengan DB_DRIVER; nilai selain sqlite diperlakukan sebagai MySQL.
+type Dialect string
+
+const (
+	DialectMySQL  Dialect = "mysql"
+	DialectSQLite Dialect = "sqlite"
+)
+
+// Now adalah ekspresi waktu saat ini. CURRENT_TIMESTAMP di SQLite selalu UTC, sama
+// dengan nilai default kolom created_at di migrasi SQLite.
+func (d Dialect) Now() string {
+	if d == DialectSQLite {
+		return "CURRENT_TIMESTAMP"
+	}
+	return "NOW()"
+}
+
+// SecondsAgo adalah ekspresi waktu saat ini dikurangi parameter ? dalam detik.
+func (d Dialect) SecondsAgo() string {
+	if d == DialectSQLite {
+		return "datetime('now', '-' || ? || ' seconds')"
+	}
+	return "NOW() - INTERVAL ? SECOND"
+}
+
+// ForUpdate adalah klausa penguncian baris untuk SELECT di dalam transaksi. SQLite tidak
+// punya row lock; koneksinya membuka transaksi dengan _txlock=immediate sehingga lock
+// tulis database sudah dipegang sejak BEGIN.
+func (d Dialect) ForUpdate() string {
+	if d == DialectSQLite {
+		return ""
+	}
+	return " FOR UPDATE"
+}
+
+// InsertIgnore adalah awalan INSERT yang melewati baris pelanggar unique key tanpa error.
+func (d Dialect) InsertIgnore() string {
+	if d == DialectSQLite {
+		return "INSERT OR IGNORE"
+	}
+	return "INSERT IGNORE"
+}
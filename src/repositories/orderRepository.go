@@ -0,0 +1,308 @@
+package repositories
+
+import (
+	"contact-management/src/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrorOrderNotFound = errors.New("order not found")
+
+var ErrorOutOfStock = errors.New("product out of stock")
+
+type orderRepository struct {
+	db *Database
+}
+
+func NewOrderRepository(db *Database) *orderRepository {
+	return &orderRepository{db: db}
+}
+
+type OrderRepository interface {
+	CreateOrder(ctx context.Context, order *models.Order) error
+	GetOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, int, error)
+	GetOrderByID(ctx context.Context, id int) (*models.Order, error)
+	LockOrderByID(ctx context.Context, id int) (*models.Order, error)
+	GetOrderByCode(ctx context.Context, code string) (*models.Order, error)
+	UpdateOrderStatus(ctx context.Context, id int, from, to string) (int64, error)
+	ReleaseOrder(ctx context.Context, id int, from, to string) (int64, error)
+	ReserveOrder(ctx context.Context, id int, from, to string) (int64, error)
+	GetStalePendingOrderIDs(ctx context.Context, olderThan time.Duration) ([]int, error)
+	GetProductByID(ctx context.Context, id int) (*models.Product, error)
+}
+
+const orderColumns = "order_id, COALESCE(order_code, ''), product_id, name, email, COALESCE(phone, ''), method, status, created_at, updated_at, deleted_at"
+
+func scanOrder(row rowScanner) (*models.Order, error) {
+	order := models.Order{}
+	var deletedAt sql.NullTime
+	if err := row.Scan(&order.OrderID, &order.OrderCode, &order.ProductID, &order.Name, &order.Email, &order.Phone, &order.Method, &order.Status, &order.CreatedAt, &order.UpdatedAt, &deletedAt); err != nil {
+		return nil, err
+	}
+	if deletedAt.Valid {
+		order.DeletedAt = &deletedAt.Time
+	}
+	return &order, nil
+}
+
+// CreateOrder menyimpan order baru sekaligus mereservasi satu unit stok produk.
+// Baris produk dikunci dengan SELECT ... FOR UPDATE (di SQLite, lock tulis transaksi)
+// sehingga dua checkout yang bersamaan tidak bisa mengambil unit terakhir yang sama.
+func (or *orderRepository) CreateOrder(ctx context.Context, order *models.Order) error {
+	ctx, cancel := or.db.withTimeout(ctx)
+	defer cancel()
+
+	return or.db.WithinTx(ctx, func(ctx context.Context) error {
+		tx := or.db.querier(ctx)
+
+		var stock int
+		err := tx.QueryRowContext(ctx, "SELECT COALESCE(stock, 0) FROM products WHERE product_id = ? AND deleted_at IS NULL"+or.db.Dialect.ForUpdate(), order.ProductID).Scan(&stock)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrorProductNotFound
+			}
+			return err
+		}
+
+		if stock < 1 {
+			return ErrorOutOfStock
+		}
+
+		reserved, err := tx.ExecContext(ctx, "UPDATE products SET stock = stock - 1 WHERE product_id = ? AND stock >= 1", order.ProductID)
+		if err != nil {
+			return err
+		}
+		if rowAffected, err := reserved.RowsAffected(); err != nil || rowAffected == 0 {
+			return ErrorOutOfStock
+		}
+
+		result, err := tx.ExecContext(ctx, "INSERT INTO orders (order_code, product_id, name, email, phone, method, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			order.OrderCode, order.ProductID, order.Name, order.Email, order.Phone, order.Method, order.Status)
+		if err != nil {
+			return err
+		}
+
+		orderID, _ := result.LastInsertId()
+		order.OrderID = int(orderID)
+		return nil
+	})
+}
+
+func (or *orderRepository) GetOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, int, error) {
+	ctx, cancel := or.db.withTimeout(ctx)
+	defer cancel()
+
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	where := " WHERE deleted_at IS NULL"
+	args := []any{}
+	if filter.Status != "" {
+		where += " AND status = ?"
+		args = append(args, filter.Status)
+	}
+
+	var total int
+	if err := or.db.querier(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM orders"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	rows, err := or.db.querier(ctx).QueryContext(ctx, "SELECT "+orderColumns+" FROM orders"+where+" ORDER BY order_id DESC LIMIT ? OFFSET ?", append(args, filter.Limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	orders := make([]models.Order, 0, filter.Limit)
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		orders = append(orders, *order)
+	}
+
+	return orders, total, rows.Err()
+}
+
+func (or *orderRepository) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
+	ctx, cancel := or.db.withTimeout(ctx)
+	defer cancel()
+
+	row := or.db.querier(ctx).QueryRowContext(ctx, "SELECT "+orderColumns+" FROM orders WHERE order_id = ? AND deleted_at IS NULL", id)
+	order, err := scanOrder(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrorOrderNotFound
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+// LockOrderByID membaca order dan mengunci barisnya sampai transaksi di ctx selesai, agar
+// request lain untuk order yang sama menunggu. Hanya bermakna di dalam WithinTx.
+func (or *orderRepository) LockOrderByID(ctx context.Context, id int) (*models.Order, error) {
+	ctx, cancel := or.db.withTimeout(ctx)
+	defer cancel()
+
+	row := or.db.querier(ctx).QueryRowContext(ctx, "SELECT "+orderColumns+" FROM orders WHERE order_id = ? AND deleted_at IS NULL"+or.db.Dialect.ForUpdate(), id)
+	order, err := scanOrder(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrorOrderNotFound
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+func (or *orderRepository) GetOrderByCode(ctx context.Context, code string) (*models.Order, error) {
+	ctx, cancel := or.db.withTimeout(ctx)
+	defer cancel()
+
+	row := or.db.querier(ctx).QueryRowContext(ctx, "SELECT "+orderColumns+" FROM orders WHERE order_code = ? AND deleted_at IS NULL", code)
+	order, err := scanOrder(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrorOrderNotFound
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+// UpdateOrderStatus hanya mengubah status jika status saat ini masih sama
+// dengan from, sehingga dua perubahan yang bersamaan tidak saling menimpa.
+func (or *orderRepository) UpdateOrderStatus(ctx context.Context, id int, from, to string) (int64, error) {
+	ctx, cancel := or.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := or.db.querier(ctx).ExecContext(ctx, "UPDATE orders SET status = ? WHERE order_id = ? AND status = ? AND deleted_at IS NULL", to, id, from)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ReleaseOrder mengubah status order dan mengembalikan unit stok yang direservasi
+// dalam satu transaksi. Stok hanya dikembalikan jika perubahan status berhasil,
+// sehingga reservasi tidak pernah dilepas dua kali.
+func (or *orderRepository) ReleaseOrder(ctx context.Context, id int, from, to string) (int64, error) {
+	ctx, cancel := or.db.withTimeout(ctx)
+	defer cancel()
+
+	var rowAffected int64
+	err := or.db.WithinTx(ctx, func(ctx context.Context) error {
+		tx := or.db.querier(ctx)
+
+		result, err := tx.ExecContext(ctx, "UPDATE orders SET status = ? WHERE order_id = ? AND status = ? AND deleted_at IS NULL", to, id, from)
+		if err != nil {
+			return err
+		}
+
+		rowAffected, err = result.RowsAffected()
+		if err != nil || rowAffected == 0 {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE products SET stock = COALESCE(stock, 0) + 1 WHERE product_id = (SELECT product_id FROM orders WHERE order_id = ?)", id)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rowAffected, nil
+}
+
+// ReserveOrder kebalikan ReleaseOrder: mengambil lagi satu unit stok untuk order yang
+// reservasinya sudah dilepas lalu mengubah statusnya. ErrorOutOfStock berarti stok
+// sudah habis dan status order tidak diubah.
+func (or *orderRepository) ReserveOrder(ctx context.Context, id int, from, to string) (int64, error) {
+	ctx, cancel := or.db.withTimeout(ctx)
+	defer cancel()
+
+	var rowAffected int64
+	err := or.db.WithinTx(ctx, func(ctx context.Context) error {
+		tx := or.db.querier(ctx)
+
+		var stock int
+		err := tx.QueryRowContext(ctx, "SELECT COALESCE(stock, 0) FROM products WHERE product_id = (SELECT product_id FROM orders WHERE order_id = ?)"+or.db.Dialect.ForUpdate(), id).Scan(&stock)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrorProductNotFound
+			}
+			return err
+		}
+		if stock < 1 {
+			return ErrorOutOfStock
+		}
+
+		result, err := tx.ExecContext(ctx, "UPDATE orders SET status = ? WHERE order_id = ? AND status = ? AND deleted_at IS NULL", to, id, from)
+		if err != nil {
+			return err
+		}
+
+		rowAffected, err = result.RowsAffected()
+		if err != nil || rowAffected == 0 {
+			return err
+		}
+
+		reserved, err := tx.ExecContext(ctx, "UPDATE products SET stock = stock - 1 WHERE product_id = (SELECT product_id FROM orders WHERE order_id = ?) AND stock >= 1", id)
+		if err != nil {
+			return err
+		}
+		if row, err := reserved.RowsAffected(); err != nil || row == 0 {
+			return ErrorOutOfStock
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rowAffected, nil
+}
+
+func (or *orderRepository) GetStalePendingOrderIDs(ctx context.Context, olderThan time.Duration) ([]int, error) {
+	ctx, cancel := or.db.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := or.db.querier(ctx).QueryContext(ctx, "SELECT order_id FROM orders WHERE status = ? AND deleted_at IS NULL AND created_at < "+or.db.Dialect.SecondsAgo(), models.OrderStatusPending, int(olderThan.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (or *orderRepository) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
+	ctx, cancel := or.db.withTimeout(ctx)
+	defer cancel()
+
+	row := or.db.querier(ctx).QueryRowContext(ctx, "SELECT "+productColumns+" FROM products WHERE product_id = ? AND deleted_at IS NULL", id)
+	product, err := scanProduct(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrorProductNotFound
+		}
+		return nil, err
+	}
+	return product, nil
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"contact-management/src/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrInvalidResetToken = errors.New("token reset password tidak valid atau sudah kedaluwarsa")
+
+type passwordResetRepository struct {
+	db *Database
+}
+
+func NewPasswordResetRepository(db *Database) *passwordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+type PasswordResetRepository interface {
+	CreateResetToken(ctx context.Context, token *models.PasswordResetToken) error
+	// ConsumeResetToken menandai token terpakai dan mengembalikan username pemiliknya.
+	// Token yang tidak ada, kedaluwarsa per now, atau sudah dipakai menghasilkan
+	// ErrInvalidResetToken.
+	ConsumeResetToken(ctx context.Context, tokenHash string, now time.Time) (string, error)
+	// DeleteUnusedResetTokens membatalkan semua token user yang belum dipakai.
+	DeleteUnusedResetTokens(ctx context.Context, userID int) error
+}
+
+func (pr *passwordResetRepository) CreateResetToken(ctx context.Context, token *models.PasswordResetToken) error {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := pr.db.querier(ctx).ExecContext(ctx, "INSERT INTO password_reset_tokens (user_id, token_hash, created_by, expires_at) VALUES (?, ?, ?, ?)",
+		token.UserID, token.TokenHash, token.CreatedBy, utcTime(token.ExpiresAt))
+	return err
+}
+
+func (pr *passwordResetRepository) ConsumeResetToken(ctx context.Context, tokenHash string, now time.Time) (string, error) {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	// UPDATE bersyarat membuat token hanya bisa dipakai sekali walaupun ditukar bersamaan
+	result, err := pr.db.querier(ctx).ExecContext(ctx, "UPDATE password_reset_tokens SET used_at = ? WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?",
+		utcTime(now), tokenHash, utcTime(now))
+	if err != nil {
+		return "", err
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+	if rowAffected == 0 {
+		return "", ErrInvalidResetToken
+	}
+
+	var username string
+	err = pr.db.querier(ctx).QueryRowContext(ctx, "SELECT u.username FROM password_reset_tokens t JOIN users u ON u.user_id = t.user_id WHERE t.token_hash = ?", tokenHash).Scan(&username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrInvalidResetToken
+		}
+		return "", err
+	}
+	return username, nil
+}
+
+func (pr *passwordResetRepository) DeleteUnusedResetTokens(ctx context.Context, userID int) error {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := pr.db.querier(ctx).ExecContext(ctx, "DELETE FROM password_reset_tokens WHERE user_id = ? AND used_at IS NULL", userID)
+	return err
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"contact-management/src/models"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var ErrorPaymentNotFound = errors.New("payment not found")
+
+type paymentRepository struct {
+	db *Database
+}
+
+func NewPaymentRepository(db *Database) *paymentRepository {
+	return &paymentRepository{db: db}
+}
+
+type PaymentRepository interface {
+	CreatePayment(ctx context.Context, payment *models.Payment) error
+	GetPaymentByID(ctx context.Context, id int) (*models.Payment, error)
+	GetPaymentByExternalID(ctx context.Context, externalID string) (*models.Payment, error)
+	GetPendingPaymentByOrderID(ctx context.Context, orderID int) (*models.Payment, error)
+	GetPendingPaymentsByOrderID(ctx context.Context, orderID int) ([]models.Payment, error)
+	UpdatePaymentStatus(ctx context.Context, id int, from, to string) (int64, error)
+	HasWebhookEvent(ctx context.Context, provider, eventID string) (bool, error)
+	RecordWebhookEvent(ctx context.Context, provider, eventID string, paymentID int, status string) (bool, error)
+}
+
+const paymentColumns = "payment_id, product_id, order_id, amount, name, email, COALESCE(phone, ''), method, status, external_id, COALESCE(payment_url, ''), created_at, updated_at, deleted_at"
+
+func scanPayment(row rowScanner) (*models.Payment, error) {
+	payment := models.Payment{}
+	var deletedAt sql.NullTime
+	if err := row.Scan(&payment.PaymentID, &payment.ProductID, &payment.OrderID, &payment.Amount, &payment.Name, &payment.Email, &payment.Phone, &payment.Method, &payment.Status, &payment.ExternalID, &payment.PaymentURL, &payment.CreatedAt, &payment.UpdatedAt, &deletedAt); err != nil {
+		return nil, err
+	}
+	if deletedAt.Valid {
+		payment.DeletedAt = &deletedAt.Time
+	}
+	return &payment, nil
+}
+
+func (pr *paymentRepository) CreatePayment(ctx context.Context, payment *models.Payment) error {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := pr.db.querier(ctx).ExecContext(ctx, "INSERT INTO payments (product_id, order_id, amount, name, email, phone, method, status, external_id, payment_url) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		payment.ProductID, payment.OrderID, payment.Amount, payment.Name, payment.Email, payment.Phone, payment.Method, payment.Status, payment.ExternalID, payment.PaymentURL)
+	if err != nil {
+		return err
+	}
+
+	paymentID, _ := result.LastInsertId()
+	payment.PaymentID = int(paymentID)
+	return nil
+}
+
+func (pr *paymentRepository) getPayment(ctx context.Context, query string, args ...any) (*models.Payment, error) {
+	payment, err := scanPayment(pr.db.querier(ctx).QueryRowContext(ctx, "SELECT "+paymentColumns+" FROM payments WHERE "+query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrorPaymentNotFound
+		}
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (pr *paymentRepository) GetPaymentByID(ctx context.Context, id int) (*models.Payment, error) {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	return pr.getPayment(ctx, "payment_id = ? AND deleted_at IS NULL", id)
+}
+
+func (pr *paymentRepository) GetPaymentByExternalID(ctx context.Context, externalID string) (*models.Payment, error) {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	return pr.getPayment(ctx, "external_id = ? AND deleted_at IS NULL", externalID)
+}
+
+func (pr *paymentRepository) GetPendingPaymentByOrderID(ctx context.Context, orderID int) (*models.Payment, error) {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	return pr.getPayment(ctx, "order_id = ? AND status = ? AND deleted_at IS NULL ORDER BY payment_id DESC LIMIT 1", orderID, models.PaymentStatusPending)
+}
+
+func (pr *paymentRepository) GetPendingPaymentsByOrderID(ctx context.Context, orderID int) ([]models.Payment, error) {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := pr.db.querier(ctx).QueryContext(ctx, "SELECT "+paymentColumns+" FROM payments WHERE order_id = ? AND status = ? AND deleted_at IS NULL ORDER BY payment_id", orderID, models.PaymentStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []models.Payment
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, *payment)
+	}
+	return payments, rows.Err()
+}
+
+func (pr *paymentRepository) UpdatePaymentStatus(ctx context.Context, id int, from, to string) (int64, error) {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := pr.db.querier(ctx).ExecContext(ctx, "UPDATE payments SET status = ? WHERE payment_id = ? AND status = ? AND deleted_at IS NULL", to, id, from)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (pr *paymentRepository) HasWebhookEvent(ctx context.Context, provider, eventID string) (bool, error) {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	var count int
+	err := pr.db.querier(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM payment_webhook_events WHERE provider = ? AND event_id = ?", provider, eventID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RecordWebhookEvent mencatat event yang sudah diproses. Nilai false berarti
+// event yang sama sudah tercatat lebih dulu oleh request lain.
+func (pr *paymentRepository) RecordWebhookEvent(ctx context.Context, provider, eventID string, paymentID int, status string) (bool, error) {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := pr.db.querier(ctx).ExecContext(ctx, pr.db.Dialect.InsertIgnore()+" INTO payment_webhook_events (provider, event_id, payment_id, status) VALUES (?, ?, ?, ?)", provider, eventID, paymentID, status)
+	if err != nil {
+		return false, err
+	}
+
+	rowAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowAffected > 0, nil
+}
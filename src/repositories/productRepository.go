@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"contact-management/src/models"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var ErrorProductNotFound = errors.New("product not found")
+
+type productRepository struct {
+	db *Database
+}
+
+func NewProductRepository(db *Database) *productRepository {
+	return &productRepository{db: db}
+}
+
+type ProductRepository interface {
+	CreateProduct(ctx context.Context, product *models.Product) error
+	GetAllProducts(ctx context.Context) ([]models.Product, error)
+	GetProductByID(ctx context.Context, id int) (*models.Product, error)
+	UpdateProduct(ctx context.Context, id int, product *models.Product) error
+	DeleteProduct(ctx context.Context, id int) error
+	GetBrandProductByID(ctx context.Context, id int) (*models.BrandProduct, error)
+}
+
+const productColumns = "product_id, name, brand_product_id, COALESCE(price, 0), COALESCE(description, ''), COALESCE(duration, ''), COALESCE(stock, 0), created_at, updated_at, deleted_at"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanProduct(row rowScanner) (*models.Product, error) {
+	product := models.Product{}
+	var deletedAt sql.NullTime
+	if err := row.Scan(&product.ProductID, &product.Name, &product.BrandProductID, &product.Price, &product.Description, &product.Duration, &product.Stock, &product.CreatedAt, &product.UpdatedAt, &deletedAt); err != nil {
+		return nil, err
+	}
+	if deletedAt.Valid {
+		product.DeletedAt = &deletedAt.Time
+	}
+	return &product, nil
+}
+
+func (pr *productRepository) CreateProduct(ctx context.Context, product *models.Product) error {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := pr.db.querier(ctx).ExecContext(ctx, "INSERT INTO products (name, brand_product_id, price, description, duration, stock) VALUES (?, ?, ?, ?, ?, ?)",
+		product.Name, product.BrandProductID, product.Price, product.Description, product.Duration, product.Stock)
+	if err != nil {
+		return err
+	}
+
+	productID, _ := result.LastInsertId()
+	product.ProductID = int(productID)
+	return nil
+}
+
+func (pr *productRepository) GetAllProducts(ctx context.Context) ([]models.Product, error) {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := pr.db.querier(ctx).QueryContext(ctx, "SELECT "+productColumns+" FROM products WHERE deleted_at IS NULL")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []models.Product{}
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, *product)
+	}
+
+	return products, rows.Err()
+}
+
+func (pr *productRepository) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	row := pr.db.querier(ctx).QueryRowContext(ctx, "SELECT "+productColumns+" FROM products WHERE product_id = ? AND deleted_at IS NULL", id)
+	product, err := scanProduct(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrorProductNotFound
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+// UpdateProduct tidak menyentuh stock: stok hanya berubah lewat reservasi order dan
+// unggah credential agar tidak menimpa perubahan yang berjalan bersamaan.
+func (pr *productRepository) UpdateProduct(ctx context.Context, id int, product *models.Product) error {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := pr.db.querier(ctx).ExecContext(ctx, "UPDATE products SET name = ?, brand_product_id = ?, price = ?, description = ?, duration = ? WHERE product_id = ? AND deleted_at IS NULL",
+		product.Name, product.BrandProductID, product.Price, product.Description, product.Duration, id)
+	if err != nil {
+		return err
+	}
+
+	product.ProductID = id
+	return nil
+}
+
+func (pr *productRepository) DeleteProduct(ctx context.Context, id int) error {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	row, err := pr.db.querier(ctx).ExecContext(ctx, "UPDATE products SET deleted_at = "+pr.db.Dialect.Now()+" WHERE product_id = ? AND deleted_at IS NULL", id)
+	if err != nil {
+		return err
+	}
+
+	rowAffected, err := row.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowAffected == 0 {
+		return ErrorProductNotFound
+	}
+	return nil
+}
+
+func (pr *productRepository) GetBrandProductByID(ctx context.Context, id int) (*models.BrandProduct, error) {
+	ctx, cancel := pr.db.withTimeout(ctx)
+	defer cancel()
+
+	row := pr.db.querier(ctx).QueryRowContext(ctx, "SELECT brand_product_id, name, category_id, created_at, updated_at FROM brand_products WHERE brand_product_id = ? AND deleted_at IS NULL", id)
+	brandProduct := models.BrandProduct{}
+	if err := row.Scan(&brandProduct.BrandProductID, &brandProduct.Name, &brandProduct.CategoryID, &brandProduct.CreatedAt, &brandProduct.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrorBrandProductNotFound
+		}
+		return nil, err
+	}
+	return &brandProduct, nil
+}
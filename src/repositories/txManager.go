@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// TxManager menjalankan beberapa operasi repository sebagai satu unit kerja.
+type TxManager interface {
+	// WithinTx menjalankan fn di dalam transaksi. Repository yang dipanggil dengan ctx
+	// milik fn memakai transaksi tersebut; transaksi di-commit bila fn mengembalikan nil
+	// dan di-rollback bila tidak. Pemanggilan bersarang ikut transaksi terluar.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+const (
+	// txMaxAttempts adalah jumlah percobaan transaksi yang gagal karena deadlock
+	// atau konflik serialisasi, termasuk percobaan pertama.
+	txMaxAttempts = 4
+	txBaseBackoff = 20 * time.Millisecond
+)
+
+// MySQL memakai kode 1213 untuk deadlock maupun kegagalan serialisasi.
+const mysqlErrDeadlock = 1213
+
+type txKey struct{}
+
+type afterCommitKey struct{}
+
+func txFromContext(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(txKey{}).(*sql.Tx)
+	return tx
+}
+
+// AfterCommit menjadwalkan fn, misalnya panggilan ke layanan luar, untuk dijalankan setelah
+// transaksi terluar di ctx berhasil di-commit. Bila transaksi di-rollback atau diulang,
+// fn dari percobaan tersebut dibuang. Di luar transaksi fn langsung dijalankan.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	hooks, ok := ctx.Value(afterCommitKey{}).(*[]func(context.Context))
+	if !ok {
+		fn(ctx)
+		return
+	}
+	*hooks = append(*hooks, fn)
+}
+
+// WithinTx mengulang seluruh fn dengan backoff eksponensial bila transaksi gagal karena
+// deadlock atau konflik serialisasi, jadi fn tidak boleh punya efek samping di luar
+// database; efek samping seperti itu dijadwalkan lewat AfterCommit. *sql.Tx tidak aman dipakai bersamaan, jadi fn tidak boleh memanggil
+// repository dari goroutine lain.
+func (d *Database) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if txFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	backoff := txBaseBackoff
+	for attempt := 1; ; attempt++ {
+		var hooks []func(context.Context)
+		err := d.runTx(context.WithValue(ctx, afterCommitKey{}, &hooks), fn)
+		if err == nil {
+			for _, hook := range hooks {
+				hook(ctx)
+			}
+			return nil
+		}
+		if attempt == txMaxAttempts || !d.isRetryable(err) {
+			return err
+		}
+
+		// jitter mencegah transaksi yang saling deadlock mencoba ulang bersamaan
+		timer := time.NewTimer(backoff/2 + rand.N(backoff))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (d *Database) runTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// isRetryable melaporkan apakah err berasal dari deadlock atau konflik serialisasi yang
+// aman diulang dari awal transaksi. Di SQLite ini berarti database masih dikunci penulis
+// lain setelah busy_timeout habis.
+func (d *Database) isRetryable(err error) bool {
+	if d.Dialect == DialectSQLite {
+		var sqliteErr *sqlite.Error
+		if !errors.As(err, &sqliteErr) {
+			return false
+		}
+		code := sqliteErr.Code() & 0xff
+		return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+	}
+
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDeadlock
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"contact-management/src/models"
+	"context"
 	"database/sql"
 	"errors"
 )
@@ -9,23 +10,27 @@ import (
 var ErrUserNotFound = errors.New("User tidak ditemukan")
 
 type UserRepository interface {
-	GetUsers(page, limit int) ([]models.User, int, error)
-	FindByUsername(username string) (*models.User, error)
-	CreateUser(user *models.User) error
-	UpdateUser(username string, user *models.User) (int64, error)
-	DeleteUser(username string) (int64, error)
+	GetUsers(ctx context.Context, page, limit int) ([]models.User, int, error)
+	FindByUsername(ctx context.Context, username string) (*models.User, error)
+	CreateUser(ctx context.Context, user *models.User) error
+	UpdateUser(ctx context.Context, username string, user *models.User) (int64, error)
+	UpdatePassword(ctx context.Context, username, hashedPassword string) (int64, error)
+	DeleteUser(ctx context.Context, username string) (int64, error)
 }
 
 type userRepository struct {
-	db *sql.DB
+	db *Database
 }
 
-func NewUserRepository(db *sql.DB) UserRepository {
+func NewUserRepository(db *Database) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (u *userRepository) CreateUser(user *models.User) error {
-	result, err := u.db.Exec("INSERT INTO users (username, password) VALUES (?, ?)", user.Username, user.Password)
+func (u *userRepository) CreateUser(ctx context.Context, user *models.User) error {
+	ctx, cancel := u.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := u.db.querier(ctx).ExecContext(ctx, "INSERT INTO users (username, password, role) VALUES (?, ?, ?)", user.Username, user.Password, user.Role)
 	if err != nil {
 		return err
 	}
@@ -40,9 +45,12 @@ func (u *userRepository) CreateUser(user *models.User) error {
 	return nil
 }
 
-func (u *userRepository) FindByUsername(username string) (*models.User, error) {
+func (u *userRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
+	ctx, cancel := u.db.withTimeout(ctx)
+	defer cancel()
+
 	var user models.User
-	err := u.db.QueryRow("SELECT user_id, username, password FROM users WHERE username = ?", username).Scan(&user.UserId, &user.Username, &user.Password)
+	err := u.db.querier(ctx).QueryRowContext(ctx, "SELECT user_id, username, password, role, created_at FROM users WHERE username = ?", username).Scan(&user.UserId, &user.Username, &user.Password, &user.Role, &user.CreatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrUserNotFound
@@ -53,7 +61,10 @@ func (u *userRepository) FindByUsername(username string) (*models.User, error) {
 	return &user, nil
 }
 
-func (u *userRepository) GetUsers(page, limit int) ([]models.User, int, error) {
+func (u *userRepository) GetUsers(ctx context.Context, page, limit int) ([]models.User, int, error) {
+	ctx, cancel := u.db.withTimeout(ctx)
+	defer cancel()
+
 	if limit <= 0 {
 		limit = 10
 	}
@@ -62,7 +73,7 @@ func (u *userRepository) GetUsers(page, limit int) ([]models.User, int, error) {
 	}
 
 	offset := (page - 1) * limit
-	rows, err := u.db.Query("SELECT user_id, username, created_at, updated_at FROM users LIMIT ? OFFSET ?", limit, offset)
+	rows, err := u.db.querier(ctx).QueryContext(ctx, "SELECT user_id, username, role, created_at, updated_at FROM users ORDER BY user_id LIMIT ? OFFSET ?", limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -71,7 +82,7 @@ func (u *userRepository) GetUsers(page, limit int) ([]models.User, int, error) {
 	users := make([]models.User, 0, limit)
 	for rows.Next() {
 		var user models.User
-		err := rows.Scan(&user.UserId, &user.Username, &user.CreatedAt, &user.UpdatedAt)
+		err := rows.Scan(&user.UserId, &user.Username, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -79,7 +90,7 @@ func (u *userRepository) GetUsers(page, limit int) ([]models.User, int, error) {
 	}
 
 	var total int
-	err = u.db.QueryRow("SELECT COUNT(*) FROM users").Scan(&total)
+	err = u.db.querier(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -87,16 +98,15 @@ func (u *userRepository) GetUsers(page, limit int) ([]models.User, int, error) {
 	return users, total, nil
 }
 
-func (u *userRepository) UpdateUser(username string, user *models.User) (int64, error) {
-	result, err := u.db.Exec("UPDATE users SET username = ?, password = ? WHERE username = ?", user.Username, user.Password, username)
-	
-	id, err := result.LastInsertId()
+func (u *userRepository) UpdateUser(ctx context.Context, username string, user *models.User) (int64, error) {
+	ctx, cancel := u.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := u.db.querier(ctx).ExecContext(ctx, "UPDATE users SET username = ?, password = ?, role = ? WHERE username = ?", user.Username, user.Password, user.Role, username)
 	if err != nil {
 		return 0, err
 	}
 
-	user.UserId = int(id)
-
 	RowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return 0, err
@@ -104,8 +114,26 @@ func (u *userRepository) UpdateUser(username string, user *models.User) (int64,
 	return RowsAffected, err
 }
 
-func (u *userRepository) DeleteUser(username string) (int64, error)  {
-	row, err := u.db.Exec("DELETE FROM users WHERE username = ?", username)
+// UpdatePassword hanya mengganti hash password, tanpa menyentuh username maupun role.
+func (u *userRepository) UpdatePassword(ctx context.Context, username, hashedPassword string) (int64, error) {
+	ctx, cancel := u.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := u.db.querier(ctx).ExecContext(ctx, "UPDATE users SET password = ? WHERE username = ?", hashedPassword, username)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (u *userRepository) DeleteUser(ctx context.Context, username string) (int64, error)  {
+	ctx, cancel := u.db.withTimeout(ctx)
+	defer cancel()
+
+	row, err := u.db.querier(ctx).ExecContext(ctx, "DELETE FROM users WHERE username = ?", username)
+	if err != nil {
+		return 0, err
+	}
 	RowsAffected, err := row.RowsAffected()
 	if err != nil {
 		return 0, err
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"context"
+	"encoding/json"
+)
+
+// systemActor dicatat sebagai actor bila perubahan tidak datang dari request terautentikasi,
+// misalnya dari CLI atau background worker.
+const systemActor = "system"
+
+// AuditEntry adalah satu perubahan yang akan dicatat AuditService. Before dan After
+// di-marshal ke JSON apa adanya; nil berarti entitas belum ada atau sudah dihapus.
+type AuditEntry struct {
+	// Actor kosong berarti diambil dari username di context request.
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     any
+	After      any
+}
+
+// AuditService mencatat siapa mengubah apa ke tabel audit_logs. Actor, IP dan request id
+// diambil dari context yang diisi RequestLogger dan AuthMiddleware. Dipanggil di dalam
+// WithinTx, catatan ikut transaksi perubahan yang dicatatnya.
+type AuditService struct {
+	auditLogRepo repositories.AuditLogRepository
+}
+
+func NewAuditService(auditLogRepo repositories.AuditLogRepository) *AuditService {
+	return &AuditService{auditLogRepo: auditLogRepo}
+}
+
+func (as *AuditService) Record(ctx context.Context, entry AuditEntry) error {
+	before, err := auditJSON(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := auditJSON(entry.After)
+	if err != nil {
+		return err
+	}
+
+	actor := entry.Actor
+	if actor == "" {
+		actor, _ = ctx.Value("username").(string)
+	}
+	if actor == "" {
+		actor = systemActor
+	}
+	ip, _ := ctx.Value("ip").(string)
+	requestID, _ := ctx.Value("request_id").(string)
+
+	return as.auditLogRepo.CreateAuditLog(ctx, &models.AuditLog{
+		Actor:      actor,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Before:     before,
+		After:      after,
+		IP:         ip,
+		RequestID:  requestID,
+	})
+}
+
+func auditJSON(value any) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
+
+func (as *AuditService) GetAuditLogs(ctx context.Context, filter models.AuditLogFilter) (*models.AuditLogResponsePagination, error) {
+	auditLogs, total, err := as.auditLogRepo.GetAuditLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuditLogResponsePagination{
+		AuditLogs: auditLogs,
+		Total:     total,
+		Page:      filter.Page,
+		Limit:     filter.Limit,
+	}, nil
+}
@@ -1,29 +1,45 @@
 package services
 
 import (
+	"contact-management/src/config"
 	"contact-management/src/helpers"
 	"contact-management/src/models"
 	"contact-management/src/repositories"
 	"contact-management/src/utils"
+	"context"
 	"errors"
+	"sync"
 
 	"golang.org/x/crypto/bcrypt"
 	// "github.com/go-playground/validator/v10"
 )
 
 type AuthService struct {
-	userRepo repositories.UserRepository
+	userRepo     repositories.UserRepository
+	tokenService *utils.TokenService
+	loginGuard   *LoginGuard
+	passwordCfg  config.PasswordConfig
 }
 
 var ErrUsernameTaken = errors.New("username sudah digunakan")
 
 var ErrInvalidCredentials = errors.New("username atau password salah")
 
-func NewAuthService(userRepo repositories.UserRepository) *AuthService {
-	return &AuthService{userRepo: userRepo}
+// dummyPasswordHash dibandingkan saat username tidak ditemukan, agar waktu respons
+// tidak membocorkan username mana yang terdaftar.
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
+
+func NewAuthService(userRepo repositories.UserRepository, tokenService *utils.TokenService, loginGuard *LoginGuard, passwordCfg config.PasswordConfig) *AuthService {
+	return &AuthService{userRepo: userRepo, tokenService: tokenService, loginGuard: loginGuard, passwordCfg: passwordCfg}
 }
 
-func (a *AuthService) Register(user *models.User) error {
+func (a *AuthService) Register(ctx context.Context, user *models.User) error {
+	// Registrasi mandiri selalu menjadi customer; role lain hanya diberikan oleh admin.
+	user.Role = models.RoleCustomer
+
 	validate := helpers.InitValidator()
 
 	err := validate.Struct(user)
@@ -31,8 +47,11 @@ func (a *AuthService) Register(user *models.User) error {
 		formatted := helpers.FormatValidationError(err)
 		return helpers.ValidationErrors{Messages: formatted}
 	}
+	if err := validatePassword("password", user.Password, user.Username, a.passwordCfg.MinLength); err != nil {
+		return err
+	}
 
-	isUser, err := a.userRepo.FindByUsername(user.Username)
+	isUser, err := a.userRepo.FindByUsername(ctx, user.Username)
 	if err != nil && !errors.Is(err, repositories.ErrUserNotFound) {
 		return err
 	}
@@ -48,7 +67,7 @@ func (a *AuthService) Register(user *models.User) error {
 
 	user.Password = string(hashedPassword)
 
-	err = a.userRepo.CreateUser(user)
+	err = a.userRepo.CreateUser(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -56,37 +75,65 @@ func (a *AuthService) Register(user *models.User) error {
 	return nil
 }
 
-func (a *AuthService) Login(user *models.User) (string, error) {
+func (a *AuthService) Login(ctx context.Context, user *models.User, meta models.SessionMeta) (*models.TokenPair, error) {
 	validate := helpers.InitValidator()
 
 	err := validate.Struct(user)
 	if err != nil {
 		formatted := helpers.FormatValidationError(err)
-		return "", helpers.ValidationErrors{Messages: formatted}
+		return nil, helpers.ValidationErrors{Messages: formatted}
 	}
 
 
-	data_user, err := a.userRepo.FindByUsername(user.Username)
-	if err != nil {
-		return "", err
+	if err := a.loginGuard.Check(ctx, user.Username, meta.IP); err != nil {
+		return nil, err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(data_user.Password), []byte(user.Password)); err != nil {
-		return "", ErrInvalidCredentials
+	data_user, err := a.userRepo.FindByUsername(ctx, user.Username)
+	if err != nil && !errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, err
+	}
+
+	// username yang tidak terdaftar diperlakukan sama dengan password yang salah
+	hash := dummyPasswordHash()
+	if data_user != nil {
+		hash = []byte(data_user.Password)
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(user.Password)); err != nil || data_user == nil {
+		if err := a.loginGuard.Failed(ctx, user.Username, meta.IP, data_user != nil); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := a.loginGuard.Succeeded(ctx, data_user.Username); err != nil {
+		return nil, err
+	}
+
+	tokens, err := a.tokenService.GenerateTokenPair(ctx, data_user.Username, data_user.Role, meta)
+
+	if err != nil {
+		return nil, err
 	}
 
-	token, err := utils.GenerateToken(user.Username)
+	return tokens, nil
+}
+
+func (a *AuthService) Refresh(ctx context.Context, request *models.RefreshTokenRequest) (*models.TokenPair, error) {
+	validate := helpers.InitValidator()
 
+	err := validate.Struct(request)
 	if err != nil {
-		return "", err
+		formatted := helpers.FormatValidationError(err)
+		return nil, helpers.ValidationErrors{Messages: formatted}
 	}
 
-	return token, nil
+	return a.tokenService.RefreshTokens(ctx, request.RefreshToken)
 }
 
-func (a *AuthService) Me(username string) (*models.User, error) {
+func (a *AuthService) Me(ctx context.Context, username string) (*models.User, error) {
 
-	user, err := a.userRepo.FindByUsername(username)
+	user, err := a.userRepo.FindByUsername(ctx, username)
 	if err != nil {
 		return nil, err
 	}
@@ -94,15 +141,36 @@ func (a *AuthService) Me(username string) (*models.User, error) {
 	return user, nil
 }
 
-func (a *AuthService) Logout(username, token string) error {
-	_, err := a.userRepo.FindByUsername(username)
+func (a *AuthService) Logout(ctx context.Context, username, token string) error {
+	_, err := a.userRepo.FindByUsername(ctx, username)
 	if err != nil {
 		return err
 	}
 
-	err = utils.RevokeToken(token, username)
+	err = a.tokenService.RevokeToken(ctx, token, username)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// ListSessions mengembalikan semua sesi aktif user dan menandai sesi yang sedang dipakai.
+func (a *AuthService) ListSessions(ctx context.Context, username, currentSessionID string) ([]models.Session, error) {
+	sessions, err := a.tokenService.ListSessions(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].SessionID == currentSessionID
+	}
+	return sessions, nil
+}
+
+func (a *AuthService) RevokeSession(ctx context.Context, username, sessionID string) error {
+	return a.tokenService.RevokeSession(ctx, username, sessionID)
+}
+
+func (a *AuthService) LogoutEverywhere(ctx context.Context, username string) error {
+	return a.tokenService.RevokeAllSessions(ctx, username)
+}
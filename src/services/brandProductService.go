@@ -4,17 +4,21 @@ import (
 	"contact-management/src/helpers"
 	"contact-management/src/models"
 	"contact-management/src/repositories"
+	"context"
+	"strconv"
 )
 
 type BrandProductService struct {
 	brandProductRepository repositories.BrandProductRepository
+	txManager              repositories.TxManager
+	audit                  *AuditService
 }
 
-func NewBrandProductService(brandProductRepository repositories.BrandProductRepository) *BrandProductService {
-	return &BrandProductService{brandProductRepository: brandProductRepository}
+func NewBrandProductService(brandProductRepository repositories.BrandProductRepository, txManager repositories.TxManager, audit *AuditService) *BrandProductService {
+	return &BrandProductService{brandProductRepository: brandProductRepository, txManager: txManager, audit: audit}
 }
 
-func (bps *BrandProductService) CreateBrandProduct(brandProduct *models.BrandProduct) error {
+func (bps *BrandProductService) CreateBrandProduct(ctx context.Context, brandProduct *models.BrandProduct) error {
 	validate := helpers.InitValidator()
 
 	err := validate.Struct(brandProduct)
@@ -23,7 +27,7 @@ func (bps *BrandProductService) CreateBrandProduct(brandProduct *models.BrandPro
 		return helpers.ValidationErrors{Messages: formatted}
 	}
 
-	category, err := bps.brandProductRepository.GetCategoryByID(brandProduct.CategoryID)
+	category, err := bps.brandProductRepository.GetCategoryByID(ctx, brandProduct.CategoryID)
 	if err != nil {
 		return err
 	}
@@ -32,15 +36,29 @@ func (bps *BrandProductService) CreateBrandProduct(brandProduct *models.BrandPro
 		return repositories.ErrorCategoryNotFound
 	}
 
-	return bps.brandProductRepository.CreateBrandProduct(brandProduct)
+	return bps.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := bps.brandProductRepository.CreateBrandProduct(ctx, brandProduct); err != nil {
+			return err
+		}
+		after, err := bps.brandProductRepository.GetBrandProductByID(ctx, brandProduct.BrandProductID)
+		if err != nil {
+			return err
+		}
+		return bps.audit.Record(ctx, AuditEntry{
+			Action:     models.AuditActionCreate,
+			EntityType: models.AuditEntityBrandProduct,
+			EntityID:   strconv.Itoa(brandProduct.BrandProductID),
+			After:      after,
+		})
+	})
 }
 
-func (bps *BrandProductService) GetAllBrandProducts() ([]models.BrandProduct, error) {
-	return bps.brandProductRepository.GetAllBrandProducts()
+func (bps *BrandProductService) GetAllBrandProducts(ctx context.Context) ([]models.BrandProduct, error) {
+	return bps.brandProductRepository.GetAllBrandProducts(ctx)
 }
 
-func (bps *BrandProductService) GetBrandProductByID(id int) (*models.BrandProduct, error) {
-	brandProduct, err := bps.brandProductRepository.GetBrandProductByID(id)
+func (bps *BrandProductService) GetBrandProductByID(ctx context.Context, id int) (*models.BrandProduct, error) {
+	brandProduct, err := bps.brandProductRepository.GetBrandProductByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +70,7 @@ func (bps *BrandProductService) GetBrandProductByID(id int) (*models.BrandProduc
 	return brandProduct, nil
 }
 
-func (bps *BrandProductService) UpdateBrandProduct(id int, brandProduct *models.BrandProduct) error {
+func (bps *BrandProductService) UpdateBrandProduct(ctx context.Context, id int, brandProduct *models.BrandProduct) error {
 	validate := helpers.InitValidator()
 
 	err := validate.Struct(brandProduct)
@@ -61,7 +79,7 @@ func (bps *BrandProductService) UpdateBrandProduct(id int, brandProduct *models.
 		return helpers.ValidationErrors{Messages: formatted}
 	}
 
-	category, err := bps.brandProductRepository.GetCategoryByID(brandProduct.CategoryID)
+	category, err := bps.brandProductRepository.GetCategoryByID(ctx, brandProduct.CategoryID)
 	if err != nil {
 		return err
 	}
@@ -70,18 +88,47 @@ func (bps *BrandProductService) UpdateBrandProduct(id int, brandProduct *models.
 		return repositories.ErrorCategoryNotFound
 	}
 
-	return bps.brandProductRepository.UpdateBrandProduct(id, brandProduct)
+	return bps.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := bps.brandProductRepository.GetBrandProductByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := bps.brandProductRepository.UpdateBrandProduct(ctx, id, brandProduct); err != nil {
+			return err
+		}
+		after, err := bps.brandProductRepository.GetBrandProductByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return bps.audit.Record(ctx, AuditEntry{
+			Action:     models.AuditActionUpdate,
+			EntityType: models.AuditEntityBrandProduct,
+			EntityID:   strconv.Itoa(id),
+			Before:     before,
+			After:      after,
+		})
+	})
 }
 
-func (bps *BrandProductService) DeleteBrandProduct(id int) error {
-	brandProduct, err := bps.brandProductRepository.GetBrandProductByID(id)
-	if err != nil {
-		return err
-	}
-
-	if brandProduct == nil {
-		return repositories.ErrorBrandProductNotFound
-	}
-
-	return bps.brandProductRepository.DeleteBrandProduct(id)
+func (bps *BrandProductService) DeleteBrandProduct(ctx context.Context, id int) error {
+	return bps.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		brandProduct, err := bps.brandProductRepository.GetBrandProductByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if brandProduct == nil {
+			return repositories.ErrorBrandProductNotFound
+		}
+
+		if err := bps.brandProductRepository.DeleteBrandProduct(ctx, id); err != nil {
+			return err
+		}
+		return bps.audit.Record(ctx, AuditEntry{
+			Action:     models.AuditActionDelete,
+			EntityType: models.AuditEntityBrandProduct,
+			EntityID:   strconv.Itoa(id),
+			Before:     brandProduct,
+		})
+	})
 }
@@ -4,51 +4,117 @@ import (
 	"contact-management/src/helpers"
 	"contact-management/src/models"
 	"contact-management/src/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
 )
 
 type CategoryService struct {
 	categoryRepo repositories.CategoryRepository
+	txManager    repositories.TxManager
+	audit        *AuditService
 }
 
-func NewCategoryService(categoryRepo repositories.CategoryRepository) *CategoryService {
+func NewCategoryService(categoryRepo repositories.CategoryRepository, txManager repositories.TxManager, audit *AuditService) *CategoryService {
 	return &CategoryService{
 		categoryRepo: categoryRepo,
+		txManager:    txManager,
+		audit:        audit,
 	}
 }
 
-func (cs *CategoryService) GetAllCategories() ([]*models.Category, error) {
-	categories, err := cs.categoryRepo.GetAllCategories()
+func (cs *CategoryService) GetAllCategories(ctx context.Context) ([]*models.Category, error) {
+	categories, err := cs.categoryRepo.GetAllCategories(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return categories, nil
 }
 
-func (cs *CategoryService) GetCategoryByID(id int) (*models.Category, error) {
+func (cs *CategoryService) GetCategoryByID(ctx context.Context, id int) (*models.Category, error) {
 
-	return cs.categoryRepo.GetCategoryByID(id)
+	return cs.categoryRepo.GetCategoryByID(ctx, id)
 }
 
-func (cs *CategoryService) CreateCategory(category *models.Category) error {
+func (cs *CategoryService) CreateCategory(ctx context.Context, category *models.Category) error {
 	validate := helpers.InitValidator()
 
 	err := validate.Struct(category)
 	if err != nil {
 		return err
 	}
-	return cs.categoryRepo.CreateCategory(category)
+	return cs.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := cs.categoryRepo.CreateCategory(ctx, category); err != nil {
+			return err
+		}
+		after, err := cs.findForAudit(ctx, category.CategoryID)
+		if err != nil {
+			return err
+		}
+		return cs.audit.Record(ctx, AuditEntry{
+			Action:     models.AuditActionCreate,
+			EntityType: models.AuditEntityCategory,
+			EntityID:   strconv.Itoa(category.CategoryID),
+			After:      after,
+		})
+	})
 }
 
-func (cs *CategoryService) UpdateCategory(category *models.Category, id int) error {
+func (cs *CategoryService) UpdateCategory(ctx context.Context, category *models.Category, id int) error {
 	validate := helpers.InitValidator()
 
 	err := validate.Struct(category)
 	if err != nil {
 		return err
 	}
-	return cs.categoryRepo.UpdateCategory(category, id)
+	return cs.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := cs.findForAudit(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := cs.categoryRepo.UpdateCategory(ctx, category, id); err != nil {
+			return err
+		}
+		after, err := cs.findForAudit(ctx, id)
+		if err != nil {
+			return err
+		}
+		return cs.audit.Record(ctx, AuditEntry{
+			Action:     models.AuditActionUpdate,
+			EntityType: models.AuditEntityCategory,
+			EntityID:   strconv.Itoa(id),
+			Before:     before,
+			After:      after,
+		})
+	})
 }
 
-func (cs *CategoryService) DeleteCategory(id int) error {
-	return cs.categoryRepo.DeleteCategory(id)
+func (cs *CategoryService) DeleteCategory(ctx context.Context, id int) error {
+	return cs.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := cs.findForAudit(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := cs.categoryRepo.DeleteCategory(ctx, id); err != nil {
+			return err
+		}
+		return cs.audit.Record(ctx, AuditEntry{
+			Action:     models.AuditActionDelete,
+			EntityType: models.AuditEntityCategory,
+			EntityID:   strconv.Itoa(id),
+			Before:     before,
+		})
+	})
+}
+
+// findForAudit membaca kategori untuk isi before/after audit log. Kategori yang tidak
+// ada dilaporkan sebagai ErrorCategoryNotFound, sama seperti UpdateCategory dan
+// DeleteCategory di repository.
+func (cs *CategoryService) findForAudit(ctx context.Context, id int) (*models.Category, error) {
+	category, err := cs.categoryRepo.GetCategoryByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repositories.ErrorCategoryNotFound
+	}
+	return category, err
 }
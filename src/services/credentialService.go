@@ -0,0 +1,111 @@
+package services
+
+import (
+	"contact-management/src/helpers"
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"contact-management/src/utils"
+	"context"
+	"errors"
+	"strings"
+)
+
+var ErrCredentialNotDelivered = errors.New("credential belum dikirim untuk order ini")
+
+type CredentialService struct {
+	credentialRepository repositories.CredentialRepository
+	orderRepository      repositories.OrderRepository
+	encrypter            *utils.Encrypter
+}
+
+func NewCredentialService(credentialRepository repositories.CredentialRepository, orderRepository repositories.OrderRepository, encrypter *utils.Encrypter) *CredentialService {
+	return &CredentialService{
+		credentialRepository: credentialRepository,
+		orderRepository:      orderRepository,
+		encrypter:            encrypter,
+	}
+}
+
+// UploadCredentials mengenkripsi lalu menyimpan credential untuk sebuah produk.
+// Stok produk bertambah sesuai jumlah credential yang diunggah.
+func (cs *CredentialService) UploadCredentials(ctx context.Context, productID int, request *models.CredentialUploadRequest) (*models.CredentialUploadResponse, error) {
+	validate := helpers.Validator()
+
+	err := validate.Struct(request)
+	if err != nil {
+		formatted := helpers.FormatValidationError(err)
+		return nil, helpers.ValidationErrors{Messages: formatted}
+	}
+
+	if _, err := cs.orderRepository.GetProductByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	ciphertexts := make([]string, len(request.Credentials))
+	for i, secret := range request.Credentials {
+		ciphertexts[i], err = cs.encrypter.Encrypt(secret)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cs.credentialRepository.CreateCredentials(ctx, productID, ciphertexts); err != nil {
+		return nil, err
+	}
+
+	available, err := cs.credentialRepository.CountAvailableCredentials(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CredentialUploadResponse{
+		ProductID: productID,
+		Uploaded:  len(ciphertexts),
+		Available: available,
+	}, nil
+}
+
+// DeliverCredential memberikan satu credential yang belum terpakai kepada order.
+func (cs *CredentialService) DeliverCredential(ctx context.Context, order *models.Order) (*models.Credential, error) {
+	return cs.credentialRepository.AssignCredential(ctx, order.OrderID, order.ProductID)
+}
+
+// LookupCredential mengembalikan credential milik pembeli berdasarkan kode order dan email.
+// Kode order yang tidak cocok dengan email diperlakukan sama seperti order yang tidak ada.
+func (cs *CredentialService) LookupCredential(ctx context.Context, request *models.CredentialLookupRequest) (*models.Credential, error) {
+	validate := helpers.Validator()
+
+	err := validate.Struct(request)
+	if err != nil {
+		formatted := helpers.FormatValidationError(err)
+		return nil, helpers.ValidationErrors{Messages: formatted}
+	}
+
+	order, err := cs.orderRepository.GetOrderByCode(ctx, request.OrderCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(strings.TrimSpace(order.Email), strings.TrimSpace(request.Email)) {
+		return nil, repositories.ErrorOrderNotFound
+	}
+
+	if order.Status != models.OrderStatusFulfilled {
+		return nil, ErrCredentialNotDelivered
+	}
+
+	credential, err := cs.credentialRepository.GetCredentialByOrderID(ctx, order.OrderID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrorCredentialNotFound) {
+			return nil, ErrCredentialNotDelivered
+		}
+		return nil, err
+	}
+
+	credential.Secret, err = cs.encrypter.Decrypt(credential.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	return credential, nil
+}
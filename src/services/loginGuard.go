@@ -0,0 +1,229 @@
+package services
+
+import (
+	"contact-management/src/config"
+	"contact-management/src/models"
+	"contact-management/src/utils"
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Lingkup LoginLockedError.
+const (
+	LockScopeUsername = "username"
+	LockScopeIP       = "ip"
+)
+
+// LoginLockedError dikembalikan ketika login ditolak karena akun atau IP asal
+// sedang dikunci setelah terlalu banyak percobaan gagal.
+type LoginLockedError struct {
+	Scope      string
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	if e.Scope == LockScopeIP {
+		return "terlalu banyak percobaan login gagal dari alamat ini"
+	}
+	return "akun dikunci sementara karena terlalu banyak percobaan login gagal"
+}
+
+// LoginGuard melindungi login dari brute-force: menghitung login gagal per username dan
+// per IP, memperlambat percobaan berikutnya secara progresif, dan mengunci sementara
+// username atau IP yang melewati batas. Login berhasil, gagal dan terkunci untuk username
+// yang terdaftar dicatat ke audit log dengan username tersebut sebagai actor; kegagalan
+// untuk username yang tidak terdaftar hanya dicatat ke logger agar audit log tidak bisa
+// dibanjiri username sembarang.
+type LoginGuard struct {
+	store  utils.LoginAttemptStore
+	cfg    config.LoginConfig
+	audit  *AuditService
+	logger *logrus.Logger
+}
+
+func NewLoginGuard(store utils.LoginAttemptStore, cfg config.LoginConfig, audit *AuditService, logger *logrus.Logger) *LoginGuard {
+	return &LoginGuard{store: store, cfg: cfg, audit: audit, logger: logger}
+}
+
+// record mencatat kejadian login ke audit log. After berisi hitungan gagal saat itu.
+func (g *LoginGuard) record(ctx context.Context, action, username string, after any) error {
+	return g.audit.Record(ctx, AuditEntry{
+		Actor:      username,
+		Action:     action,
+		EntityType: models.AuditEntityUser,
+		EntityID:   username,
+		After:      after,
+	})
+}
+
+// maxAttemptedUsernameLength mengikuti panjang kolom users.username.
+const maxAttemptedUsernameLength = 50
+
+// attemptedUsername membuang karakter kontrol dari username yang dicoba dan memotongnya
+// ke maxAttemptedUsernameLength sebelum dipakai sebagai kunci hitungan atau dicatat.
+func attemptedUsername(username string) string {
+	username = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, username)
+
+	if runes := []rune(username); len(runes) > maxAttemptedUsernameLength {
+		return string(runes[:maxAttemptedUsernameLength])
+	}
+	return username
+}
+
+func usernameAttemptKey(username string) string {
+	return "user:" + username
+}
+
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check menolak login dengan *LoginLockedError bila username atau IP sedang dikunci,
+// lalu menunggu jeda progresif sesuai jumlah kegagalan sebelumnya.
+func (g *LoginGuard) Check(ctx context.Context, username, ip string) error {
+	username = attemptedUsername(username)
+	for _, lock := range []struct{ scope, key string }{
+		{LockScopeUsername, usernameAttemptKey(username)},
+		{LockScopeIP, ipAttemptKey(ip)},
+	} {
+		remaining, err := g.store.LockedFor(ctx, lock.key)
+		if err != nil {
+			return err
+		}
+		if remaining > 0 {
+			return &LoginLockedError{Scope: lock.scope, RetryAfter: remaining}
+		}
+	}
+
+	failures, err := g.store.Failures(ctx, usernameAttemptKey(username))
+	if err != nil {
+		return err
+	}
+	ipFailures, err := g.store.Failures(ctx, ipAttemptKey(ip))
+	if err != nil {
+		return err
+	}
+	return g.wait(ctx, g.delay(max(failures, ipFailures)))
+}
+
+// delay adalah DelayBase yang berlipat dua untuk setiap kegagalan setelah yang pertama,
+// dibatasi DelayMax.
+func (g *LoginGuard) delay(failures int) time.Duration {
+	if failures <= 0 || g.cfg.DelayBase <= 0 {
+		return 0
+	}
+	delay := g.cfg.DelayBase
+	for i := 1; i < failures && delay < g.cfg.DelayMax; i++ {
+		delay *= 2
+	}
+	return min(delay, g.cfg.DelayMax)
+}
+
+func (g *LoginGuard) wait(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Failed mencatat login gagal. Known menandai apakah username terdaftar; hanya kegagalan
+// untuk username terdaftar yang ditulis ke audit log. Bila kegagalan ini membuat username
+// atau IP melewati batasnya, keduanya dikunci dan *LoginLockedError dikembalikan.
+func (g *LoginGuard) Failed(ctx context.Context, username, ip string, known bool) error {
+	username = attemptedUsername(username)
+
+	failures, err := g.store.AddFailure(ctx, usernameAttemptKey(username), g.cfg.FailureWindow)
+	if err != nil {
+		return err
+	}
+	ipFailures, err := g.store.AddFailure(ctx, ipAttemptKey(ip), g.cfg.FailureWindow)
+	if err != nil {
+		return err
+	}
+
+	if known {
+		err = g.record(ctx, models.AuditActionLoginFailed, username, map[string]int{
+			"failures":    failures,
+			"ip_failures": ipFailures,
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		g.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"event":       "login_failed",
+			"username":    username,
+			"ip":          ip,
+			"failures":    failures,
+			"ip_failures": ipFailures,
+		}).Info("Login gagal untuk username yang tidak terdaftar")
+	}
+
+	if g.cfg.MaxFailures > 0 && failures >= g.cfg.MaxFailures {
+		return g.lock(ctx, LockScopeUsername, usernameAttemptKey(username), username, ip, known)
+	}
+	if g.cfg.MaxFailuresPerIP > 0 && ipFailures >= g.cfg.MaxFailuresPerIP {
+		return g.lock(ctx, LockScopeIP, ipAttemptKey(ip), username, ip, known)
+	}
+	return nil
+}
+
+// lock mengunci key dan mencatatnya ke logger; audit log hanya ditulis bila username terdaftar.
+func (g *LoginGuard) lock(ctx context.Context, scope, key, username, ip string, known bool) error {
+	if err := g.store.Lock(ctx, key, g.cfg.LockoutDuration); err != nil {
+		return err
+	}
+
+	g.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"event":    "login_locked",
+		"scope":    scope,
+		"username": username,
+		"ip":       ip,
+		"duration": g.cfg.LockoutDuration.String(),
+	}).Warn("Login dikunci sementara")
+
+	if known {
+		err := g.record(ctx, models.AuditActionLoginLocked, username, map[string]string{
+			"scope":    scope,
+			"duration": g.cfg.LockoutDuration.String(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return &LoginLockedError{Scope: scope, RetryAfter: g.cfg.LockoutDuration}
+}
+
+// Succeeded mencatat login berhasil dan mengosongkan hitungan gagal username. Hitungan
+// IP sengaja dibiarkan agar penyerang tidak bisa mengosongkannya dengan login ke akunnya
+// sendiri di sela percobaan.
+func (g *LoginGuard) Succeeded(ctx context.Context, username string) error {
+	if err := g.record(ctx, models.AuditActionLoginSucceeded, username, nil); err != nil {
+		return err
+	}
+
+	return g.store.Reset(ctx, usernameAttemptKey(username))
+}
+
+// Unlock membuka kunci username dan mengosongkan hitungan gagalnya.
+func (g *LoginGuard) Unlock(ctx context.Context, username string) error {
+	return g.store.Reset(ctx, usernameAttemptKey(username))
+}
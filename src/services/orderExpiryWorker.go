@@ -0,0 +1,58 @@
+package services
+
+import (
+	"contact-management/src/config"
+	"context"
+	"time"
+)
+
+// OrderExpiryWorker secara berkala meng-expire order pending yang melewati
+// ORDER_RESERVATION_TTL agar stok yang direservasi kembali tersedia.
+type OrderExpiryWorker struct {
+	orderService *OrderService
+	ttl          time.Duration
+	interval     time.Duration
+	stop         chan struct{}
+	done         chan struct{}
+}
+
+func NewOrderExpiryWorker(orderService *OrderService, cfg config.OrderConfig) *OrderExpiryWorker {
+	return &OrderExpiryWorker{
+		orderService: orderService,
+		ttl:          cfg.ReservationTTL,
+		interval:     cfg.ExpiryInterval,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+func (w *OrderExpiryWorker) Start() {
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				expired, err := w.orderService.ExpireStaleOrders(context.Background(), w.ttl)
+				if err != nil {
+					w.orderService.logger.Error("Gagal meng-expire order: ", err)
+					continue
+				}
+				if expired > 0 {
+					w.orderService.logger.Infof("%d order pending di-expire", expired)
+				}
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop menghentikan worker dan menunggu iterasi yang sedang berjalan selesai.
+func (w *OrderExpiryWorker) Stop() {
+	close(w.stop)
+	<-w.done
+}
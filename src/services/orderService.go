@@ -0,0 +1,330 @@
+package services
+
+import (
+	"contact-management/src/gateways"
+	"contact-management/src/helpers"
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"contact-management/src/utils"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var ErrUnknownOrderStatus = errors.New("status order tidak dikenal")
+
+var ErrOrderStatusChanged = errors.New("status order telah berubah, silakan coba lagi")
+
+// InvalidTransitionError dikembalikan ketika perubahan status order
+// tidak diizinkan oleh state machine.
+type InvalidTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("status order tidak dapat diubah dari %s ke %s", e.From, e.To)
+}
+
+var orderTransitions = map[string][]string{
+	models.OrderStatusPending: {models.OrderStatusPaid, models.OrderStatusExpired, models.OrderStatusCancelled},
+	models.OrderStatusPaid:    {models.OrderStatusFulfilled, models.OrderStatusRefunded},
+}
+
+var orderStatuses = []string{
+	models.OrderStatusPending,
+	models.OrderStatusPaid,
+	models.OrderStatusFulfilled,
+	models.OrderStatusExpired,
+	models.OrderStatusCancelled,
+	models.OrderStatusRefunded,
+}
+
+func isKnownOrderStatus(status string) bool {
+	for _, s := range orderStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// releasesReservation melaporkan apakah transisi membatalkan order yang stoknya
+// masih direservasi. Transisi ke paid justru mengunci reservasi tersebut secara permanen.
+func releasesReservation(from, to string) bool {
+	return from == models.OrderStatusPending && (to == models.OrderStatusExpired || to == models.OrderStatusCancelled)
+}
+
+// CanTransition melaporkan apakah order boleh berpindah dari status from ke status to.
+func CanTransition(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+type OrderService struct {
+	orderRepository   repositories.OrderRepository
+	paymentRepository repositories.PaymentRepository
+	credentialService *CredentialService
+	txManager         repositories.TxManager
+	gateway           gateways.PaymentGateway
+	logger            *logrus.Logger
+}
+
+func NewOrderService(orderRepository repositories.OrderRepository, paymentRepository repositories.PaymentRepository, credentialService *CredentialService, txManager repositories.TxManager, gateway gateways.PaymentGateway, logger *logrus.Logger) *OrderService {
+	return &OrderService{
+		orderRepository:   orderRepository,
+		paymentRepository: paymentRepository,
+		credentialService: credentialService,
+		txManager:         txManager,
+		gateway:           gateway,
+		logger:            logger,
+	}
+}
+
+func (o *OrderService) CreateOrder(ctx context.Context, order *models.Order) error {
+	validate := helpers.Validator()
+
+	err := validate.Struct(order)
+	if err != nil {
+		formatted := helpers.FormatValidationError(err)
+		return helpers.ValidationErrors{Messages: formatted}
+	}
+
+	code, err := utils.RandomHex(6)
+	if err != nil {
+		return err
+	}
+
+	// repository sekaligus memvalidasi produk dan mereservasi satu unit stok
+	order.OrderCode = "ORD-" + strings.ToUpper(code)
+	order.Status = models.OrderStatusPending
+	return o.orderRepository.CreateOrder(ctx, order)
+}
+
+func (o *OrderService) GetOrders(ctx context.Context, filter models.OrderFilter) (*models.OrderResponsePagination, error) {
+	if filter.Status != "" && !isKnownOrderStatus(filter.Status) {
+		return nil, ErrUnknownOrderStatus
+	}
+
+	orders, total, err := o.orderRepository.GetOrders(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OrderResponsePagination{
+		Orders: orders,
+		Total:  total,
+		Page:   filter.Page,
+		Limit:  filter.Limit,
+	}, nil
+}
+
+func (o *OrderService) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
+	return o.orderRepository.GetOrderByID(ctx, id)
+}
+
+// TransitionOrder memindahkan order ke status baru jika transisinya legal. Perubahan
+// status, pelepasan stok beserta pembatalan payment pending-nya, dan pengiriman
+// credential untuk order yang baru dibayar berjalan dalam satu transaksi; invoice
+// payment yang dibatalkan baru dibatalkan di gateway setelah transaksi di-commit.
+func (o *OrderService) TransitionOrder(ctx context.Context, id int, to string) (*models.Order, error) {
+	if !isKnownOrderStatus(to) {
+		return nil, ErrUnknownOrderStatus
+	}
+
+	var order *models.Order
+	err := o.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = o.transition(ctx, id, to)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (o *OrderService) transition(ctx context.Context, id int, to string) (*models.Order, error) {
+	order, err := o.orderRepository.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !CanTransition(order.Status, to) {
+		return nil, &InvalidTransitionError{From: order.Status, To: to}
+	}
+
+	var row int64
+	if releasesReservation(order.Status, to) {
+		row, err = o.orderRepository.ReleaseOrder(ctx, id, order.Status, to)
+	} else {
+		row, err = o.orderRepository.UpdateOrderStatus(ctx, id, order.Status, to)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if row == 0 {
+		return nil, ErrOrderStatusChanged
+	}
+
+	if releasesReservation(order.Status, to) {
+		if err := o.cancelPendingPayments(ctx, order.OrderID); err != nil {
+			return nil, err
+		}
+	}
+
+	order.Status = to
+
+	if to == models.OrderStatusPaid {
+		return o.fulfil(ctx, order)
+	}
+	return order, nil
+}
+
+// cancelPendingPayments membatalkan payment pending order yang reservasinya dilepas agar
+// order tersebut tidak bisa dibayar lagi. Invoice-nya baru dibatalkan di payment gateway
+// setelah transaksi di-commit; invoice yang gagal dibatalkan hanya dicatat ke log karena
+// pembayaran yang tetap masuk ditangani oleh ReclaimPaidOrder.
+func (o *OrderService) cancelPendingPayments(ctx context.Context, orderID int) error {
+	payments, err := o.paymentRepository.GetPendingPaymentsByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, payment := range payments {
+		if _, err := o.paymentRepository.UpdatePaymentStatus(ctx, payment.PaymentID, models.PaymentStatusPending, models.PaymentStatusCancelled); err != nil {
+			return err
+		}
+		externalID := payment.ExternalID
+		repositories.AfterCommit(ctx, func(ctx context.Context) {
+			o.cancelInvoice(context.WithoutCancel(ctx), orderID, externalID)
+		})
+	}
+	return nil
+}
+
+func (o *OrderService) cancelInvoice(ctx context.Context, orderID int, externalID string) {
+	err := o.gateway.CancelInvoice(ctx, externalID)
+	if err != nil && !errors.Is(err, gateways.ErrInvoiceNotFound) {
+		o.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"order_id":    orderID,
+			"external_id": externalID,
+			"provider":    o.gateway.Name(),
+		}).Error("Gagal membatalkan invoice di payment gateway: ", err)
+	}
+}
+
+// ReclaimPaidOrder menghidupkan lagi order expired atau cancelled yang ternyata tetap
+// dibayar: satu unit stok direservasi ulang, order ditandai paid, lalu credential dikirim.
+// repositories.ErrorOutOfStock berarti order tidak dapat dipenuhi dan statusnya tidak berubah.
+func (o *OrderService) ReclaimPaidOrder(ctx context.Context, id int) (*models.Order, error) {
+	var order *models.Order
+	err := o.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = o.orderRepository.GetOrderByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if !releasesReservation(models.OrderStatusPending, order.Status) {
+			return &InvalidTransitionError{From: order.Status, To: models.OrderStatusPaid}
+		}
+
+		row, err := o.orderRepository.ReserveOrder(ctx, id, order.Status, models.OrderStatusPaid)
+		if err != nil {
+			return err
+		}
+		if row == 0 {
+			return ErrOrderStatusChanged
+		}
+
+		order.Status = models.OrderStatusPaid
+		order, err = o.fulfil(ctx, order)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// DeliverOrder mencoba lagi pengiriman credential untuk order yang sudah dibayar,
+// misalnya setelah admin menambah credential yang sebelumnya habis. Credential
+// hanya terpakai bila order berhasil ditandai fulfilled.
+func (o *OrderService) DeliverOrder(ctx context.Context, id int) (*models.Order, error) {
+	var order *models.Order
+	err := o.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = o.orderRepository.GetOrderByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if order.Status != models.OrderStatusPaid {
+			return &InvalidTransitionError{From: order.Status, To: models.OrderStatusFulfilled}
+		}
+
+		if _, err := o.credentialService.DeliverCredential(ctx, order); err != nil {
+			return err
+		}
+
+		order, err = o.transition(ctx, order.OrderID, models.OrderStatusFulfilled)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// fulfil mengirim credential ke order yang baru dibayar lalu menandainya fulfilled.
+// Jika credential habis order tetap paid sampai admin memanggil DeliverOrder; error lain
+// dikembalikan agar transaksi pembayaran dibatalkan atau dicoba ulang.
+func (o *OrderService) fulfil(ctx context.Context, order *models.Order) (*models.Order, error) {
+	if _, err := o.credentialService.DeliverCredential(ctx, order); err != nil {
+		if !errors.Is(err, repositories.ErrorCredentialUnavailable) {
+			return nil, err
+		}
+		o.logger.WithContext(ctx).WithField("order_id", order.OrderID).Warn("Credential belum dapat dikirim: ", err)
+		return order, nil
+	}
+
+	return o.transition(ctx, order.OrderID, models.OrderStatusFulfilled)
+}
+
+// ExpireStaleOrders mengubah order pending yang lebih tua dari ttl menjadi expired,
+// melepas reservasi stoknya, dan membatalkan invoice-nya. Order yang statusnya sudah
+// berubah dilewati; order yang gagal di-expire dicoba lagi pada putaran berikutnya
+// tanpa menahan order lain.
+func (o *OrderService) ExpireStaleOrders(ctx context.Context, ttl time.Duration) (int, error) {
+	ids, err := o.orderRepository.GetStalePendingOrderIDs(ctx, ttl)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	var errs []error
+	for _, id := range ids {
+		_, err := o.TransitionOrder(ctx, id, models.OrderStatusExpired)
+		if err != nil {
+			var transitionErr *InvalidTransitionError
+			if errors.As(err, &transitionErr) || errors.Is(err, ErrOrderStatusChanged) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("order %d: %w", id, err))
+			continue
+		}
+		expired++
+	}
+
+	return expired, errors.Join(errs...)
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"contact-management/src/helpers"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// bcryptMaxLength adalah panjang maksimal input bcrypt; byte setelahnya diabaikan.
+const bcryptMaxLength = 72
+
+// validatePassword menerapkan kebijakan password baru: minimal minLength karakter, paling
+// banyak 72 byte, memuat huruf dan angka, dan tidak sama dengan username. Pelanggaran
+// dilaporkan sebagai ValidationErrors pada field.
+func validatePassword(field, password, username string, minLength int) error {
+	var message string
+	switch {
+	case len([]rune(password)) < minLength:
+		message = fmt.Sprintf("Password minimal %d karakter", minLength)
+	case len(password) > bcryptMaxLength:
+		message = fmt.Sprintf("Password maksimal %d byte", bcryptMaxLength)
+	case !strings.ContainsFunc(password, unicode.IsLetter) || !strings.ContainsFunc(password, unicode.IsDigit):
+		message = "Password harus memuat huruf dan angka"
+	case strings.EqualFold(password, username):
+		message = "Password tidak boleh sama dengan username"
+	default:
+		return nil
+	}
+	return helpers.ValidationErrors{Messages: map[string]string{field: message}}
+}
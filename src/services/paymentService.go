@@ -0,0 +1,301 @@
+package services
+
+import (
+	"contact-management/src/gateways"
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"contact-management/src/utils"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var ErrOrderNotPayable = errors.New("order tidak dalam status menunggu pembayaran")
+
+var ErrPaymentNotCancellable = errors.New("payment tidak dapat dibatalkan")
+
+// webhookOrderStatus memetakan status akhir payment ke status order.
+var webhookOrderStatus = map[string]string{
+	models.PaymentStatusPaid:    models.OrderStatusPaid,
+	models.PaymentStatusExpired: models.OrderStatusExpired,
+	models.PaymentStatusFailed:  models.OrderStatusCancelled,
+}
+
+type PaymentService struct {
+	paymentRepository repositories.PaymentRepository
+	orderRepository   repositories.OrderRepository
+	orderService      *OrderService
+	txManager         repositories.TxManager
+	gateway           gateways.PaymentGateway
+	reservationTTL    time.Duration
+	logger            *logrus.Logger
+}
+
+func NewPaymentService(paymentRepository repositories.PaymentRepository, orderRepository repositories.OrderRepository, orderService *OrderService, txManager repositories.TxManager, gateway gateways.PaymentGateway, reservationTTL time.Duration, logger *logrus.Logger) *PaymentService {
+	return &PaymentService{
+		paymentRepository: paymentRepository,
+		orderRepository:   orderRepository,
+		orderService:      orderService,
+		txManager:         txManager,
+		gateway:           gateway,
+		reservationTTL:    reservationTTL,
+		logger:            logger,
+	}
+}
+
+// CreatePayment membuat invoice di payment gateway untuk order yang masih pending
+// lalu menyimpannya sebagai baris payments. Jika order sudah punya payment pending,
+// payment tersebut dikembalikan apa adanya. Invoice tidak pernah berlaku lebih lama
+// dari sisa reservasi stok order.
+//
+// Baris order dikunci selama pemeriksaan dan pembuatan invoice sehingga request yang
+// bersamaan untuk order yang sama menunggu lalu mendapat payment yang sama.
+func (ps *PaymentService) CreatePayment(ctx context.Context, orderID int) (*models.Payment, error) {
+	var payment *models.Payment
+	var invoices []string
+	err := ps.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		payment = nil
+
+		order, err := ps.orderRepository.LockOrderByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		if order.Status != models.OrderStatusPending {
+			return ErrOrderNotPayable
+		}
+
+		var remaining time.Duration
+		if ps.reservationTTL > 0 {
+			remaining = time.Until(order.CreatedAt.Add(ps.reservationTTL))
+			if remaining < time.Second {
+				return ErrOrderNotPayable
+			}
+		}
+
+		existing, err := ps.paymentRepository.GetPendingPaymentByOrderID(ctx, order.OrderID)
+		if err == nil {
+			payment = existing
+			return nil
+		}
+		if !errors.Is(err, repositories.ErrorPaymentNotFound) {
+			return err
+		}
+
+		product, err := ps.orderRepository.GetProductByID(ctx, order.ProductID)
+		if err != nil {
+			return err
+		}
+
+		suffix, err := utils.RandomHex(8)
+		if err != nil {
+			return err
+		}
+		externalID := fmt.Sprintf("order-%d-%s", order.OrderID, suffix)
+
+		invoice, err := ps.gateway.CreateInvoice(ctx, gateways.CreateInvoiceRequest{
+			ExternalID:  externalID,
+			Amount:      product.Price,
+			Name:        order.Name,
+			Email:       order.Email,
+			Phone:       order.Phone,
+			Method:      order.Method,
+			Description: product.Name,
+			MaxDuration: remaining,
+		})
+		if err != nil {
+			return &gateways.GatewayError{Provider: ps.gateway.Name(), Err: err}
+		}
+		invoices = append(invoices, invoice.ExternalID)
+
+		payment = &models.Payment{
+			ProductID:  product.ProductID,
+			OrderID:    order.OrderID,
+			Amount:     product.Price,
+			Name:       order.Name,
+			Email:      order.Email,
+			Phone:      order.Phone,
+			Method:     order.Method,
+			Status:     models.PaymentStatusPending,
+			ExternalID: invoice.ExternalID,
+			PaymentURL: invoice.PaymentURL,
+		}
+		return ps.paymentRepository.CreatePayment(ctx, payment)
+	})
+
+	// invoice yang sudah terbuat di provider tetapi tidak tercatat, karena transaksinya gagal
+	// atau diulang, dibatalkan agar tidak bisa dibayar; tetap dijalankan meskipun request
+	// sudah habis waktu atau dibatalkan
+	for _, externalID := range invoices {
+		if err == nil && externalID == payment.ExternalID {
+			continue
+		}
+		ps.gateway.CancelInvoice(context.WithoutCancel(ctx), externalID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (ps *PaymentService) GetPaymentByID(ctx context.Context, id int) (*models.Payment, error) {
+	return ps.paymentRepository.GetPaymentByID(ctx, id)
+}
+
+func (ps *PaymentService) CancelPayment(ctx context.Context, id int) (*models.Payment, error) {
+	payment, err := ps.paymentRepository.GetPaymentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.Status != models.PaymentStatusPending {
+		return nil, ErrPaymentNotCancellable
+	}
+
+	if err := ps.gateway.CancelInvoice(ctx, payment.ExternalID); err != nil {
+		return nil, &gateways.GatewayError{Provider: ps.gateway.Name(), Err: err}
+	}
+
+	row, err := ps.paymentRepository.UpdatePaymentStatus(ctx, payment.PaymentID, models.PaymentStatusPending, models.PaymentStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	if row == 0 {
+		return nil, ErrPaymentNotCancellable
+	}
+
+	payment.Status = models.PaymentStatusCancelled
+	return payment, nil
+}
+
+// HandleWebhook memproses callback provider. Event yang sudah pernah diproses,
+// datang terlambat, atau tidak mengubah status payment diabaikan sehingga
+// callback yang dikirim ulang tidak pernah diterapkan dua kali.
+// Nilai kembalian true berarti event ini mengubah status payment.
+func (ps *PaymentService) HandleWebhook(ctx context.Context, provider string, header http.Header, body []byte) (bool, error) {
+	if provider != ps.gateway.Name() {
+		return false, gateways.ErrUnknownProvider
+	}
+
+	event, err := ps.gateway.ParseWebhook(header, body)
+	if err != nil {
+		return false, err
+	}
+
+	// status payment, status order beserta stok dan credential-nya, serta catatan event
+	// disimpan bersama; bila salah satu gagal provider dapat mengirim ulang callback
+	var applied bool
+	err = ps.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		applied, err = ps.applyWebhook(ctx, provider, event)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+func (ps *PaymentService) applyWebhook(ctx context.Context, provider string, event *gateways.WebhookEvent) (bool, error) {
+	processed, err := ps.paymentRepository.HasWebhookEvent(ctx, provider, event.EventID)
+	if err != nil {
+		return false, err
+	}
+	if processed {
+		return false, nil
+	}
+
+	payment, err := ps.paymentRepository.GetPaymentByExternalID(ctx, event.ExternalID)
+	if err != nil {
+		return false, err
+	}
+
+	applied := false
+	orderStatus, final := webhookOrderStatus[event.Status]
+	if final && canApplyPaymentStatus(payment.Status, event.Status) {
+		row, err := ps.paymentRepository.UpdatePaymentStatus(ctx, payment.PaymentID, payment.Status, event.Status)
+		if err != nil {
+			return false, err
+		}
+		applied = row > 0
+	}
+
+	if applied {
+		if _, err := ps.orderService.TransitionOrder(ctx, payment.OrderID, orderStatus); err != nil {
+			var transitionErr *InvalidTransitionError
+			if !errors.As(err, &transitionErr) {
+				return false, err
+			}
+			if orderStatus == models.OrderStatusPaid {
+				if err := ps.reclaimPaidOrder(ctx, payment, event); err != nil {
+					return false, err
+				}
+			} else {
+				ps.logger.WithContext(ctx).WithFields(logrus.Fields{
+					"order_id":   payment.OrderID,
+					"payment_id": payment.PaymentID,
+					"event_id":   event.EventID,
+				}).Warn("Webhook payment tidak dapat mengubah status order: ", err)
+			}
+		}
+	} else {
+		ps.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"payment_id":     payment.PaymentID,
+			"payment_status": payment.Status,
+			"event_id":       event.EventID,
+			"event_status":   event.Status,
+		}).Info("Webhook payment diabaikan")
+	}
+
+	if _, err := ps.paymentRepository.RecordWebhookEvent(ctx, provider, event.EventID, payment.PaymentID, event.Status); err != nil {
+		return false, err
+	}
+
+	return applied, nil
+}
+
+// canApplyPaymentStatus melaporkan apakah status dari webhook boleh diterapkan ke payment.
+// Payment pending menerima semua status akhir. Payment yang sudah kita batalkan atau
+// expired tetap menerima paid karena uangnya benar-benar sudah diterima provider.
+func canApplyPaymentStatus(from, to string) bool {
+	switch from {
+	case models.PaymentStatusPending:
+		return true
+	case models.PaymentStatusCancelled, models.PaymentStatusExpired, models.PaymentStatusFailed:
+		return to == models.PaymentStatusPaid
+	}
+	return false
+}
+
+// reclaimPaidOrder menangani pembayaran yang masuk setelah order expired atau dibatalkan.
+// Order dihidupkan lagi bila stok masih ada; bila tidak, payment ditandai refund_required
+// dan dicatat sebagai error agar admin mengembalikan dananya.
+func (ps *PaymentService) reclaimPaidOrder(ctx context.Context, payment *models.Payment, event *gateways.WebhookEvent) error {
+	entry := ps.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"order_id":   payment.OrderID,
+		"payment_id": payment.PaymentID,
+		"event_id":   event.EventID,
+	})
+
+	_, err := ps.orderService.ReclaimPaidOrder(ctx, payment.OrderID)
+	if err == nil {
+		entry.Warn("Order dibayar setelah reservasinya dilepas, stok direservasi ulang")
+		return nil
+	}
+
+	var transitionErr *InvalidTransitionError
+	if !errors.Is(err, repositories.ErrorOutOfStock) && !errors.As(err, &transitionErr) {
+		return err
+	}
+
+	if _, err := ps.paymentRepository.UpdatePaymentStatus(ctx, payment.PaymentID, models.PaymentStatusPaid, models.PaymentStatusRefundRequired); err != nil {
+		return err
+	}
+	entry.Error("Payment diterima untuk order yang tidak dapat dipenuhi, dana perlu dikembalikan: ", err)
+	return nil
+}
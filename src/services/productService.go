@@ -0,0 +1,115 @@
+package services
+
+import (
+	"contact-management/src/helpers"
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"context"
+	"strconv"
+)
+
+type ProductService struct {
+	productRepository repositories.ProductRepository
+	txManager         repositories.TxManager
+	audit             *AuditService
+}
+
+func NewProductService(productRepository repositories.ProductRepository, txManager repositories.TxManager, audit *AuditService) *ProductService {
+	return &ProductService{productRepository: productRepository, txManager: txManager, audit: audit}
+}
+
+func (ps *ProductService) validate(ctx context.Context, product *models.Product) error {
+	validate := helpers.Validator()
+
+	err := validate.Struct(product)
+	if err != nil {
+		formatted := helpers.FormatValidationError(err)
+		return helpers.ValidationErrors{Messages: formatted}
+	}
+
+	// brand_product_id harus mengarah ke brand product yang belum dihapus
+	_, err = ps.productRepository.GetBrandProductByID(ctx, product.BrandProductID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ps *ProductService) CreateProduct(ctx context.Context, product *models.Product) error {
+	if err := ps.validate(ctx, product); err != nil {
+		return err
+	}
+
+	return ps.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := ps.productRepository.CreateProduct(ctx, product); err != nil {
+			return err
+		}
+		after, err := ps.productRepository.GetProductByID(ctx, product.ProductID)
+		if err != nil {
+			return err
+		}
+		return ps.audit.Record(ctx, AuditEntry{
+			Action:     models.AuditActionCreate,
+			EntityType: models.AuditEntityProduct,
+			EntityID:   strconv.Itoa(product.ProductID),
+			After:      after,
+		})
+	})
+}
+
+func (ps *ProductService) GetAllProducts(ctx context.Context) ([]models.Product, error) {
+	return ps.productRepository.GetAllProducts(ctx)
+}
+
+func (ps *ProductService) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
+	return ps.productRepository.GetProductByID(ctx, id)
+}
+
+func (ps *ProductService) UpdateProduct(ctx context.Context, id int, product *models.Product) error {
+	return ps.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := ps.productRepository.GetProductByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := ps.validate(ctx, product); err != nil {
+			return err
+		}
+
+		if err := ps.productRepository.UpdateProduct(ctx, id, product); err != nil {
+			return err
+		}
+		after, err := ps.productRepository.GetProductByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		// stock di body diabaikan, jadi kembalikan produk seperti yang tersimpan
+		*product = *after
+		return ps.audit.Record(ctx, AuditEntry{
+			Action:     models.AuditActionUpdate,
+			EntityType: models.AuditEntityProduct,
+			EntityID:   strconv.Itoa(id),
+			Before:     before,
+			After:      after,
+		})
+	})
+}
+
+func (ps *ProductService) DeleteProduct(ctx context.Context, id int) error {
+	return ps.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := ps.productRepository.GetProductByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := ps.productRepository.DeleteProduct(ctx, id); err != nil {
+			return err
+		}
+		return ps.audit.Record(ctx, AuditEntry{
+			Action:     models.AuditActionDelete,
+			EntityType: models.AuditEntityProduct,
+			EntityID:   strconv.Itoa(id),
+			Before:     before,
+		})
+	})
+}
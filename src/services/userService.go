@@ -1,23 +1,118 @@
 package services
 
 import (
+	"contact-management/src/config"
 	"contact-management/src/helpers"
 	"contact-management/src/models"
 	"contact-management/src/repositories"
+	"contact-management/src/utils"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserService struct {
-	userRepo repositories.UserRepository
+	userRepo          repositories.UserRepository
+	passwordResetRepo repositories.PasswordResetRepository
+	tokenService      *utils.TokenService
+	loginGuard        *LoginGuard
+	txManager         repositories.TxManager
+	audit             *AuditService
+	passwordCfg       config.PasswordConfig
+	now               func() time.Time
 }
 
-func NewUserService(userRepo repositories.UserRepository) *UserService {
-	return &UserService{userRepo: userRepo}
+var ErrRoleForbidden = errors.New("hanya admin yang dapat mengatur role maupun mengubah akun admin dan staff")
+
+func NewUserService(userRepo repositories.UserRepository, passwordResetRepo repositories.PasswordResetRepository, tokenService *utils.TokenService, loginGuard *LoginGuard, txManager repositories.TxManager, audit *AuditService, passwordCfg config.PasswordConfig) *UserService {
+	return &UserService{
+		userRepo:          userRepo,
+		passwordResetRepo: passwordResetRepo,
+		tokenService:      tokenService,
+		loginGuard:        loginGuard,
+		txManager:         txManager,
+		audit:             audit,
+		passwordCfg:       passwordCfg,
+		now:               time.Now,
+	}
+}
+
+// WithClock mengganti sumber waktu masa berlaku token reset password, dipakai test untuk
+// memajukan waktu tanpa menunggu.
+func (uc *UserService) WithClock(now func() time.Time) *UserService {
+	uc.now = now
+	return uc
+}
+
+// userAuditState adalah isi before/after audit log untuk user. Hash password tidak pernah
+// dicatat; PasswordChanged menandai perubahan yang mengganti password.
+type userAuditState struct {
+	models.UserResponse
+	PasswordChanged bool `json:"password_changed,omitempty"`
 }
 
-func (us *UserService) GetUsers(page, limit int) (*models.UserResponsePagination, error) {
-	users, total, err := us.userRepo.GetUsers(page, limit)
+func newUserAuditState(user *models.User, passwordChanged bool) *userAuditState {
+	return &userAuditState{
+		UserResponse: models.UserResponse{
+			UserId:    user.UserId,
+			Username:  user.Username,
+			Role:      user.Role,
+			CreatedAt: user.CreatedAt,
+		},
+		PasswordChanged: passwordChanged,
+	}
+}
+
+// recordUser mencatat perubahan user ke audit log dengan isi terbaru user dari database.
+// Entity id adalah username sebelum perubahan, sehingga riwayat user yang diganti namanya
+// tetap bisa dicari dengan username lamanya.
+func (uc *UserService) recordUser(ctx context.Context, action string, username string, before *models.User, passwordChanged bool) error {
+	after, err := uc.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	entry := AuditEntry{
+		Action:     action,
+		EntityType: models.AuditEntityUser,
+		EntityID:   after.Username,
+		After:      newUserAuditState(after, passwordChanged),
+	}
+	if before != nil {
+		entry.EntityID = before.Username
+		entry.Before = newUserAuditState(before, false)
+	}
+	return uc.audit.Record(ctx, entry)
+}
+
+// authorizeRole mencegah eskalasi hak akses: tanpa permission roles:assign, actor hanya
+// boleh mengelola akun customer dan tidak boleh memberikan role selain customer.
+func authorizeRole(actor models.Role, roles ...models.Role) error {
+	if actor.Can(models.PermissionRolesAssign) {
+		return nil
+	}
+	for _, role := range roles {
+		if role != models.RoleCustomer {
+			return ErrRoleForbidden
+		}
+	}
+	return nil
+}
+
+// revokeSessions mengeluarkan user dari semua perangkat setelah akunnya diubah atau dihapus,
+// agar token lama tidak lagi membawa username atau role yang sudah tidak berlaku.
+func (uc *UserService) revokeSessions(ctx context.Context, username string) {
+	// Perubahan akun sudah tersimpan, jadi sesi tetap dicabut walaupun request sudah
+	// dibatalkan. Kegagalan sudah dicatat oleh TokenService; perubahan akun tetap dianggap berhasil.
+	uc.tokenService.RevokeAllSessions(context.WithoutCancel(ctx), username)
+}
+
+func (us *UserService) GetUsers(ctx context.Context, page, limit int) (*models.UserResponsePagination, error) {
+	users, total, err := us.userRepo.GetUsers(ctx, page, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -27,6 +122,7 @@ func (us *UserService) GetUsers(page, limit int) (*models.UserResponsePagination
 		responseUsers[i] = models.UserResponse{
 			UserId:    user.UserId,
 			Username:  user.Username,
+			Role:      user.Role,
 			CreatedAt: user.CreatedAt,
 		}
 	}
@@ -39,7 +135,7 @@ func (us *UserService) GetUsers(page, limit int) (*models.UserResponsePagination
 	}, nil
 }
 
-func (uc *UserService) CreateUser(user *models.User) error {
+func (uc *UserService) CreateUser(ctx context.Context, user *models.User, actor models.Role) error {
 
 	validate := helpers.InitValidator()
 
@@ -48,8 +144,19 @@ func (uc *UserService) CreateUser(user *models.User) error {
 		formatted := helpers.FormatValidationError(err)
 		return helpers.ValidationErrors{Messages: formatted}
 	}
+
+	if err := validatePassword("password", user.Password, user.Username, uc.passwordCfg.MinLength); err != nil {
+		return err
+	}
+
+	if user.Role == "" {
+		user.Role = models.RoleCustomer
+	}
+	if err := authorizeRole(actor, user.Role); err != nil {
+		return err
+	}
 	
-	dataUser, _ := uc.userRepo.FindByUsername(user.Username)
+	dataUser, _ := uc.userRepo.FindByUsername(ctx, user.Username)
 	if dataUser != nil {
 		return ErrUsernameTaken
 	}
@@ -61,58 +168,242 @@ func (uc *UserService) CreateUser(user *models.User) error {
 	}
 
 	user.Password = string(hashedPassword)
-	err = uc.userRepo.CreateUser(user)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.CreateUser(ctx, user); err != nil {
+			return err
+		}
+		return uc.recordUser(ctx, models.AuditActionCreate, user.Username, nil, false)
+	})
 }
 
-func (uc *UserService) GetUserByUsername(username string) (*models.User, error) {
-	user, err := uc.userRepo.FindByUsername(username)
+func (uc *UserService) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	user, err := uc.userRepo.FindByUsername(ctx, username)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (uc *UserService) UpdateUser(username string, user *models.User) error {
+// UpdateUser mengubah username, role, dan password user. Password boleh dikosongkan untuk
+// mempertahankan password lama; password baru harus lolos kebijakan password.
+func (uc *UserService) UpdateUser(ctx context.Context, username string, user *models.User, actor models.Role) error {
 	validate := helpers.InitValidator()
 
-	err := validate.Struct(user)
+	err := validate.StructExcept(user, "Password")
 	if err != nil {
 		formatted := helpers.FormatValidationError(err)
 		return helpers.ValidationErrors{Messages: formatted}
 	}
 
-	dataUser, _ := uc.userRepo.FindByUsername(user.Username)
-	if dataUser != nil {
+	target, err := uc.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == "" {
+		user.Role = target.Role
+	}
+	if err := authorizeRole(actor, target.Role, user.Role); err != nil {
+		return err
+	}
+
+	// username milik user itu sendiri boleh dikirim ulang tanpa dianggap sudah dipakai
+	dataUser, _ := uc.userRepo.FindByUsername(ctx, user.Username)
+	if dataUser != nil && dataUser.UserId != target.UserId {
 		return ErrUsernameTaken
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
-	
+	passwordChanged := user.Password != ""
+	if passwordChanged {
+		if err := validatePassword("password", user.Password, user.Username, uc.passwordCfg.MinLength); err != nil {
+			return err
+		}
+
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		user.Password = string(hashedPassword)
+	} else {
+		user.Password = target.Password
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		row, err := uc.userRepo.UpdateUser(ctx, username, user)
+		if err != nil {
+			return err
+		}
+
+		if row == 0 {
+			return repositories.ErrUserNotFound
+		}
+		return uc.recordUser(ctx, models.AuditActionUpdate, user.Username, target, passwordChanged)
+	})
 	if err != nil {
 		return err
 	}
 
-	user.Password = string(hashedPassword)
+	uc.revokeSessions(ctx, username)
+	return nil
+}
+
+// ResetPassword mengganti password user tanpa password lama lalu mencabut semua sesinya.
+// Dipakai dari CLI oleh operator yang sudah punya akses ke server.
+func (uc *UserService) ResetPassword(ctx context.Context, username, password string) error {
+	if password == "" {
+		return helpers.ValidationErrors{Messages: map[string]string{"password": "Password wajib diisi"}}
+	}
+	if err := validatePassword("password", password, username, uc.passwordCfg.MinLength); err != nil {
+		return err
+	}
 
-	row, err := uc.userRepo.UpdateUser(username ,user)
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		return uc.updatePassword(ctx, "", username, password, models.AuditActionPasswordReset)
+	})
 	if err != nil {
 		return err
 	}
-	
-	if row == 0 {
-		return repositories.ErrUserNotFound
+
+	uc.revokeSessions(ctx, username)
+	return nil
+}
+
+// ChangePassword mengganti password milik user sendiri setelah password lamanya cocok,
+// lalu mencabut semua sesinya termasuk sesi yang sedang dipakai.
+func (uc *UserService) ChangePassword(ctx context.Context, username string, request *models.ChangePasswordRequest) error {
+	validate := helpers.Validator()
+
+	if err := validate.Struct(request); err != nil {
+		formatted := helpers.FormatValidationError(err)
+		return helpers.ValidationErrors{Messages: formatted}
+	}
+
+	user, err := uc.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
 	}
 
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.CurrentPassword)); err != nil {
+		return helpers.ValidationErrors{Messages: map[string]string{"current_password": "Password lama salah"}}
+	}
+	if request.NewPassword == request.CurrentPassword {
+		return helpers.ValidationErrors{Messages: map[string]string{"new_password": "Password baru harus berbeda dari password lama"}}
+	}
+	if err := validatePassword("new_password", request.NewPassword, username, uc.passwordCfg.MinLength); err != nil {
+		return err
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		return uc.updatePassword(ctx, "", username, request.NewPassword, models.AuditActionPasswordChange)
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.revokeSessions(ctx, username)
 	return nil
 }
 
-func (uc *UserService) DeleteUser(username string) error {
-	row, err := uc.userRepo.DeleteUser(username)
+// IssuePasswordReset menerbitkan token reset password sekali pakai untuk user. Token
+// lama yang belum dipakai dibatalkan. Token mentah hanya ada di response ini; yang
+// disimpan hanya hash-nya.
+func (uc *UserService) IssuePasswordReset(ctx context.Context, username string, actor models.Role) (*models.PasswordResetResponse, error) {
+	target, err := uc.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeRole(actor, target.Role); err != nil {
+		return nil, err
+	}
+
+	token, err := utils.RandomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := uc.now().Add(uc.passwordCfg.ResetTokenTTL)
+	createdBy, _ := ctx.Value("username").(string)
+	if createdBy == "" {
+		createdBy = systemActor
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.passwordResetRepo.DeleteUnusedResetTokens(ctx, target.UserId); err != nil {
+			return err
+		}
+		err := uc.passwordResetRepo.CreateResetToken(ctx, &models.PasswordResetToken{
+			UserID:    target.UserId,
+			TokenHash: hashResetToken(token),
+			CreatedBy: createdBy,
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, AuditEntry{
+			Action:     models.AuditActionResetIssued,
+			EntityType: models.AuditEntityUser,
+			EntityID:   target.Username,
+			After:      map[string]time.Time{"expires_at": expiresAt},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PasswordResetResponse{
+		Username:   target.Username,
+		ResetToken: token,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// ResetPasswordWithToken menukar token reset password dengan password baru. Token langsung
+// hangus walaupun dipakai bersamaan; bila password baru ditolak kebijakan, token tetap
+// berlaku. Setelahnya semua sesi dicabut dan kunci login user dibuka.
+func (uc *UserService) ResetPasswordWithToken(ctx context.Context, request *models.ResetPasswordRequest) error {
+	validate := helpers.Validator()
+
+	if err := validate.Struct(request); err != nil {
+		formatted := helpers.FormatValidationError(err)
+		return helpers.ValidationErrors{Messages: formatted}
+	}
+
+	var username string
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		username, err = uc.passwordResetRepo.ConsumeResetToken(ctx, hashResetToken(request.Token), uc.now())
+		if err != nil {
+			return err
+		}
+		if err := validatePassword("new_password", request.NewPassword, username, uc.passwordCfg.MinLength); err != nil {
+			return err
+		}
+		return uc.updatePassword(ctx, username, username, request.NewPassword, models.AuditActionPasswordReset)
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.revokeSessions(ctx, username)
+	return uc.loginGuard.Unlock(ctx, username)
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// updatePassword mengganti hash password user, membatalkan token reset yang belum dipakai
+// dan mencatat perubahan ke audit log. Dipanggil di dalam WithinTx; actor kosong berarti
+// diambil dari context.
+func (uc *UserService) updatePassword(ctx context.Context, actor, username, password, action string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	row, err := uc.userRepo.UpdatePassword(ctx, username, string(hashedPassword))
 	if err != nil {
 		return err
 	}
@@ -121,5 +412,74 @@ func (uc *UserService) DeleteUser(username string) error {
 		return repositories.ErrUserNotFound
 	}
 
+	user, err := uc.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	if err := uc.passwordResetRepo.DeleteUnusedResetTokens(ctx, user.UserId); err != nil {
+		return err
+	}
+
+	return uc.audit.Record(ctx, AuditEntry{
+		Actor:      actor,
+		Action:     action,
+		EntityType: models.AuditEntityUser,
+		EntityID:   username,
+		After:      newUserAuditState(user, true),
+	})
+}
+
+// UnlockUser membuka kunci login user yang terkunci karena terlalu banyak percobaan gagal.
+func (uc *UserService) UnlockUser(ctx context.Context, username string, actor models.Role) error {
+	target, err := uc.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeRole(actor, target.Role); err != nil {
+		return err
+	}
+
+	if err := uc.loginGuard.Unlock(ctx, target.Username); err != nil {
+		return err
+	}
+	return uc.audit.Record(ctx, AuditEntry{
+		Action:     models.AuditActionUnlock,
+		EntityType: models.AuditEntityUser,
+		EntityID:   target.Username,
+	})
+}
+
+func (uc *UserService) DeleteUser(ctx context.Context, username string, actor models.Role) error {
+	target, err := uc.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeRole(actor, target.Role); err != nil {
+		return err
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		row, err := uc.userRepo.DeleteUser(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		if row == 0 {
+			return repositories.ErrUserNotFound
+		}
+		return uc.audit.Record(ctx, AuditEntry{
+			Action:     models.AuditActionDelete,
+			EntityType: models.AuditEntityUser,
+			EntityID:   target.Username,
+			Before:     newUserAuditState(target, false),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.revokeSessions(ctx, username)
 	return nil
 }
\ No newline at end of file
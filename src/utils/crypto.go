@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+)
+
+var ErrInvalidEncryptionKey = errors.New("encryption key harus 32 byte (hex atau base64)")
+
+var ErrInvalidCiphertext = errors.New("ciphertext tidak valid")
+
+// Encrypter mengenkripsi data sensitif (misalnya credential produk digital)
+// dengan AES-256-GCM. Hasil enkripsi berupa base64 dari nonce diikuti ciphertext.
+type Encrypter struct {
+	aead cipher.AEAD
+}
+
+// ParseEncryptionKey menerima key 32 byte dalam bentuk hex (64 karakter) atau base64.
+func ParseEncryptionKey(key string) ([]byte, error) {
+	if raw, err := hex.DecodeString(key); err == nil && len(raw) == 32 {
+		return raw, nil
+	}
+	if raw, err := base64.StdEncoding.DecodeString(key); err == nil && len(raw) == 32 {
+		return raw, nil
+	}
+	return nil, ErrInvalidEncryptionKey
+}
+
+func NewEncrypter(key string) (*Encrypter, error) {
+	raw, err := ParseEncryptionKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Encrypter{aead: aead}, nil
+}
+
+func (e *Encrypter) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := e.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (e *Encrypter) Decrypt(ciphertext string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(sealed) < e.aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+
+	nonce, data := sealed[:e.aead.NonceSize()], sealed[e.aead.NonceSize():]
+	plaintext, err := e.aead.Open(nil, nonce, data, nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	return string(plaintext), nil
+}
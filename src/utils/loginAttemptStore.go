@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"contact-management/src/config"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginAttemptStore menyimpan hitungan login gagal dan kunci sementara per key, misalnya
+// user:<username> atau ip:<alamat>. Seperti TokenStore, datanya kedaluwarsa sendiri.
+type LoginAttemptStore interface {
+	// AddFailure menambah hitungan gagal key dan mengembalikan totalnya. Hitungan
+	// hilang setelah window berlalu tanpa kegagalan baru.
+	AddFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	Failures(ctx context.Context, key string) (int, error)
+	Lock(ctx context.Context, key string, ttl time.Duration) error
+	// LockedFor mengembalikan sisa waktu kunci key, atau nol jika tidak terkunci.
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	// Reset menghapus hitungan gagal sekaligus kunci key.
+	Reset(ctx context.Context, key string) error
+}
+
+// NewLoginAttemptStore memakai backend yang sama dengan token store sesi.
+func NewLoginAttemptStore(cfg config.SessionConfig, client *redis.Client) (LoginAttemptStore, error) {
+	switch cfg.Store {
+	case "redis":
+		return NewRedisLoginAttemptStore(client), nil
+	case "memory":
+		return NewMemoryLoginAttemptStore(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTokenStore, cfg.Store)
+	}
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryCounter struct {
+	count     int
+	expiresAt time.Time
+}
+
+// MemoryLoginAttemptStore adalah LoginAttemptStore di memori proses. Hitungannya tidak
+// dibagi antar instance aplikasi dan hilang saat restart.
+type MemoryLoginAttemptStore struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	lastSweep time.Time
+
+	failures map[string]memoryCounter
+	locks    map[string]time.Time
+}
+
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{
+		now:      time.Now,
+		failures: make(map[string]memoryCounter),
+		locks:    make(map[string]time.Time),
+	}
+}
+
+// WithClock mengganti sumber waktu, dipakai test untuk memajukan waktu tanpa menunggu.
+func (s *MemoryLoginAttemptStore) WithClock(now func() time.Time) *MemoryLoginAttemptStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = now
+	return s
+}
+
+// sweep membuang entri kedaluwarsa. Dipanggil dengan mu terkunci.
+func (s *MemoryLoginAttemptStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, counter := range s.failures {
+		if !now.Before(counter.expiresAt) {
+			delete(s.failures, key)
+		}
+	}
+	for key, expiresAt := range s.locks {
+		if !now.Before(expiresAt) {
+			delete(s.locks, key)
+		}
+	}
+}
+
+func (s *MemoryLoginAttemptStore) AddFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	counter := s.failures[key]
+	if !now.Before(counter.expiresAt) {
+		counter.count = 0
+	}
+	counter.count++
+	counter.expiresAt = now.Add(window)
+	s.failures[key] = counter
+	return counter.count, nil
+}
+
+func (s *MemoryLoginAttemptStore) Failures(ctx context.Context, key string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, ok := s.failures[key]
+	if !ok || !s.now().Before(counter.expiresAt) {
+		return 0, nil
+	}
+	return counter.count, nil
+}
+
+func (s *MemoryLoginAttemptStore) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.locks[key] = s.now().Add(ttl)
+	return nil
+}
+
+func (s *MemoryLoginAttemptStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt, ok := s.locks[key]
+	if !ok {
+		return 0, nil
+	}
+	return max(0, expiresAt.Sub(s.now())), nil
+}
+
+func (s *MemoryLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+	delete(s.locks, key)
+	return nil
+}
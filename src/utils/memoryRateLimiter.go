@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryHits struct {
+	times  []time.Time
+	window time.Duration
+}
+
+// MemoryRateLimiter adalah RateLimiter di memori proses. Hitungannya tidak dibagi
+// antar instance aplikasi dan hilang saat restart.
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	lastSweep time.Time
+	hits      map[string]*memoryHits
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		now:  time.Now,
+		hits: make(map[string]*memoryHits),
+	}
+}
+
+// WithClock mengganti sumber waktu, dipakai test untuk memajukan waktu tanpa menunggu.
+func (l *MemoryRateLimiter) WithClock(now func() time.Time) *MemoryRateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.now = now
+	return l
+}
+
+func (l *MemoryRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	entry := l.hits[key]
+	if entry == nil {
+		entry = &memoryHits{}
+		l.hits[key] = entry
+	}
+	entry.window = window
+	entry.times = dropExpired(entry.times, now.Add(-window))
+
+	result := RateLimitResult{Limit: limit}
+	if len(entry.times) < limit {
+		entry.times = append(entry.times, now)
+		result.Allowed = true
+	}
+	result.Remaining = limit - len(entry.times)
+	if len(entry.times) > 0 {
+		result.Reset = entry.times[0].Add(window).Sub(now)
+	}
+	return result, nil
+}
+
+// dropExpired membuang waktu request yang tidak lebih baru dari cutoff. times selalu
+// terurut karena hanya ditambah di akhir.
+func dropExpired(times []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(times) && !times[i].After(cutoff) {
+		i++
+	}
+	return times[i:]
+}
+
+// sweep membuang key yang semua requestnya sudah keluar dari window. Dipanggil dengan mu terkunci.
+func (l *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < memorySweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, entry := range l.hits {
+		if len(dropExpired(entry.times, now.Add(-entry.window))) == 0 {
+			delete(l.hits, key)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package utils
+
+import (
+	"contact-management/src/models"
+	"context"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval membatasi seberapa sering entri kedaluwarsa dibersihkan dari map.
+const memorySweepInterval = time.Minute
+
+type memorySession struct {
+	session   models.Session
+	expiresAt time.Time
+}
+
+type memoryValue struct {
+	value     string
+	expiresAt time.Time
+}
+
+// MemoryTokenStore adalah TokenStore di memori proses dengan aturan TTL yang sama
+// seperti RedisTokenStore. Cocok untuk development dan test; state hilang saat restart
+// dan tidak dibagi antar instance aplikasi.
+type MemoryTokenStore struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	lastSweep time.Time
+
+	sessions      map[string]memorySession
+	userSessions  map[string]map[string]time.Time
+	refreshFamily map[string]memoryValue
+	refreshTokens map[string]memoryValue
+}
+
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		now:           time.Now,
+		sessions:      make(map[string]memorySession),
+		userSessions:  make(map[string]map[string]time.Time),
+		refreshFamily: make(map[string]memoryValue),
+		refreshTokens: make(map[string]memoryValue),
+	}
+}
+
+// WithClock mengganti sumber waktu, dipakai test untuk memajukan waktu tanpa menunggu.
+func (s *MemoryTokenStore) WithClock(now func() time.Time) *MemoryTokenStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = now
+	return s
+}
+
+// sweep membuang entri kedaluwarsa. Dipanggil dengan mu terkunci.
+func (s *MemoryTokenStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for id, entry := range s.sessions {
+		if !now.Before(entry.expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+	for username, index := range s.userSessions {
+		for id, expiresAt := range index {
+			if !now.Before(expiresAt) {
+				delete(index, id)
+			}
+		}
+		if len(index) == 0 {
+			delete(s.userSessions, username)
+		}
+	}
+	for _, values := range []map[string]memoryValue{s.refreshFamily, s.refreshTokens} {
+		for key, entry := range values {
+			if !now.Before(entry.expiresAt) {
+				delete(values, key)
+			}
+		}
+	}
+}
+
+func (s *MemoryTokenStore) liveSession(sessionID string, now time.Time) (memorySession, bool) {
+	entry, ok := s.sessions[sessionID]
+	if !ok || !now.Before(entry.expiresAt) {
+		return memorySession{}, false
+	}
+	return entry, true
+}
+
+func liveValue(values map[string]memoryValue, key string, now time.Time) (string, bool) {
+	entry, ok := values[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		return "", false
+	}
+	return entry.value, true
+}
+
+func (s *MemoryTokenStore) SaveSession(ctx context.Context, session *models.Session, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	expiresAt := now.Add(ttl)
+	s.sessions[session.SessionID] = memorySession{session: *session, expiresAt: expiresAt}
+
+	index, ok := s.userSessions[session.Username]
+	if !ok {
+		index = make(map[string]time.Time)
+		s.userSessions[session.Username] = index
+	}
+	// Sama seperti EXPIRE pada set Redis: seluruh indeks ikut diperpanjang.
+	for id := range index {
+		index[id] = expiresAt
+	}
+	index[session.SessionID] = expiresAt
+	return nil
+}
+
+func (s *MemoryTokenStore) GetSession(ctx context.Context, sessionID string) (*models.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.liveSession(sessionID, s.now())
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	session := entry.session
+	return &session, nil
+}
+
+func (s *MemoryTokenStore) TouchSession(ctx context.Context, session *models.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.liveSession(session.SessionID, s.now())
+	if !ok {
+		return nil
+	}
+	entry.session = *session
+	s.sessions[session.SessionID] = entry
+	return nil
+}
+
+func (s *MemoryTokenStore) ListSessionIDs(ctx context.Context, username string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	sessionIDs := make([]string, 0, len(s.userSessions[username]))
+	for id, expiresAt := range s.userSessions[username] {
+		if now.Before(expiresAt) {
+			sessionIDs = append(sessionIDs, id)
+		}
+	}
+	return sessionIDs, nil
+}
+
+func (s *MemoryTokenStore) DeleteSession(ctx context.Context, username, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt, ok := s.userSessions[username][sessionID]
+	if !ok || !s.now().Before(expiresAt) {
+		return ErrSessionNotFound
+	}
+
+	delete(s.userSessions[username], sessionID)
+	delete(s.sessions, sessionID)
+	delete(s.refreshFamily, sessionID)
+	return nil
+}
+
+func (s *MemoryTokenStore) DeleteAllSessions(ctx context.Context, username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sessionID := range s.userSessions[username] {
+		delete(s.sessions, sessionID)
+		delete(s.refreshFamily, sessionID)
+	}
+	delete(s.userSessions, username)
+	return nil
+}
+
+func (s *MemoryTokenStore) SaveRefreshToken(ctx context.Context, sessionID, hash string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	expiresAt := now.Add(ttl)
+	s.refreshFamily[sessionID] = memoryValue{value: hash, expiresAt: expiresAt}
+	s.refreshTokens[hash] = memoryValue{value: sessionID, expiresAt: expiresAt}
+	return nil
+}
+
+func (s *MemoryTokenStore) FindRefreshToken(ctx context.Context, hash string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessionID, ok := liveValue(s.refreshTokens, hash, s.now())
+	if !ok {
+		return "", ErrInvalidRefreshToken
+	}
+	return sessionID, nil
+}
+
+func (s *MemoryTokenStore) RotateRefreshToken(ctx context.Context, session *models.Session, oldHash, newHash string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	current, ok := liveValue(s.refreshFamily, session.SessionID, now)
+	if !ok {
+		return ErrInvalidRefreshToken
+	}
+	if current != oldHash {
+		return ErrRefreshTokenReused
+	}
+
+	expiresAt := now.Add(ttl)
+	s.refreshFamily[session.SessionID] = memoryValue{value: newHash, expiresAt: expiresAt}
+	s.refreshTokens[newHash] = memoryValue{value: session.SessionID, expiresAt: expiresAt}
+	s.sessions[session.SessionID] = memorySession{session: *session, expiresAt: expiresAt}
+	if index, ok := s.userSessions[session.Username]; ok {
+		for id := range index {
+			index[id] = expiresAt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RateLimitResult adalah hasil satu pemeriksaan rate limit.
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset adalah sisa waktu sampai request tertua keluar dari window, yaitu saat
+	// satu slot kembali tersedia.
+	Reset time.Duration
+}
+
+// RateLimiter menghitung request per key dengan sliding window log: sebuah request
+// dihitung selama terjadi dalam window terakhir. Request yang ditolak tidak dicatat,
+// sehingga klien yang terus mencoba tetap bisa masuk lagi begitu window bergeser.
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error)
+}
+
+// fallbackRetryInterval adalah jeda sebelum primary dicoba lagi setelah gagal, agar
+// setiap request tidak ikut menunggu timeout Redis.
+const fallbackRetryInterval = 30 * time.Second
+
+// FallbackRateLimiter memakai primary dan beralih ke fallback selama primary gagal.
+// Hitungan di fallback hanya berlaku untuk instance ini.
+type FallbackRateLimiter struct {
+	primary  RateLimiter
+	fallback RateLimiter
+	logger   *logrus.Logger
+
+	mu      sync.Mutex
+	now     func() time.Time
+	retryAt time.Time
+}
+
+func NewFallbackRateLimiter(primary, fallback RateLimiter, logger *logrus.Logger) *FallbackRateLimiter {
+	return &FallbackRateLimiter{primary: primary, fallback: fallback, logger: logger, now: time.Now}
+}
+
+func (l *FallbackRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error) {
+	l.mu.Lock()
+	usePrimary := !l.now().Before(l.retryAt)
+	l.mu.Unlock()
+
+	if usePrimary {
+		result, err := l.primary.Allow(ctx, key, limit, window)
+		if err == nil {
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return RateLimitResult{}, err
+		}
+
+		l.mu.Lock()
+		l.retryAt = l.now().Add(fallbackRetryInterval)
+		l.mu.Unlock()
+		l.logger.WithContext(ctx).Warn("Rate limiter Redis gagal, memakai limiter in-memory: ", err)
+	}
+
+	return l.fallback.Allow(ctx, key, limit, window)
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisLoginAttemptStore menyimpan hitungan gagal di login_failures:<key> dan kunci
+// sementara di login_lock:<key>, keduanya dengan TTL.
+type RedisLoginAttemptStore struct {
+	client *redis.Client
+}
+
+func NewRedisLoginAttemptStore(client *redis.Client) *RedisLoginAttemptStore {
+	return &RedisLoginAttemptStore{client: client}
+}
+
+func loginFailuresKey(key string) string {
+	return fmt.Sprintf("login_failures:%s", key)
+}
+
+func loginLockKey(key string) string {
+	return fmt.Sprintf("login_lock:%s", key)
+}
+
+func (s *RedisLoginAttemptStore) AddFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	pipe := s.client.TxPipeline()
+	count := pipe.Incr(ctx, loginFailuresKey(key))
+	pipe.PExpire(ctx, loginFailuresKey(key), window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return int(count.Val()), nil
+}
+
+func (s *RedisLoginAttemptStore) Failures(ctx context.Context, key string) (int, error) {
+	count, err := s.client.Get(ctx, loginFailuresKey(key)).Int()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return count, err
+}
+
+func (s *RedisLoginAttemptStore) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	return s.client.Set(ctx, loginLockKey(key), 1, ttl).Err()
+}
+
+func (s *RedisLoginAttemptStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.client.PTTL(ctx, loginLockKey(key)).Result()
+	if err != nil {
+		return 0, err
+	}
+	// PTTL bernilai negatif untuk key yang tidak ada
+	return max(0, ttl), nil
+}
+
+func (s *RedisLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	return s.client.Del(ctx, loginFailuresKey(key), loginLockKey(key)).Err()
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript mencatat request di sorted set dengan skor waktu dalam milidetik.
+// Membuang request lama, menghitung, dan mencatat request baru dijalankan atomik di Redis
+// sehingga semua instance aplikasi berbagi hitungan yang sama.
+//
+// KEYS[1] key, ARGV[1] waktu sekarang (ms), ARGV[2] window (ms), ARGV[3] limit,
+// ARGV[4] member unik. Mengembalikan {allowed, jumlah request, sisa waktu reset (ms)}.
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+
+local reset = 0
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`)
+
+// RedisRateLimiter menyimpan hitungan rate limit di rate_limit:<key>.
+type RedisRateLimiter struct {
+	client *redis.Client
+	now    func() time.Time
+}
+
+func NewRedisRateLimiter(client *redis.Client) *RedisRateLimiter {
+	return &RedisRateLimiter{client: client, now: time.Now}
+}
+
+func rateLimitKey(key string) string {
+	return fmt.Sprintf("rate_limit:%s", key)
+}
+
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error) {
+	suffix, err := RandomHex(4)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	now := l.now()
+	member := fmt.Sprintf("%d-%s", now.UnixNano(), suffix)
+
+	values, err := slidingWindowScript.Run(ctx, l.client, []string{rateLimitKey(key)},
+		now.UnixMilli(), window.Milliseconds(), limit, member).Int64Slice()
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	if len(values) != 3 {
+		return RateLimitResult{}, fmt.Errorf("hasil rate limit tidak terduga: %v", values)
+	}
+
+	return RateLimitResult{
+		Allowed:   values[0] == 1,
+		Limit:     limit,
+		Remaining: limit - int(values[1]),
+		Reset:     time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
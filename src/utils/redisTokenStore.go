@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"contact-management/src/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisTokenStore menyimpan setiap sesi sebagai JSON di session:<id> dan mencatat
+// id-nya di set user_sessions:<username>. Untuk refresh token, refresh_family:<id sesi>
+// berisi hash yang berlaku saat ini, sedangkan refresh_token:<hash> menunjuk ke sesinya
+// dan sengaja tidak dihapus saat dirotasi agar pemakaian ulang token lama bisa dikenali.
+type RedisTokenStore struct {
+	client *redis.Client
+}
+
+func NewRedisTokenStore(client *redis.Client) *RedisTokenStore {
+	return &RedisTokenStore{client: client}
+}
+
+func sessionKey(sessionID string) string {
+	return fmt.Sprintf("session:%s", sessionID)
+}
+
+func userSessionsKey(username string) string {
+	return fmt.Sprintf("user_sessions:%s", username)
+}
+
+func refreshTokenKey(hash string) string {
+	return fmt.Sprintf("refresh_token:%s", hash)
+}
+
+func refreshFamilyKey(sessionID string) string {
+	return fmt.Sprintf("refresh_family:%s", sessionID)
+}
+
+func (s *RedisTokenStore) SaveSession(ctx context.Context, session *models.Session, ttl time.Duration) error {
+	payload, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, sessionKey(session.SessionID), payload, ttl)
+	pipe.SAdd(ctx, userSessionsKey(session.Username), session.SessionID)
+	pipe.Expire(ctx, userSessionsKey(session.Username), ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (s *RedisTokenStore) GetSession(ctx context.Context, sessionID string) (*models.Session, error) {
+	payload, err := s.client.Get(ctx, sessionKey(sessionID)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	var session models.Session
+	if err := json.Unmarshal(payload, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *RedisTokenStore) TouchSession(ctx context.Context, session *models.Session) error {
+	payload, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	// XX agar sesi yang baru saja dicabut tidak hidup kembali.
+	return s.client.SetArgs(ctx, sessionKey(session.SessionID), payload, redis.SetArgs{KeepTTL: true, Mode: "XX"}).Err()
+}
+
+func (s *RedisTokenStore) ListSessionIDs(ctx context.Context, username string) ([]string, error) {
+	return s.client.SMembers(ctx, userSessionsKey(username)).Result()
+}
+
+func (s *RedisTokenStore) DeleteSession(ctx context.Context, username, sessionID string) error {
+	removed, err := s.client.SRem(ctx, userSessionsKey(username), sessionID).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrSessionNotFound
+	}
+
+	return s.client.Del(ctx, sessionKey(sessionID), refreshFamilyKey(sessionID)).Err()
+}
+
+func (s *RedisTokenStore) DeleteAllSessions(ctx context.Context, username string) error {
+	sessionIDs, err := s.client.SMembers(ctx, userSessionsKey(username)).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{userSessionsKey(username)}
+	for _, sessionID := range sessionIDs {
+		keys = append(keys, sessionKey(sessionID), refreshFamilyKey(sessionID))
+	}
+	return s.client.Del(ctx, keys...).Err()
+}
+
+func (s *RedisTokenStore) SaveRefreshToken(ctx context.Context, sessionID, hash string, ttl time.Duration) error {
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, refreshFamilyKey(sessionID), hash, ttl)
+	pipe.Set(ctx, refreshTokenKey(hash), sessionID, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *RedisTokenStore) FindRefreshToken(ctx context.Context, hash string) (string, error) {
+	sessionID, err := s.client.Get(ctx, refreshTokenKey(hash)).Result()
+	if err == redis.Nil {
+		return "", ErrInvalidRefreshToken
+	}
+	return sessionID, err
+}
+
+func (s *RedisTokenStore) RotateRefreshToken(ctx context.Context, session *models.Session, oldHash, newHash string, ttl time.Duration) error {
+	payload, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	// WATCH memastikan hanya satu permintaan yang bisa merotasi hash yang sama;
+	// permintaan lain yang kalah balapan diperlakukan sebagai pemakaian ulang.
+	familyKey := refreshFamilyKey(session.SessionID)
+	err = s.client.Watch(ctx, func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, familyKey).Result()
+		if err == redis.Nil {
+			return ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+		if current != oldHash {
+			return ErrRefreshTokenReused
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, familyKey, newHash, ttl)
+			pipe.Set(ctx, refreshTokenKey(newHash), session.SessionID, ttl)
+			pipe.Set(ctx, sessionKey(session.SessionID), payload, ttl)
+			pipe.Expire(ctx, userSessionsKey(session.Username), ttl)
+			return nil
+		})
+		return err
+	}, familyKey)
+	if errors.Is(err, redis.TxFailedErr) {
+		return ErrRefreshTokenReused
+	}
+	return err
+}
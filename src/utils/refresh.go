@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"contact-management/src/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+var ErrInvalidRefreshToken = errors.New("refresh token tidak valid")
+
+var ErrRefreshTokenReused = errors.New("refresh token sudah pernah dipakai")
+
+// Setiap sesi adalah satu keluarga refresh token. Hanya hash token yang disimpan
+// di TokenStore; token mentah tidak pernah disimpan.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (ts *TokenService) issueRefreshToken(ctx context.Context, sessionID string) (string, error) {
+	token, err := RandomHex(32)
+	if err != nil {
+		return "", err
+	}
+
+	if err := ts.store.SaveRefreshToken(ctx, sessionID, hashRefreshToken(token), ts.refreshExpiration); err != nil {
+		ts.logger.WithContext(ctx).Error("Failed to store refresh token", err)
+		return "", err
+	}
+
+	return token, nil
+}
+
+// RefreshTokens menukar refresh token dengan pasangan token baru dan merotasi
+// refresh token tersebut. Jika token yang sudah dirotasi dipakai lagi, seluruh
+// keluarga token (sesinya) dicabut dan ErrRefreshTokenReused dikembalikan.
+func (ts *TokenService) RefreshTokens(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
+	hash := hashRefreshToken(refreshToken)
+
+	sessionID, err := ts.store.FindRefreshToken(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := ts.store.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	newToken, err := RandomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	session.ExpiresAt = ts.now().Add(ts.refreshExpiration)
+	err = ts.store.RotateRefreshToken(ctx, session, hash, hashRefreshToken(newToken), ts.refreshExpiration)
+	if errors.Is(err, ErrRefreshTokenReused) {
+		ts.logger.WithContext(ctx).Warnf("Refresh token reuse detected for user %s, revoking session %s", session.Username, sessionID)
+		if revokeErr := ts.RevokeSession(ctx, session.Username, sessionID); revokeErr != nil && !errors.Is(revokeErr, ErrSessionNotFound) {
+			return nil, revokeErr
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := ts.signAccessToken(session)
+	if err != nil {
+		return nil, err
+	}
+
+	return ts.tokenPair(token, newToken), nil
+}
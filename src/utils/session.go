@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"contact-management/src/models"
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+var ErrSessionNotFound = errors.New("sesi tidak ditemukan")
+
+// lastSeenResolution membatasi seberapa sering last_seen ditulis ulang ke TokenStore.
+const lastSeenResolution = time.Minute
+
+// Setiap sesi hidup selama TTL refresh token dan tercatat di indeks sesi milik username,
+// sehingga bisa didaftar dan dicabut sekaligus.
+func (ts *TokenService) createSession(ctx context.Context, username string, role models.Role, meta models.SessionMeta) (*models.Session, error) {
+	sessionID, err := RandomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.enforceSessionLimit(ctx, username); err != nil {
+		return nil, err
+	}
+
+	now := ts.now()
+	session := &models.Session{
+		SessionID:  sessionID,
+		Username:   username,
+		Role:       role,
+		Device:     meta.Device,
+		IP:         meta.IP,
+		UserAgent:  meta.UserAgent,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(ts.refreshExpiration),
+	}
+
+	if err := ts.store.SaveSession(ctx, session, ts.refreshExpiration); err != nil {
+		ts.logger.WithContext(ctx).Error("Failed to store session", err)
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// enforceSessionLimit mengeluarkan sesi tertua sampai tersisa ruang untuk satu sesi baru.
+func (ts *TokenService) enforceSessionLimit(ctx context.Context, username string) error {
+	if ts.maxSessions <= 0 {
+		return nil
+	}
+
+	sessions, err := ts.ListSessions(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; len(sessions)-i >= ts.maxSessions; i++ {
+		if err := ts.RevokeSession(ctx, username, sessions[i].SessionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// touchSession memastikan sesi masih aktif dan milik username, lalu memperbarui last_seen.
+func (ts *TokenService) touchSession(ctx context.Context, username, sessionID string) error {
+	session, err := ts.store.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.Username != username {
+		return ErrSessionNotFound
+	}
+
+	if ts.now().Sub(session.LastSeenAt) < lastSeenResolution {
+		return nil
+	}
+
+	session.LastSeenAt = ts.now()
+	if err := ts.store.TouchSession(ctx, session); err != nil {
+		ts.logger.WithContext(ctx).Warn("Failed to update session last seen", err)
+	}
+	return nil
+}
+
+// ListSessions mengembalikan sesi aktif milik username, diurutkan dari yang paling lama.
+// Id sesi yang sudah kedaluwarsa dibersihkan dari indeks sekalian.
+func (ts *TokenService) ListSessions(ctx context.Context, username string) ([]models.Session, error) {
+	sessionIDs, err := ts.store.ListSessionIDs(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]models.Session, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		session, err := ts.store.GetSession(ctx, sessionID)
+		if errors.Is(err, ErrSessionNotFound) {
+			ts.store.DeleteSession(ctx, username, sessionID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+func (ts *TokenService) RevokeSession(ctx context.Context, username, sessionID string) error {
+	err := ts.store.DeleteSession(ctx, username, sessionID)
+	if err != nil && !errors.Is(err, ErrSessionNotFound) {
+		ts.logger.WithContext(ctx).Error("Failed to revoke session", err)
+	}
+	return err
+}
+
+// RevokeAllSessions mengeluarkan username dari semua perangkat.
+func (ts *TokenService) RevokeAllSessions(ctx context.Context, username string) error {
+	if err := ts.store.DeleteAllSessions(ctx, username); err != nil {
+		ts.logger.WithContext(ctx).Error("Failed to revoke all sessions", err)
+		return err
+	}
+	return nil
+}
@@ -1,78 +1,232 @@
 package utils
 
 import (
-	"contact-management/src/apps"
+	"contact-management/src/config"
+	"contact-management/src/models"
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
 )
 
-func GenerateToken(username string) (string, error) {
-	claims := jwt.MapClaims{
-		"username": username,
-		"exp":      time.Now().Add(time.Hour * 24).Unix(),
+var ErrInvalidToken = errors.New("Invalid token")
+
+var ErrInvalidJWTConfig = errors.New("konfigurasi JWT tidak valid")
+
+const defaultKeyID = "default"
+
+// insecureJWTSecret adalah secret contoh yang dulu menjadi nilai bawaan JWT_SECRET. Karena
+// sudah diketahui umum, secret ini hanya diterima di APP_ENV development dan test.
+const insecureJWTSecret = "default-secret-key"
+
+type Claims struct {
+	Username string      `json:"username"`
+	Role     models.Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// TokenService menerbitkan dan memverifikasi JWT berdasarkan JWTConfig.
+// Beberapa key dapat aktif bersamaan untuk rotasi: token baru selalu
+// ditandatangani dengan key aktif, sedangkan token lama tetap bisa diverifikasi
+// selama kid-nya masih terdaftar di JWT_KEYS.
+//
+// Access token berumur pendek (JWT_EXPIRATION), sedangkan sesi beserta refresh
+// token-nya bertahan selama JWT_REFRESH_EXPIRATION sejak refresh terakhir.
+type TokenService struct {
+	keys              map[string][]byte
+	activeKeyID       string
+	issuer            string
+	audience          string
+	expiration        time.Duration
+	refreshExpiration time.Duration
+	maxSessions       int
+	store             TokenStore
+	logger            *logrus.Logger
+	now               func() time.Time
+}
+
+func NewTokenService(cfg *config.Config, store TokenStore, logger *logrus.Logger) (*TokenService, error) {
+	expiration, err := time.ParseDuration(cfg.JWT.Expiration)
+	if err != nil || expiration <= 0 {
+		return nil, fmt.Errorf("%w: JWT_EXPIRATION %q", ErrInvalidJWTConfig, cfg.JWT.Expiration)
 	}
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	refreshExpiration, err := time.ParseDuration(cfg.JWT.RefreshExpiration)
+	if err != nil || refreshExpiration < expiration {
+		return nil, fmt.Errorf("%w: JWT_REFRESH_EXPIRATION %q", ErrInvalidJWTConfig, cfg.JWT.RefreshExpiration)
+	}
+
+	keys, activeKeyID, err := parseKeys(cfg.JWT)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if cfg.App.Env != "development" && cfg.App.Env != "test" {
+		for kid, secret := range keys {
+			if string(secret) == insecureJWTSecret {
+				return nil, fmt.Errorf("%w: secret key %q tidak boleh memakai nilai contoh di APP_ENV %q", ErrInvalidJWTConfig, kid, cfg.App.Env)
+			}
+		}
 	}
 
-	rdb := apps.RedisClient()
-	ctx := context.Background()
-	userKey := fmt.Sprintf("user_token:%s", username)
-	prevToken, err := rdb.Get(ctx, userKey).Result()
-	if err == nil {
-		prevTokenKey := fmt.Sprintf("token:%s", prevToken)
-		rdb.Del(ctx, prevTokenKey)
-	} else if err != redis.Nil {
-		apps.LoggingApp().Warn("Failed to read previous token", err)
+	return &TokenService{
+		keys:              keys,
+		activeKeyID:       activeKeyID,
+		issuer:            cfg.JWT.Issuer,
+		audience:          cfg.JWT.Audience,
+		expiration:        expiration,
+		refreshExpiration: refreshExpiration,
+		maxSessions:       cfg.Session.MaxPerUser,
+		store:             store,
+		logger:            logger,
+		now:               time.Now,
+	}, nil
+}
+
+// WithClock mengganti sumber waktu untuk penerbitan dan validasi token serta umur sesi,
+// dipakai test untuk memajukan waktu tanpa menunggu.
+func (ts *TokenService) WithClock(now func() time.Time) *TokenService {
+	ts.now = now
+	return ts
+}
+
+func parseKeys(cfg config.JWTConfig) (map[string][]byte, string, error) {
+	keys := make(map[string][]byte)
+
+	if strings.TrimSpace(cfg.Keys) == "" {
+		if cfg.Secret == "" {
+			return nil, "", fmt.Errorf("%w: JWT_SECRET kosong", ErrInvalidJWTConfig)
+		}
+		keys[defaultKeyID] = []byte(cfg.Secret)
+		return keys, defaultKeyID, nil
 	}
 
-	tokenKey := fmt.Sprintf("token:%s", token)
-	if err := rdb.Set(ctx, tokenKey, username, 24*time.Hour).Err(); err != nil {
-		apps.LoggingApp().Error("Failed to store token in Redis", err)
-		return "", err
+	for _, pair := range strings.Split(cfg.Keys, ",") {
+		kid, secret, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || kid == "" || secret == "" {
+			return nil, "", fmt.Errorf("%w: JWT_KEYS harus berformat kid:secret", ErrInvalidJWTConfig)
+		}
+		keys[kid] = []byte(secret)
 	}
 
-	if err := rdb.Set(ctx, userKey, token, 24*time.Hour).Err(); err != nil {
-		apps.LoggingApp().Error("Failed to update user token mapping", err)
-		return "", err
+	activeKeyID := cfg.ActiveKeyID
+	if _, ok := keys[activeKeyID]; !ok {
+		return nil, "", fmt.Errorf("%w: JWT_ACTIVE_KEY_ID %q tidak ada di JWT_KEYS", ErrInvalidJWTConfig, activeKeyID)
 	}
 
-	return token, nil
+	return keys, activeKeyID, nil
+}
+
+// GenerateTokenPair membuat sesi baru untuk username lalu menerbitkan access token
+// yang membawa id sesi di klaim jti dan role user, beserta refresh token pertama untuk sesi itu.
+func (ts *TokenService) GenerateTokenPair(ctx context.Context, username string, role models.Role, meta models.SessionMeta) (*models.TokenPair, error) {
+	session, err := ts.createSession(ctx, username, role, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	pair, err := ts.issueTokenPair(ctx, session)
+	if err != nil {
+		ts.RevokeSession(ctx, username, session.SessionID)
+		return nil, err
+	}
+	return pair, nil
 }
 
-func VerifyToken(tokenString string) (string, error) {
-	rdb := apps.RedisClient()
-	ctx := context.Background()
+func (ts *TokenService) issueTokenPair(ctx context.Context, session *models.Session) (*models.TokenPair, error) {
+	token, err := ts.signAccessToken(session)
+	if err != nil {
+		return nil, err
+	}
 
-	tokenKey := fmt.Sprintf("token:%s", tokenString)
-	username, err := rdb.Get(ctx, tokenKey).Result()
+	refreshToken, err := ts.issueRefreshToken(ctx, session.SessionID)
 	if err != nil {
-		return "", errors.New("Invalid token")
+		return nil, err
 	}
-	return username, nil
+
+	return ts.tokenPair(token, refreshToken), nil
 }
 
-func RevokeToken(tokenString, username string) error {
-	rdb := apps.RedisClient()
-	ctx := context.Background()
+func (ts *TokenService) tokenPair(token, refreshToken string) *models.TokenPair {
+	return &models.TokenPair{
+		Token:        token,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(ts.expiration.Seconds()),
+	}
+}
 
-	userKey := fmt.Sprintf("user_token:%s", username)
-	tokenKey := fmt.Sprintf("token:%s", tokenString)
+func (ts *TokenService) signAccessToken(session *models.Session) (string, error) {
+	now := ts.now()
+	claims := Claims{
+		Username: session.Username,
+		Role:     session.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        session.SessionID,
+			Subject:   session.Username,
+			Issuer:    ts.issuer,
+			Audience:  jwt.ClaimStrings{ts.audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ts.expiration)),
+		},
+	}
 
-	if err := rdb.Del(ctx, userKey).Err(); err != nil {
-		apps.LoggingApp().Error("Failed to revoke user token", err)
-		return err
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	jwtToken.Header["kid"] = ts.activeKeyID
+
+	return jwtToken.SignedString(ts.keys[ts.activeKeyID])
+}
+
+// ParseToken memverifikasi signature, kid, exp, iat, issuer, audience dan role token
+// tanpa memeriksa apakah token sudah dicabut.
+func (ts *TokenService) ParseToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := ts.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown kid %q", kid)
+		}
+		return key, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(ts.issuer),
+		jwt.WithAudience(ts.audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(ts.now),
+	)
+	if err != nil || claims.IssuedAt == nil || claims.Username == "" || claims.ID == "" || !claims.Role.Valid() {
+		return nil, ErrInvalidToken
 	}
-	if err := rdb.Del(ctx, tokenKey).Err(); err != nil {
-		apps.LoggingApp().Error("Failed to revoke token", err)
+
+	return claims, nil
+}
+
+// VerifyToken memvalidasi JWT lalu memastikan sesi yang dirujuk token masih aktif.
+func (ts *TokenService) VerifyToken(ctx context.Context, tokenString string) (*Claims, error) {
+	claims, err := ts.ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.touchSession(ctx, claims.Username, claims.ID); err != nil {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// RevokeToken mengakhiri sesi milik token sehingga token tidak dapat dipakai lagi.
+func (ts *TokenService) RevokeToken(ctx context.Context, tokenString, username string) error {
+	claims, err := ts.ParseToken(tokenString)
+	if err != nil {
 		return err
 	}
-	return nil
+	if claims.Username != username {
+		return ErrInvalidToken
+	}
+	return ts.RevokeSession(ctx, username, claims.ID)
 }
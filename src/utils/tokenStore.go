@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"contact-management/src/config"
+	"contact-management/src/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrUnknownTokenStore = errors.New("token store tidak dikenal")
+
+// TokenStore menyimpan state autentikasi yang dipakai TokenService: sesi login,
+// indeks sesi per user dan keluarga refresh token. Implementasinya harus menghapus
+// data yang TTL-nya habis dengan sendirinya.
+type TokenStore interface {
+	// SaveSession menyimpan sesi dengan TTL dan mencatatnya di indeks sesi milik username.
+	SaveSession(ctx context.Context, session *models.Session, ttl time.Duration) error
+	// GetSession mengembalikan ErrSessionNotFound jika sesi tidak ada atau sudah kedaluwarsa.
+	GetSession(ctx context.Context, sessionID string) (*models.Session, error)
+	// TouchSession menulis ulang sesi tanpa mengubah sisa TTL-nya.
+	TouchSession(ctx context.Context, session *models.Session) error
+	ListSessionIDs(ctx context.Context, username string) ([]string, error)
+	// DeleteSession menghapus sesi beserta keluarga refresh token-nya, dan mengembalikan
+	// ErrSessionNotFound jika id sesi tidak tercatat di indeks milik username.
+	DeleteSession(ctx context.Context, username, sessionID string) error
+	DeleteAllSessions(ctx context.Context, username string) error
+
+	// SaveRefreshToken menjadikan hash sebagai refresh token yang berlaku untuk sesi.
+	SaveRefreshToken(ctx context.Context, sessionID, hash string, ttl time.Duration) error
+	// FindRefreshToken mengembalikan id sesi pemilik hash, termasuk hash yang sudah dirotasi.
+	FindRefreshToken(ctx context.Context, hash string) (string, error)
+	// RotateRefreshToken mengganti oldHash dengan newHash secara atomik dan menyimpan ulang
+	// sesi dengan TTL baru. Mengembalikan ErrRefreshTokenReused jika oldHash bukan lagi
+	// refresh token yang berlaku, atau ErrInvalidRefreshToken jika keluarganya sudah dicabut.
+	RotateRefreshToken(ctx context.Context, session *models.Session, oldHash, newHash string, ttl time.Duration) error
+}
+
+func NewTokenStore(cfg config.SessionConfig, client *redis.Client) (TokenStore, error) {
+	switch cfg.Store {
+	case "redis":
+		return NewRedisTokenStore(client), nil
+	case "memory":
+		return NewMemoryTokenStore(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTokenStore, cfg.Store)
+	}
+}
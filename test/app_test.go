@@ -0,0 +1,114 @@
+package test
+
+import (
+	"contact-management/src/app"
+	"contact-management/src/models"
+	"contact-management/src/utils"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAppWithClock(t *testing.T) {
+	cfg := testConfig()
+	expiration, err := time.ParseDuration(cfg.JWT.Expiration)
+	if err != nil {
+		t.Fatalf("Invalid JWT_EXPIRATION %q: %v", cfg.JWT.Expiration, err)
+	}
+
+	clock := &fakeClock{now: time.Now()}
+	application, err := app.New(cfg,
+		app.WithDB(testApp().DB),
+		app.WithLogger(testLogger()),
+		app.WithTokenStore(utils.NewMemoryTokenStore()),
+		app.WithGateway(testGateway()),
+		app.WithClock(clock.Now),
+	)
+	if err != nil {
+		t.Fatalf("Failed to build application: %v", err)
+	}
+	defer application.Close()
+
+	if application.DB != testApp().DB {
+		t.Error("Expected the injected database to be used")
+	}
+
+	tokens, err := application.TokenService.GenerateTokenPair(context.Background(), "testuser_app_clock", models.RoleAdmin, models.SessionMeta{})
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	t.Run("Success - Token valid on the application clock", func(t *testing.T) {
+		rr := makeRequest(t, application.Router, "GET", "/me/sessions", nil, tokens.Token)
+		assertStatusCode(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Error - Token expired once the clock passes its expiration", func(t *testing.T) {
+		clock.Advance(expiration + time.Minute)
+
+		rr := makeRequest(t, application.Router, "GET", "/me/sessions", nil, tokens.Token)
+		assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("Error - Token from another token store is rejected", func(t *testing.T) {
+		token := getValidToken(t, "testuser_app_clock")
+		defer testTokenService().RevokeAllSessions(context.Background(), "testuser_app_clock")
+
+		rr := makeRequest(t, application.Router, "GET", "/me/sessions", nil, token)
+		assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestAppCloseKeepsInjectedDB(t *testing.T) {
+	application, err := app.New(testConfig(),
+		app.WithDB(testApp().DB),
+		app.WithLogger(testLogger()),
+		app.WithTokenStore(testTokenStore()),
+		app.WithGateway(testGateway()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to build application: %v", err)
+	}
+	application.Close()
+
+	if err := testApp().DB.Ping(); err != nil {
+		t.Errorf("Expected injected database to stay open after Close, got %v", err)
+	}
+}
+
+func TestAppQueryTimeout(t *testing.T) {
+	token := getValidToken(t, "testuser_query_timeout")
+	defer cleanupTestUser(t, "testuser_query_timeout")
+
+	cfg := testConfig()
+	cfg.Database.QueryTimeout = time.Nanosecond
+	application, err := app.New(cfg,
+		app.WithDB(testApp().DB),
+		app.WithLogger(testLogger()),
+		app.WithTokenStore(testTokenStore()),
+		app.WithGateway(testGateway()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to build application: %v", err)
+	}
+	defer application.Close()
+
+	t.Run("Error - Query past its timeout returns 504", func(t *testing.T) {
+		rr := makeRequest(t, application.Router, "GET", "/categories", nil, token)
+		assertStatusCode(t, http.StatusGatewayTimeout, rr.Code)
+	})
+
+	t.Run("Error - Cancelled request returns 503", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		req := httptest.NewRequest("GET", "/categories", nil).WithContext(ctx)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		testRouter().ServeHTTP(rr, req)
+
+		assertStatusCode(t, http.StatusServiceUnavailable, rr.Code)
+	})
+}
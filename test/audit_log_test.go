@@ -0,0 +1,212 @@
+package test
+
+import (
+	"bytes"
+	"contact-management/src/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// getAuditLogs fetches GET /audit-logs with the given filters as an admin
+func getAuditLogs(t *testing.T, token string, filters url.Values) []models.AuditLog {
+	t.Helper()
+
+	rr := makeRequest(t, testRouter(), "GET", "/audit-logs?"+filters.Encode(), nil, token)
+	response := parseResponse(t, rr)
+	assertStatusCode(t, http.StatusOK, rr.Code)
+
+	var page models.AuditLogResponsePagination
+	if err := json.Unmarshal(response.Data, &page); err != nil {
+		t.Fatalf("Failed to parse audit logs: %v", err)
+	}
+	return page.AuditLogs
+}
+
+func TestAuditLog(t *testing.T) {
+	router := testRouter()
+	token := getValidToken(t, "testuser_audit")
+	defer cleanupTestUser(t, "testuser_audit")
+
+	var categoryID int
+
+	t.Run("Success - Category changes are recorded with before and after", func(t *testing.T) {
+		rr := makeRequest(t, router, "POST", "/categories", map[string]interface{}{"name": "Audit Category"}, token)
+		assertStatusCode(t, http.StatusCreated, rr.Code)
+		var category models.Category
+		json.Unmarshal(parseResponse(t, rr).Data, &category)
+		categoryID = category.CategoryID
+
+		path := fmt.Sprintf("/categories/%d", categoryID)
+		assertStatusCode(t, http.StatusOK, makeRequest(t, router, "PUT", path, map[string]interface{}{"name": "Audit Category Renamed"}, token).Code)
+		assertStatusCode(t, http.StatusOK, makeRequest(t, router, "DELETE", path, nil, token).Code)
+
+		logs := getAuditLogs(t, token, url.Values{
+			"entity_type": {models.AuditEntityCategory},
+			"entity_id":   {fmt.Sprint(categoryID)},
+		})
+		if len(logs) != 3 {
+			t.Fatalf("Expected 3 audit logs, got %d", len(logs))
+		}
+
+		// Newest first
+		deleted, updated, created := logs[0], logs[1], logs[2]
+		for i, action := range []string{models.AuditActionDelete, models.AuditActionUpdate, models.AuditActionCreate} {
+			if logs[i].Action != action || logs[i].Actor != "testuser_audit" {
+				t.Errorf("Expected %s by testuser_audit, got %s by %s", action, logs[i].Action, logs[i].Actor)
+			}
+		}
+		if string(created.Before) != "null" || !strings.Contains(string(created.After), `"Audit Category"`) {
+			t.Errorf("Expected create to only carry after, got before=%s after=%s", created.Before, created.After)
+		}
+		if !strings.Contains(string(updated.Before), `"Audit Category"`) || !strings.Contains(string(updated.After), `"Audit Category Renamed"`) {
+			t.Errorf("Expected update to carry both versions, got before=%s after=%s", updated.Before, updated.After)
+		}
+		if string(deleted.After) != "null" || !strings.Contains(string(deleted.Before), `"Audit Category Renamed"`) {
+			t.Errorf("Expected delete to only carry before, got before=%s after=%s", deleted.Before, deleted.After)
+		}
+	})
+	defer cleanupTestCategory(t, categoryID)
+
+	t.Run("Error - Failed change is not recorded", func(t *testing.T) {
+		rr := makeRequest(t, router, "DELETE", fmt.Sprintf("/categories/%d", categoryID), nil, token)
+		assertStatusCode(t, http.StatusNotFound, rr.Code)
+
+		logs := getAuditLogs(t, token, url.Values{
+			"entity_type": {models.AuditEntityCategory},
+			"entity_id":   {fmt.Sprint(categoryID)},
+		})
+		if len(logs) != 3 {
+			t.Errorf("Expected still 3 audit logs, got %d", len(logs))
+		}
+	})
+
+	t.Run("Success - Request id and IP are recorded", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]interface{}{"name": "Audit Category Request"})
+		req := httptest.NewRequest("POST", "/categories", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-Request-ID", "audit-request-1")
+		req.Header.Set("X-Forwarded-For", "203.0.113.9")
+		rr := httptest.NewRecorder()
+		testApp().Handler.ServeHTTP(rr, req)
+		assertStatusCode(t, http.StatusCreated, rr.Code)
+
+		var category models.Category
+		json.Unmarshal(parseResponse(t, rr).Data, &category)
+		defer cleanupTestCategory(t, category.CategoryID)
+
+		logs := getAuditLogs(t, token, url.Values{
+			"entity_type": {models.AuditEntityCategory},
+			"entity_id":   {fmt.Sprint(category.CategoryID)},
+		})
+		if len(logs) != 1 || logs[0].RequestID != "audit-request-1" || logs[0].IP != "203.0.113.9" {
+			t.Errorf("Expected one log with request id and IP, got %+v", logs)
+		}
+	})
+
+	t.Run("Success - User changes never record the password", func(t *testing.T) {
+		defer cleanupTestUser(t, "testuser_audit_target")
+		defer cleanupTestUser(t, "testuser_audit_renamed")
+
+		rr := makeRequest(t, router, "POST", "/users", map[string]interface{}{
+			"username": "testuser_audit_target",
+			"password": "password123",
+		}, token)
+		assertStatusCode(t, http.StatusCreated, rr.Code)
+
+		rr = makeRequest(t, router, "PUT", "/users/testuser_audit_target", map[string]interface{}{
+			"username": "testuser_audit_renamed",
+			"password": "newpassword123",
+		}, token)
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		logs := getAuditLogs(t, token, url.Values{
+			"actor":       {"testuser_audit"},
+			"entity_type": {models.AuditEntityUser},
+			"entity_id":   {"testuser_audit_target"},
+		})
+		if len(logs) != 2 {
+			t.Fatalf("Expected 2 audit logs, got %d", len(logs))
+		}
+		for _, log := range logs {
+			if strings.Contains(string(log.Before)+string(log.After), "password\"") {
+				t.Errorf("Expected no password in audit log, got before=%s after=%s", log.Before, log.After)
+			}
+		}
+		if !strings.Contains(string(logs[0].After), `"testuser_audit_renamed"`) || !strings.Contains(string(logs[0].After), `"password_changed":true`) {
+			t.Errorf("Expected update to carry the new username and mark the password change, got %s", logs[0].After)
+		}
+	})
+
+	t.Run("Success - Login attempts are recorded", func(t *testing.T) {
+		defer cleanupTestUser(t, "testuser_audit_login")
+
+		makeRequest(t, router, "POST", "/register", map[string]interface{}{
+			"username": "testuser_audit_login",
+			"password": "password123",
+		}, "")
+		makeRequest(t, router, "POST", "/login", map[string]interface{}{
+			"username": "testuser_audit_login",
+			"password": "wrongpassword",
+		}, "")
+		makeRequest(t, router, "POST", "/login", map[string]interface{}{
+			"username": "testuser_audit_login",
+			"password": "password123",
+		}, "")
+
+		logs := getAuditLogs(t, token, url.Values{"actor": {"testuser_audit_login"}})
+		if len(logs) != 2 || logs[0].Action != models.AuditActionLoginSucceeded || logs[1].Action != models.AuditActionLoginFailed {
+			t.Errorf("Expected a failed then a successful login, got %+v", logs)
+		}
+	})
+
+	t.Run("Success - Failed logins for unknown users are not recorded", func(t *testing.T) {
+		long := "testuser_audit_nobody_" + strings.Repeat("x", 200)
+		for _, username := range []string{"testuser_audit_nobody", long} {
+			rr := makeRequest(t, router, "POST", "/login", map[string]interface{}{
+				"username": username,
+				"password": "wrongpassword",
+			}, "")
+			assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+		}
+
+		for _, actor := range []string{"testuser_audit_nobody", long, long[:50]} {
+			if logs := getAuditLogs(t, token, url.Values{"actor": {actor}}); len(logs) != 0 {
+				t.Errorf("Expected no audit logs for unknown user %q, got %+v", actor, logs)
+			}
+		}
+	})
+
+	t.Run("Success - Time range filter", func(t *testing.T) {
+		future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		if logs := getAuditLogs(t, token, url.Values{"actor": {"testuser_audit"}, "from": {future}}); len(logs) != 0 {
+			t.Errorf("Expected no audit logs from the future, got %d", len(logs))
+		}
+
+		past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		if logs := getAuditLogs(t, token, url.Values{"actor": {"testuser_audit"}, "from": {past}}); len(logs) == 0 {
+			t.Error("Expected audit logs within the last hour")
+		}
+	})
+
+	t.Run("Error - Invalid time filter", func(t *testing.T) {
+		rr := makeRequest(t, router, "GET", "/audit-logs?from=yesterday", nil, token)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Error - Staff cannot read audit logs", func(t *testing.T) {
+		staffToken := getValidTokenWithRole(t, "testuser_audit_staff", models.RoleStaff)
+		defer cleanupTestUser(t, "testuser_audit_staff")
+
+		rr := makeRequest(t, router, "GET", "/audit-logs", nil, staffToken)
+
+		assertStatusCode(t, http.StatusForbidden, rr.Code)
+	})
+}
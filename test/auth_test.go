@@ -1,40 +1,12 @@
 package test
 
 import (
-	"contact-management/src/apps"
-	"contact-management/src/config"
-	"contact-management/src/controllers"
-	"contact-management/src/middlewares"
-	"contact-management/src/repositories"
-	"contact-management/src/services"
 	"net/http"
 	"testing"
-
-	"github.com/julienschmidt/httprouter"
 )
 
-func setupAuthRouter() *httprouter.Router {
-	cfg := config.LoadConfig()
-	db, err := apps.Connect(cfg)
-	if err != nil {
-		panic("Failed to connect to database: " + err.Error())
-	}
-
-	userRepo := repositories.NewUserRepository(db)
-	authService := services.NewAuthService(userRepo)
-	authController := controllers.NewAuthController(authService)
-
-	router := httprouter.New()
-	router.POST("/register", authController.Register)
-	router.POST("/login", authController.Login)
-	router.GET("/me", middlewares.AuthMiddleware(authController.Me))
-	router.POST("/logout", middlewares.AuthMiddleware(authController.Logout))
-
-	return router
-}
-
 func TestRegister(t *testing.T) {
-	router := setupAuthRouter()
+	router := testRouter()
 
 	t.Run("Success - Register new user", func(t *testing.T) {
 		body := map[string]interface{}{
@@ -88,7 +60,7 @@ func TestRegister(t *testing.T) {
 }
 
 func TestLogin(t *testing.T) {
-	router := setupAuthRouter()
+	router := testRouter()
 
 	// Setup: Create a test user
 	registerBody := map[string]interface{}{
@@ -139,8 +111,8 @@ func TestLogin(t *testing.T) {
 		rr := makeRequest(t, router, "POST", "/login", loginBody, "")
 		response := parseResponse(t, rr)
 
-		// Note: Currently returns 500 - should handle sql.ErrNoRows in repository
-		assertStatusCode(t, http.StatusInternalServerError, rr.Code)
+		// Unknown usernames are indistinguishable from a wrong password
+		assertStatusCode(t, http.StatusUnauthorized, rr.Code)
 		assertResponseStatus(t, "error", response)
 	})
 
@@ -159,7 +131,7 @@ func TestLogin(t *testing.T) {
 }
 
 func TestMe(t *testing.T) {
-	router := setupAuthRouter()
+	router := testRouter()
 
 	// Setup: Create and login test user
 	registerBody := map[string]interface{}{
@@ -198,7 +170,7 @@ func TestMe(t *testing.T) {
 }
 
 func TestLogout(t *testing.T) {
-	router := setupAuthRouter()
+	router := testRouter()
 
 	// Setup: Create test user
 	registerBody := map[string]interface{}{
@@ -1,46 +1,17 @@
 package test
 
 import (
-	"contact-management/src/apps"
-	"contact-management/src/config"
-	"contact-management/src/controllers"
-	"contact-management/src/middlewares"
-	"contact-management/src/repositories"
-	"contact-management/src/services"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
-
-	"github.com/julienschmidt/httprouter"
 )
 
-func setupBrandProductRouter() *httprouter.Router {
-	cfg := config.LoadConfig()
-	db, err := apps.Connect(cfg)
-	if err != nil {
-		panic("Failed to connect to database: " + err.Error())
-	}
-
-	brandProductRepo := repositories.NewBrandProductRepository(db)
-	brandProductService := services.NewBrandProductService(brandProductRepo)
-	brandProductController := controllers.NewBrandProductController(brandProductService)
-
-	router := httprouter.New()
-	router.GET("/brand-products", middlewares.AuthMiddleware(brandProductController.GetAllBrandProducts))
-	router.POST("/brand-products", middlewares.AuthMiddleware(brandProductController.CreateBrandProduct))
-	router.GET("/brand-products/:id", middlewares.AuthMiddleware(brandProductController.GetBrandProductByID))
-	router.PUT("/brand-products/:id", middlewares.AuthMiddleware(brandProductController.UpdateBrandProduct))
-	router.DELETE("/brand-products/:id", middlewares.AuthMiddleware(brandProductController.DeleteBrandProduct))
-
-	return router
-}
-
 // Helper to create a test category for brand products
 func createTestCategoryForBrand(t *testing.T, token string) int {
 	t.Helper()
 
-	router := setupCategoryRouter()
+	router := testRouter()
 	body := map[string]interface{}{
 		"name": "Test Category For Brands",
 	}
@@ -54,7 +25,7 @@ func createTestCategoryForBrand(t *testing.T, token string) int {
 }
 
 func TestGetAllBrandProducts(t *testing.T) {
-	router := setupBrandProductRouter()
+	router := testRouter()
 	token := getValidToken(t, "testuser_brand")
 	defer cleanupTestUser(t, "testuser_brand")
 
@@ -76,7 +47,7 @@ func TestGetAllBrandProducts(t *testing.T) {
 }
 
 func TestCreateBrandProduct(t *testing.T) {
-	router := setupBrandProductRouter()
+	router := testRouter()
 	token := getValidToken(t, "testuser_create_brand")
 	defer cleanupTestUser(t, "testuser_create_brand")
 
@@ -132,7 +103,7 @@ func TestCreateBrandProduct(t *testing.T) {
 }
 
 func TestGetBrandProductByID(t *testing.T) {
-	router := setupBrandProductRouter()
+	router := testRouter()
 	token := getValidToken(t, "testuser_get_brand")
 	defer cleanupTestUser(t, "testuser_get_brand")
 
@@ -178,7 +149,7 @@ func TestGetBrandProductByID(t *testing.T) {
 }
 
 func TestUpdateBrandProduct(t *testing.T) {
-	router := setupBrandProductRouter()
+	router := testRouter()
 	token := getValidToken(t, "testuser_update_brand")
 	defer cleanupTestUser(t, "testuser_update_brand")
 
@@ -239,7 +210,7 @@ func TestUpdateBrandProduct(t *testing.T) {
 }
 
 func TestDeleteBrandProduct(t *testing.T) {
-	router := setupBrandProductRouter()
+	router := testRouter()
 	token := getValidToken(t, "testuser_delete_brand")
 	defer cleanupTestUser(t, "testuser_delete_brand")
 
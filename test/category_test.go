@@ -1,43 +1,15 @@
 package test
 
 import (
-	"contact-management/src/apps"
-	"contact-management/src/config"
-	"contact-management/src/controllers"
-	"contact-management/src/middlewares"
-	"contact-management/src/repositories"
-	"contact-management/src/services"
+	"contact-management/src/models"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
-
-	"github.com/julienschmidt/httprouter"
 )
 
-func setupCategoryRouter() *httprouter.Router {
-	cfg := config.LoadConfig()
-	db, err := apps.Connect(cfg)
-	if err != nil {
-		panic("Failed to connect to database: " + err.Error())
-	}
-
-	categoryRepo := repositories.NewCategoryRepository(db)
-	categoryService := services.NewCategoryService(categoryRepo)
-	categoryController := controllers.NewCategoryController(categoryService)
-
-	router := httprouter.New()
-	router.GET("/categories", middlewares.AuthMiddleware(categoryController.GetAllCategories))
-	router.POST("/categories", middlewares.AuthMiddleware(categoryController.CreateCategory))
-	router.GET("/categories/:id", middlewares.AuthMiddleware(categoryController.GetCategoryByID))
-	router.PUT("/categories/:id", middlewares.AuthMiddleware(categoryController.UpdateCategory))
-	router.DELETE("/categories/:id", middlewares.AuthMiddleware(categoryController.DeleteCategory))
-
-	return router
-}
-
 func TestGetAllCategories(t *testing.T) {
-	router := setupCategoryRouter()
+	router := testRouter()
 	token := getValidToken(t, "testuser_category")
 	defer cleanupTestUser(t, "testuser_category")
 
@@ -59,7 +31,7 @@ func TestGetAllCategories(t *testing.T) {
 }
 
 func TestCreateCategory(t *testing.T) {
-	router := setupCategoryRouter()
+	router := testRouter()
 	token := getValidToken(t, "testuser_create_cat")
 	defer cleanupTestUser(t, "testuser_create_cat")
 
@@ -107,7 +79,7 @@ func TestCreateCategory(t *testing.T) {
 }
 
 func TestGetCategoryByID(t *testing.T) {
-	router := setupCategoryRouter()
+	router := testRouter()
 	token := getValidToken(t, "testuser_get_cat")
 	defer cleanupTestUser(t, "testuser_get_cat")
 
@@ -150,7 +122,7 @@ func TestGetCategoryByID(t *testing.T) {
 }
 
 func TestUpdateCategory(t *testing.T) {
-	router := setupCategoryRouter()
+	router := testRouter()
 	token := getValidToken(t, "testuser_update_cat")
 	defer cleanupTestUser(t, "testuser_update_cat")
 
@@ -203,7 +175,7 @@ func TestUpdateCategory(t *testing.T) {
 }
 
 func TestDeleteCategory(t *testing.T) {
-	router := setupCategoryRouter()
+	router := testRouter()
 	token := getValidToken(t, "testuser_delete_cat")
 	defer cleanupTestUser(t, "testuser_delete_cat")
 
@@ -244,3 +216,52 @@ func TestDeleteCategory(t *testing.T) {
 		assertResponseStatus(t, "error", response)
 	})
 }
+
+func TestCategoryPermissions(t *testing.T) {
+	router := testRouter()
+	staffToken := getValidTokenWithRole(t, "testuser_category_staff", models.RoleStaff)
+	customerToken := getValidTokenWithRole(t, "testuser_category_customer", models.RoleCustomer)
+	defer cleanupTestUser(t, "testuser_category_staff")
+	defer cleanupTestUser(t, "testuser_category_customer")
+
+	t.Run("Success - Staff can create category", func(t *testing.T) {
+		body := map[string]interface{}{
+			"name": "Test Category Staff",
+		}
+
+		rr := makeRequest(t, router, "POST", "/categories", body, staffToken)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusCreated, rr.Code)
+
+		var data map[string]interface{}
+		json.Unmarshal(response.Data, &data)
+		if categoryID, ok := data["category_id"].(float64); ok {
+			defer cleanupTestCategory(t, int(categoryID))
+		}
+	})
+
+	t.Run("Success - Customer can read categories", func(t *testing.T) {
+		rr := makeRequest(t, router, "GET", "/categories", nil, customerToken)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Error - Customer cannot create category", func(t *testing.T) {
+		body := map[string]interface{}{
+			"name": "Test Category Customer",
+		}
+
+		rr := makeRequest(t, router, "POST", "/categories", body, customerToken)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusForbidden, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Error - Customer cannot delete category", func(t *testing.T) {
+		rr := makeRequest(t, router, "DELETE", "/categories/1", nil, customerToken)
+
+		assertStatusCode(t, http.StatusForbidden, rr.Code)
+	})
+}
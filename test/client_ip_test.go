@@ -0,0 +1,49 @@
+package test
+
+import (
+	"contact-management/src/helpers"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIPResolver(t *testing.T) {
+	resolver, err := helpers.NewClientIPResolver([]string{"10.0.0.1", "172.16.0.0/12"})
+	if err != nil {
+		t.Fatalf("Failed to create resolver: %v", err)
+	}
+
+	cases := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		expected  string
+	}{
+		{"Untrusted connection ignores the header", "203.0.113.7:1234", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"Trusted proxy without header", "10.0.0.1:1234", nil, "10.0.0.1"},
+		{"Trusted proxy forwards the client", "10.0.0.1:1234", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"Spoofed left-most hop is skipped", "10.0.0.1:1234", []string{"1.2.3.4, 198.51.100.1"}, "198.51.100.1"},
+		{"Chain of trusted proxies", "10.0.0.1:1234", []string{"1.2.3.4, 198.51.100.1, 172.16.5.5"}, "198.51.100.1"},
+		{"Repeated headers are one list", "10.0.0.1:1234", []string{"1.2.3.4", "198.51.100.1"}, "198.51.100.1"},
+		{"Invalid hop stops the walk", "10.0.0.1:1234", []string{"198.51.100.1, garbage"}, "10.0.0.1"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = c.remote
+			for _, value := range c.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+
+			if got := resolver.Resolve(req); got != c.expected {
+				t.Errorf("Expected %s, got %s", c.expected, got)
+			}
+		})
+	}
+
+	t.Run("Error - Invalid proxy", func(t *testing.T) {
+		if _, err := helpers.NewClientIPResolver([]string{"not-an-ip"}); err == nil {
+			t.Error("Expected an invalid proxy to be rejected")
+		}
+	})
+}
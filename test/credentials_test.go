@@ -0,0 +1,209 @@
+package test
+
+import (
+	"contact-management/src/gateways"
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"contact-management/src/services"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// payTestOrder creates an order, pays it through a signed fake webhook and returns its ID and code
+func payTestOrder(t *testing.T, router *httprouter.Router, gateway *gateways.FakeGateway, productID int) (int, string) {
+	t.Helper()
+
+	body := map[string]interface{}{
+		"product_id": productID,
+		"name":       "Test Buyer",
+		"email":      "buyer@example.com",
+		"method":     "qris",
+	}
+	rr := makeRequest(t, router, "POST", "/orders", body, "")
+	response := parseResponse(t, rr)
+
+	var order map[string]interface{}
+	json.Unmarshal(response.Data, &order)
+	orderID := int(order["order_id"].(float64))
+
+	externalID := createTestPayment(t, router, orderID)
+	webhook, signature := gateway.BuildWebhook("evt-"+externalID, externalID, "paid")
+	sendWebhook(t, router, "fake", webhook, signature)
+
+	return orderID, order["order_code"].(string)
+}
+
+func TestCredentialDelivery(t *testing.T) {
+	router, gateway := testRouter(), testGateway()
+	token := getValidToken(t, "testuser_credential")
+	defer cleanupTestUser(t, "testuser_credential")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 1)
+	defer cleanupTestProduct(t, productID)
+	defer cleanupTestOrdersOfProduct(t, productID)
+	defer cleanupTestCredentials(t, productID)
+
+	var orderIDs []int
+	defer func() {
+		for _, orderID := range orderIDs {
+			cleanupTestPayments(t, orderID)
+		}
+	}()
+
+	t.Run("Success - Bulk upload credentials", func(t *testing.T) {
+		body := map[string]interface{}{
+			"credentials": []string{"user1@example.com:secret1"},
+		}
+
+		rr := makeRequest(t, router, "POST", fmt.Sprintf("/products/%d/credentials", productID), body, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusCreated, rr.Code)
+		assertResponseStatus(t, "success", response)
+
+		if stock := getProductStock(t, productID); stock != 2 {
+			t.Errorf("Expected upload to add stock, got %d", stock)
+		}
+	})
+
+	t.Run("Error - Empty upload", func(t *testing.T) {
+		body := map[string]interface{}{
+			"credentials": []string{},
+		}
+
+		rr := makeRequest(t, router, "POST", fmt.Sprintf("/products/%d/credentials", productID), body, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Success - Paid order receives credential", func(t *testing.T) {
+		orderID, orderCode := payTestOrder(t, router, gateway, productID)
+		orderIDs = append(orderIDs, orderID)
+
+		if status := getOrderStatus(t, router, orderID, token); status != "fulfilled" {
+			t.Fatalf("Expected order to be fulfilled, got %s", status)
+		}
+
+		rr := makeRequest(t, router, "POST", "/credentials/lookup", map[string]interface{}{
+			"order_code": orderCode,
+			"email":      "BUYER@example.com",
+		}, "")
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		var data map[string]interface{}
+		json.Unmarshal(response.Data, &data)
+		if data["secret"] != "user1@example.com:secret1" {
+			t.Errorf("Expected delivered secret, got %v", data["secret"])
+		}
+	})
+
+	t.Run("Error - Lookup with wrong email", func(t *testing.T) {
+		orderID, orderCode := payTestOrder(t, router, gateway, productID)
+		orderIDs = append(orderIDs, orderID)
+
+		rr := makeRequest(t, router, "POST", "/credentials/lookup", map[string]interface{}{
+			"order_code": orderCode,
+			"email":      "someone@example.com",
+		}, "")
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusNotFound, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Success - Empty pool keeps order paid until restock", func(t *testing.T) {
+		// the previous subtest consumed the last product unit without a credential
+		if len(orderIDs) < 2 {
+			t.Skip("Previous subtests did not create the paid order")
+		}
+		orderID := orderIDs[1]
+
+		if status := getOrderStatus(t, router, orderID, token); status != "paid" {
+			t.Fatalf("Expected order to stay paid, got %s", status)
+		}
+
+		rr := makeRequest(t, router, "POST", fmt.Sprintf("/orders/%d/deliver", orderID), nil, token)
+		assertStatusCode(t, http.StatusConflict, rr.Code)
+
+		makeRequest(t, router, "POST", fmt.Sprintf("/products/%d/credentials", productID), map[string]interface{}{
+			"credentials": []string{"user2@example.com:secret2"},
+		}, token)
+
+		rr = makeRequest(t, router, "POST", fmt.Sprintf("/orders/%d/deliver", orderID), nil, token)
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		if status := getOrderStatus(t, router, orderID, token); status != "fulfilled" {
+			t.Errorf("Expected order to be fulfilled after restock, got %s", status)
+		}
+	})
+}
+
+// failingCredentialRepository fails every assignment with err, like a database error
+// hit while the payment transaction is open
+type failingCredentialRepository struct {
+	repositories.CredentialRepository
+	err error
+}
+
+func (f failingCredentialRepository) AssignCredential(ctx context.Context, orderID, productID int) (*models.Credential, error) {
+	return nil, f.err
+}
+
+func TestFulfilDeliveryErrors(t *testing.T) {
+	token := getValidToken(t, "testuser_fulfil_errors")
+	defer cleanupTestUser(t, "testuser_fulfil_errors")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 2)
+	defer cleanupTestProduct(t, productID)
+	defer cleanupTestOrdersOfProduct(t, productID)
+
+	// orderServiceWith builds an order service whose credential delivery fails with err
+	orderServiceWith := func(err error) *services.OrderService {
+		db := repositories.NewDatabase(testApp().DB, repositories.Dialect(testConfig().Database.Driver), testConfig().Database.QueryTimeout)
+		orderRepo := repositories.NewOrderRepository(db)
+		credentialService := services.NewCredentialService(failingCredentialRepository{repositories.NewCredentialRepository(db), err}, orderRepo, nil)
+		return services.NewOrderService(orderRepo, repositories.NewPaymentRepository(db), credentialService, db, testGateway(), testLogger())
+	}
+
+	t.Run("Error - Database error rolls the payment back", func(t *testing.T) {
+		orderID := createTestOrder(t, productID)
+		dbErr := errors.New("deadlock found when trying to get lock")
+
+		_, err := orderServiceWith(dbErr).TransitionOrder(context.Background(), orderID, models.OrderStatusPaid)
+		if !errors.Is(err, dbErr) {
+			t.Fatalf("Expected the delivery error, got %v", err)
+		}
+
+		order, err := testApp().OrderService.GetOrderByID(context.Background(), orderID)
+		if err != nil || order.Status != models.OrderStatusPending {
+			t.Errorf("Expected order to stay pending, got %+v (%v)", order, err)
+		}
+	})
+
+	t.Run("Success - No credential available leaves the order paid", func(t *testing.T) {
+		orderID := createTestOrder(t, productID)
+
+		order, err := orderServiceWith(repositories.ErrorCredentialUnavailable).TransitionOrder(context.Background(), orderID, models.OrderStatusPaid)
+		if err != nil || order.Status != models.OrderStatusPaid {
+			t.Errorf("Expected order to be paid, got %+v (%v)", order, err)
+		}
+	})
+}
@@ -2,14 +2,27 @@ package test
 
 import (
 	"bytes"
+	"contact-management/src/app"
 	"contact-management/src/apps"
 	"contact-management/src/config"
+	"contact-management/src/gateways"
+	"contact-management/src/helpers"
+	"contact-management/src/migrations"
+	"contact-management/src/models"
 	"contact-management/src/utils"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/sirupsen/logrus"
 )
 
 // TestResponse represents a generic API response
@@ -61,50 +74,210 @@ func parseResponse(t *testing.T, rr *httptest.ResponseRecorder) TestResponse {
 	return response
 }
 
-// getValidToken creates a test user and returns a valid JWT token
+var (
+	sharedLogger     *logrus.Logger
+	sharedLoggerOnce sync.Once
+)
+
+// testLogger returns one logger for the whole test run that discards its output,
+// and installs it in helpers so error responses do not write log files during tests
+func testLogger() *logrus.Logger {
+	sharedLoggerOnce.Do(func() {
+		sharedLogger = logrus.New()
+		sharedLogger.SetOutput(io.Discard)
+		helpers.SetLogger(sharedLogger)
+	})
+	return sharedLogger
+}
+
+var (
+	sharedTokenStore     utils.TokenStore
+	sharedTokenStoreOnce sync.Once
+)
+
+// testTokenStore returns the in-memory token store shared by testTokenService and
+// testApp, so that tokens issued by helpers are accepted by the application router
+func testTokenStore() utils.TokenStore {
+	sharedTokenStoreOnce.Do(func() {
+		sharedTokenStore = utils.NewMemoryTokenStore()
+	})
+	return sharedTokenStore
+}
+
+var (
+	sharedTokenService     *utils.TokenService
+	sharedTokenServiceOnce sync.Once
+)
+
+// testTokenService returns one token service for the whole test run. It does not need
+// a database, so tests that only exercise tokens or middleware can use it on their own
+func testTokenService() *utils.TokenService {
+	sharedTokenServiceOnce.Do(func() {
+		tokenService, err := utils.NewTokenService(testConfig(), testTokenStore(), testLogger())
+		if err != nil {
+			panic("Failed to create token service: " + err.Error())
+		}
+		sharedTokenService = tokenService
+	})
+	return sharedTokenService
+}
+
+// fallbackSQLitePath is the temporary SQLite database used when DB_DRIVER is not set,
+// so a plain go test works on a fresh checkout without a MySQL server. TestMain
+// creates and removes it
+var fallbackSQLitePath string
+
+// TestMain builds the shared application before any test runs, so a database that
+// cannot be reached fails the run with one clear message instead of a panic
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "contact-management-test-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to create a temporary directory:", err)
+		os.Exit(1)
+	}
+	fallbackSQLitePath = filepath.Join(dir, "test.db")
+
+	code := 1
+	if err := buildTestApp(); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to set up the test application:", err)
+		fmt.Fprintln(os.Stderr, "Leave DB_DRIVER unset (in the environment and .env) to test against a temporary SQLite database, or point the DB_* variables at a reachable MySQL server")
+	} else {
+		code = m.Run()
+		sharedApp.Close()
+	}
+
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// testConfig returns the configuration loaded from the environment with the
+// credential encryption key fixed to testEncryptionKey and the JWT secret falling back
+// to testJWTSecret. Rate limiting is disabled
+// because every test request comes from the same address; rate limit tests enable it.
+// That address, httptest's 192.0.2.1, is trusted as a reverse proxy so tests can set
+// the client IP through X-Forwarded-For
+func testConfig() *config.Config {
+	cfg := config.LoadConfig()
+	if os.Getenv("DB_DRIVER") == "" {
+		cfg.Database.Driver = config.DriverSQLite
+		cfg.Database.Name = fallbackSQLitePath
+	}
+	cfg.Credential.EncryptionKey = testEncryptionKey
+	if cfg.JWT.Secret == "" {
+		cfg.JWT.Secret = testJWTSecret
+	}
+	cfg.App.TrustedProxies = []string{"192.0.2.1"}
+	cfg.RateLimit.Enabled = false
+	cfg.Login.MaxFailures = 0
+	cfg.Login.MaxFailuresPerIP = 0
+	cfg.Login.DelayBase = 0
+	return cfg
+}
+
+var (
+	sharedApp     *app.App
+	sharedGateway *gateways.FakeGateway
+)
+
+// buildTestApp builds the application shared by every test exactly like production,
+// with the shared token store, a discarding logger and a fake payment gateway swapped
+// in. With DB_DRIVER=sqlite the embedded SQLite migrations are applied first
+func buildTestApp() error {
+	sharedGateway = gateways.NewFakeGateway(config.PaymentConfig{
+		InvoiceDuration: time.Hour,
+		CallbackToken:   "test-callback-token",
+	})
+
+	cfg := testConfig()
+	application, err := app.New(cfg,
+		app.WithLogger(testLogger()),
+		app.WithTokenStore(testTokenStore()),
+		app.WithGateway(sharedGateway),
+	)
+	if err != nil {
+		return err
+	}
+
+	// A SQLite database is a fresh temporary file, so the schema is created here
+	if cfg.Database.Driver == config.DriverSQLite {
+		migrator, err := apps.NewMigrator(application.DB, cfg.Database.Driver, migrations.ForDriver(cfg.Database.Driver), testLogger())
+		if err != nil {
+			application.Close()
+			return fmt.Errorf("load migrations: %w", err)
+		}
+		if _, err := migrator.Up(context.Background()); err != nil {
+			application.Close()
+			return fmt.Errorf("migrate SQLite database: %w", err)
+		}
+	}
+	sharedApp = application
+	return nil
+}
+
+// testApp returns the application built by TestMain
+func testApp() *app.App {
+	return sharedApp
+}
+
+// testRouter returns the production router of testApp
+func testRouter() *httprouter.Router {
+	return testApp().Router
+}
+
+// testGateway returns the fake payment gateway wired into testApp
+func testGateway() *gateways.FakeGateway {
+	return sharedGateway
+}
+
+// getValidToken returns a valid admin JWT token for username
 func getValidToken(t *testing.T, username string) string {
 	t.Helper()
 
-	token, err := utils.GenerateToken(username)
+	return getValidTokenWithRole(t, username, models.RoleAdmin)
+}
+
+// getValidTokenWithRole returns a valid JWT token for username carrying the given role
+func getValidTokenWithRole(t *testing.T, username string, role models.Role) string {
+	t.Helper()
+
+	tokens, err := testTokenService().GenerateTokenPair(context.Background(), username, role, models.SessionMeta{})
 	if err != nil {
 		t.Fatalf("Failed to generate token: %v", err)
 	}
 
-	return token
+	return tokens.Token
 }
 
-// cleanupTestUser removes test user from database and Redis
+// cleanupTestUser removes test user, its audit logs and its sessions
 func cleanupTestUser(t *testing.T, username string) {
 	t.Helper()
 
-	cfg := config.LoadConfig()
-	db, err := apps.Connect(cfg)
-	if err != nil {
-		t.Fatalf("Failed to connect to database: %v", err)
-	}
-	defer db.Close()
+	db := testApp().DB
 
 	// Delete user from database
-	_, err = db.Exec("DELETE FROM users WHERE username = ?", username)
+	_, err := db.Exec("DELETE FROM users WHERE username = ?", username)
 	if err != nil {
 		t.Logf("Warning: Failed to cleanup user %s: %v", username, err)
 	}
 
-	// Clear token from Redis (no cleanup needed - handled by expiration)
+	// Delete the audit trail the user left behind
+	if _, err := db.Exec("DELETE FROM audit_logs WHERE actor = ?", username); err != nil {
+		t.Logf("Warning: Failed to cleanup audit logs of %s: %v", username, err)
+	}
+
+	// Clear sessions from the token store
+	if err := testTokenService().RevokeAllSessions(context.Background(), username); err != nil {
+		t.Logf("Warning: Failed to cleanup sessions of %s: %v", username, err)
+	}
 }
 
 // cleanupTestCategory removes test category from database
 func cleanupTestCategory(t *testing.T, categoryID int) {
 	t.Helper()
 
-	cfg := config.LoadConfig()
-	db, err := apps.Connect(cfg)
-	if err != nil {
-		t.Fatalf("Failed to connect to database: %v", err)
-	}
-	defer db.Close()
+	db := testApp().DB
 
-	_, err = db.Exec("DELETE FROM category WHERE category_id = ?", categoryID)
+	_, err := db.Exec("DELETE FROM category WHERE category_id = ?", categoryID)
 	if err != nil {
 		t.Logf("Warning: Failed to cleanup category %d: %v", categoryID, err)
 	}
@@ -114,19 +287,20 @@ func cleanupTestCategory(t *testing.T, categoryID int) {
 func cleanupTestBrandProduct(t *testing.T, brandProductID int) {
 	t.Helper()
 
-	cfg := config.LoadConfig()
-	db, err := apps.Connect(cfg)
-	if err != nil {
-		t.Fatalf("Failed to connect to database: %v", err)
-	}
-	defer db.Close()
+	db := testApp().DB
 
-	_, err = db.Exec("DELETE FROM brand_products WHERE brand_product_id = ?", brandProductID)
+	_, err := db.Exec("DELETE FROM brand_products WHERE brand_product_id = ?", brandProductID)
 	if err != nil {
 		t.Logf("Warning: Failed to cleanup brand product %d: %v", brandProductID, err)
 	}
 }
 
+// testEncryptionKey is the AES-256 key used to encrypt credentials in tests
+const testEncryptionKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+// testJWTSecret signs tokens in tests when JWT_SECRET is not set in the environment
+const testJWTSecret = "test-jwt-secret"
+
 // assertStatusCode checks if the response status code matches expected
 func assertStatusCode(t *testing.T, expected, got int) {
 	t.Helper()
@@ -142,3 +316,56 @@ func assertResponseStatus(t *testing.T, expected string, response TestResponse)
 		t.Errorf("Expected status %s, got %s", expected, response.Status)
 	}
 }
+
+// cleanupTestProduct removes test product from database
+func cleanupTestProduct(t *testing.T, productID int) {
+	t.Helper()
+
+	db := testApp().DB
+
+	_, err := db.Exec("DELETE FROM products WHERE product_id = ?", productID)
+	if err != nil {
+		t.Logf("Warning: Failed to cleanup product %d: %v", productID, err)
+	}
+}
+
+// cleanupTestOrder removes test order from database
+func cleanupTestOrder(t *testing.T, orderID int) {
+	t.Helper()
+
+	db := testApp().DB
+
+	_, err := db.Exec("DELETE FROM orders WHERE order_id = ?", orderID)
+	if err != nil {
+		t.Logf("Warning: Failed to cleanup order %d: %v", orderID, err)
+	}
+}
+
+// cleanupTestPayments removes all payments of a test order from database
+func cleanupTestPayments(t *testing.T, orderID int) {
+	t.Helper()
+
+	db := testApp().DB
+
+	_, err := db.Exec("DELETE FROM payment_webhook_events WHERE payment_id IN (SELECT payment_id FROM payments WHERE order_id = ?)", orderID)
+	if err != nil {
+		t.Logf("Warning: Failed to cleanup webhook events of order %d: %v", orderID, err)
+	}
+
+	_, err = db.Exec("DELETE FROM payments WHERE order_id = ?", orderID)
+	if err != nil {
+		t.Logf("Warning: Failed to cleanup payments of order %d: %v", orderID, err)
+	}
+}
+
+// cleanupTestCredentials removes all credentials of a test product from database
+func cleanupTestCredentials(t *testing.T, productID int) {
+	t.Helper()
+
+	db := testApp().DB
+
+	_, err := db.Exec("DELETE FROM product_credentials WHERE product_id = ?", productID)
+	if err != nil {
+		t.Logf("Warning: Failed to cleanup credentials of product %d: %v", productID, err)
+	}
+}
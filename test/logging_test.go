@@ -0,0 +1,120 @@
+package test
+
+import (
+	"contact-management/src/apps"
+	"contact-management/src/config"
+	"contact-management/src/controllers"
+	"contact-management/src/middlewares"
+	"contact-management/src/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/sirupsen/logrus"
+)
+
+func TestNewLogger(t *testing.T) {
+	t.Run("Success - JSON output to rotated file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "app.log")
+		logger, err := apps.NewLogger(config.LogConfig{
+			File:      file,
+			Level:     "debug",
+			Format:    "json",
+			Output:    "file",
+			MaxSizeMB: 1,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create logger: %v", err)
+		}
+
+		logger.WithField("order_id", 7).Debug("hello")
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read log file: %v", err)
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(string(content))), &entry); err != nil {
+			t.Fatalf("Expected JSON log line, got %q", content)
+		}
+		if entry["msg"] != "hello" || entry["order_id"] != float64(7) {
+			t.Errorf("Unexpected log entry: %v", entry)
+		}
+	})
+
+	cases := map[string]config.LogConfig{
+		"Error - Unknown level":  {Level: "loud", Format: "text", Output: "stdout"},
+		"Error - Unknown format": {Level: "info", Format: "xml", Output: "stdout"},
+		"Error - Unknown output": {Level: "info", Format: "text", Output: "syslog"},
+		"Error - Missing file":   {Level: "info", Format: "text", Output: "file"},
+	}
+	for name, cfg := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := apps.NewLogger(cfg); err == nil {
+				t.Error("Expected invalid log config to be rejected")
+			}
+		})
+	}
+}
+
+func setupLogRouter(logger *logrus.Logger) *httprouter.Router {
+	logController := controllers.NewLogController(logger)
+
+	authMiddleware := middlewares.AuthMiddleware(testTokenService())
+	manageSystem := middlewares.RequirePermission(models.PermissionSystemManage)
+
+	router := httprouter.New()
+	router.GET("/admin/log-level", authMiddleware(manageSystem(logController.GetLogLevel)))
+	router.PUT("/admin/log-level", authMiddleware(manageSystem(logController.UpdateLogLevel)))
+
+	return router
+}
+
+func TestLogLevelEndpoint(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(testLogger().Out)
+	logger.SetLevel(logrus.InfoLevel)
+	router := setupLogRouter(logger)
+
+	adminToken := getValidTokenWithRole(t, "testuser_log_admin", models.RoleAdmin)
+	staffToken := getValidTokenWithRole(t, "testuser_log_staff", models.RoleStaff)
+	defer testTokenService().RevokeAllSessions(context.Background(), "testuser_log_admin")
+	defer testTokenService().RevokeAllSessions(context.Background(), "testuser_log_staff")
+
+	t.Run("Success - Admin changes level at runtime", func(t *testing.T) {
+		body := map[string]interface{}{"level": "debug"}
+		rr := makeRequest(t, router, "PUT", "/admin/log-level", body, adminToken)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+		if logger.GetLevel() != logrus.DebugLevel {
+			t.Errorf("Expected level debug, got %s", logger.GetLevel())
+		}
+
+		rr = makeRequest(t, router, "GET", "/admin/log-level", nil, adminToken)
+		response := parseResponse(t, rr)
+		var data models.LogLevelResponse
+		json.Unmarshal(response.Data, &data)
+		if data.Level != "debug" {
+			t.Errorf("Expected reported level debug, got %s", data.Level)
+		}
+	})
+
+	t.Run("Error - Invalid level", func(t *testing.T) {
+		body := map[string]interface{}{"level": "loud"}
+		rr := makeRequest(t, router, "PUT", "/admin/log-level", body, adminToken)
+
+		assertStatusCode(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+
+	t.Run("Error - Staff cannot change level", func(t *testing.T) {
+		body := map[string]interface{}{"level": "trace"}
+		rr := makeRequest(t, router, "PUT", "/admin/log-level", body, staffToken)
+
+		assertStatusCode(t, http.StatusForbidden, rr.Code)
+	})
+}
@@ -0,0 +1,213 @@
+package test
+
+import (
+	"bytes"
+	"contact-management/src/app"
+	"contact-management/src/apps"
+	"contact-management/src/config"
+	"contact-management/src/models"
+	"contact-management/src/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLoginGuard(t *testing.T) {
+	cfg := testConfig()
+	cfg.Login = config.LoginConfig{
+		MaxFailures:      3,
+		MaxFailuresPerIP: 5,
+		FailureWindow:    15 * time.Minute,
+		LockoutDuration:  15 * time.Minute,
+		DelayBase:        20 * time.Millisecond,
+		DelayMax:         40 * time.Millisecond,
+	}
+
+	clock := &fakeClock{now: time.Now()}
+	application, err := app.New(cfg,
+		app.WithDB(testApp().DB),
+		app.WithLogger(testLogger()),
+		app.WithTokenStore(testTokenStore()),
+		app.WithGateway(testGateway()),
+		app.WithClock(clock.Now),
+	)
+	if err != nil {
+		t.Fatalf("Failed to build application: %v", err)
+	}
+	defer application.Close()
+	router := application.Router
+	defer cleanupTestUser(t, "testuser_guard")
+	defer cleanupTestUser(t, "testuser_guard_admin")
+
+	// login sends the request from ip so each subtest keeps its own per-IP counter
+	login := func(username, password, ip string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{
+			"username": username,
+			"password": password,
+		})
+		req := httptest.NewRequest("POST", "/login", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = ip + ":1234"
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// adminToken is issued by the application's token service so it follows the fake clock
+	adminToken := func() string {
+		tokens, err := application.TokenService.GenerateTokenPair(context.Background(), "testuser_guard_admin", models.RoleAdmin, models.SessionMeta{})
+		if err != nil {
+			t.Fatalf("Failed to generate token: %v", err)
+		}
+		return tokens.Token
+	}
+
+	rr := makeRequest(t, router, "POST", "/register", map[string]interface{}{
+		"username": "testuser_guard",
+		"password": "password123",
+	}, "")
+	assertStatusCode(t, http.StatusCreated, rr.Code)
+
+	t.Run("Error - Username is locked after too many failures", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			assertStatusCode(t, http.StatusUnauthorized, login("testuser_guard", "wrongpassword", "198.51.100.1").Code)
+		}
+
+		rr := login("testuser_guard", "wrongpassword", "198.51.100.1")
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusLocked, rr.Code)
+		assertResponseStatus(t, "error", response)
+		if got := rr.Header().Get("Retry-After"); got != "900" {
+			t.Errorf("Expected Retry-After 900, got %q", got)
+		}
+	})
+
+	t.Run("Error - Correct password is rejected while locked", func(t *testing.T) {
+		rr := login("testuser_guard", "password123", "198.51.100.2")
+
+		assertStatusCode(t, http.StatusLocked, rr.Code)
+	})
+
+	t.Run("Error - Unlock unknown user", func(t *testing.T) {
+		rr := makeRequest(t, router, "DELETE", "/users/nonexistent_user/lock", nil, adminToken())
+
+		assertStatusCode(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("Success - Admin unlocks the account", func(t *testing.T) {
+		rr := makeRequest(t, router, "DELETE", "/users/testuser_guard/lock", nil, adminToken())
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+		assertResponseStatus(t, "success", response)
+
+		assertStatusCode(t, http.StatusOK, login("testuser_guard", "password123", "198.51.100.1").Code)
+	})
+
+	t.Run("Error - IP is locked across usernames", func(t *testing.T) {
+		for _, username := range []string{"guard_a", "guard_b", "guard_c", "guard_d"} {
+			assertStatusCode(t, http.StatusUnauthorized, login(username, "wrongpassword", "198.51.100.3").Code)
+		}
+
+		rr := login("guard_e", "wrongpassword", "198.51.100.3")
+		assertStatusCode(t, http.StatusTooManyRequests, rr.Code)
+		if got := rr.Header().Get("Retry-After"); got != "900" {
+			t.Errorf("Expected Retry-After 900, got %q", got)
+		}
+
+		assertStatusCode(t, http.StatusTooManyRequests, login("testuser_guard", "password123", "198.51.100.3").Code)
+		assertStatusCode(t, http.StatusOK, login("testuser_guard", "password123", "198.51.100.4").Code)
+	})
+
+	t.Run("Error - Spoofed X-Forwarded-For does not reset the IP counter", func(t *testing.T) {
+		// loginSpoofed sends through the full handler from an address that is not a trusted proxy
+		loginSpoofed := func(username, password string, i int) int {
+			body, _ := json.Marshal(map[string]interface{}{
+				"username": username,
+				"password": password,
+			})
+			req := httptest.NewRequest("POST", "/login", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", 100+i))
+			req.RemoteAddr = "203.0.113.60:1234"
+
+			rr := httptest.NewRecorder()
+			application.Handler.ServeHTTP(rr, req)
+			return rr.Code
+		}
+
+		for i := 0; i < 4; i++ {
+			assertStatusCode(t, http.StatusUnauthorized, loginSpoofed(fmt.Sprintf("guard_spoof_%d", i), "wrongpassword", i))
+		}
+
+		assertStatusCode(t, http.StatusTooManyRequests, loginSpoofed("guard_spoof_4", "wrongpassword", 4))
+		assertStatusCode(t, http.StatusTooManyRequests, loginSpoofed("testuser_guard", "password123", 5))
+	})
+
+	t.Run("Success - Lock expires after the lockout duration", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			login("testuser_guard", "wrongpassword", "198.51.100.5")
+		}
+		assertStatusCode(t, http.StatusLocked, login("testuser_guard", "password123", "198.51.100.5").Code)
+
+		clock.Advance(15*time.Minute + time.Second)
+
+		assertStatusCode(t, http.StatusOK, login("testuser_guard", "password123", "198.51.100.5").Code)
+	})
+
+	t.Run("Success - Attempts after a failure are delayed", func(t *testing.T) {
+		assertStatusCode(t, http.StatusUnauthorized, login("testuser_guard", "wrongpassword", "198.51.100.6").Code)
+
+		start := time.Now()
+		assertStatusCode(t, http.StatusOK, login("testuser_guard", "password123", "198.51.100.6").Code)
+		if elapsed := time.Since(start); elapsed < cfg.Login.DelayBase {
+			t.Errorf("Expected login to wait at least %v, took %v", cfg.Login.DelayBase, elapsed)
+		}
+	})
+}
+
+func TestRedisLoginAttemptStore(t *testing.T) {
+	client := apps.NewRedisClient(testConfig().Redis)
+	defer client.Close()
+	if err := apps.PingRedis(client, time.Second); err != nil {
+		t.Skip("Redis is not reachable: ", err)
+	}
+
+	key := "user:test_redis_" + time.Now().Format(time.RFC3339Nano)
+	store := utils.NewRedisLoginAttemptStore(client)
+	ctx := context.Background()
+	defer store.Reset(ctx, key)
+
+	for i := 1; i <= 2; i++ {
+		failures, err := store.AddFailure(ctx, key, time.Minute)
+		if err != nil {
+			t.Fatalf("Failed to add failure: %v", err)
+		}
+		if failures != i {
+			t.Fatalf("Expected %d failures, got %d", i, failures)
+		}
+	}
+
+	if err := store.Lock(ctx, key, time.Minute); err != nil {
+		t.Fatalf("Failed to lock: %v", err)
+	}
+	remaining, err := store.LockedFor(ctx, key)
+	if err != nil || remaining <= 0 || remaining > time.Minute {
+		t.Errorf("Expected a lock within a minute, got %v (%v)", remaining, err)
+	}
+
+	if err := store.Reset(ctx, key); err != nil {
+		t.Fatalf("Failed to reset: %v", err)
+	}
+	failures, _ := store.Failures(ctx, key)
+	remaining, _ = store.LockedFor(ctx, key)
+	if failures != 0 || remaining != 0 {
+		t.Errorf("Expected reset to clear failures and lock, got %d failures and %v lock", failures, remaining)
+	}
+}
@@ -0,0 +1,208 @@
+package test
+
+import (
+	"contact-management/src/apps"
+	"contact-management/src/config"
+	"contact-management/src/migrations"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	t.Run("Success - Embedded migrations are complete and ordered", func(t *testing.T) {
+		loaded, err := apps.LoadMigrations(migrations.FS)
+		if err != nil {
+			t.Fatalf("Failed to load embedded migrations: %v", err)
+		}
+		if len(loaded) == 0 {
+			t.Fatal("Expected embedded migrations")
+		}
+		for i, migration := range loaded {
+			if migration.Up == "" || migration.Down == "" || len(migration.Checksum) != 64 {
+				t.Errorf("Migration %d_%s is incomplete", migration.Version, migration.Name)
+			}
+			if i > 0 && loaded[i-1].Version >= migration.Version {
+				t.Errorf("Migrations not ordered at %d", migration.Version)
+			}
+		}
+	})
+
+	t.Run("Success - SQLite migrations mirror the MySQL versions", func(t *testing.T) {
+		mysqlMigrations, err := apps.LoadMigrations(migrations.ForDriver(config.DriverMySQL))
+		if err != nil {
+			t.Fatalf("Failed to load MySQL migrations: %v", err)
+		}
+		sqliteMigrations, err := apps.LoadMigrations(migrations.ForDriver(config.DriverSQLite))
+		if err != nil {
+			t.Fatalf("Failed to load SQLite migrations: %v", err)
+		}
+		if len(sqliteMigrations) != len(mysqlMigrations) {
+			t.Fatalf("Expected %d SQLite migrations, got %d", len(mysqlMigrations), len(sqliteMigrations))
+		}
+		for i := range mysqlMigrations {
+			if sqliteMigrations[i].Version != mysqlMigrations[i].Version || sqliteMigrations[i].Name != mysqlMigrations[i].Name {
+				t.Errorf("Expected SQLite migration %d_%s, got %d_%s", mysqlMigrations[i].Version, mysqlMigrations[i].Name, sqliteMigrations[i].Version, sqliteMigrations[i].Name)
+			}
+		}
+	})
+
+	t.Run("Success - Checksum changes when up file is edited", func(t *testing.T) {
+		original, _ := apps.LoadMigrations(fstest.MapFS{
+			"1_create_things.up.sql":   {Data: []byte("CREATE TABLE things (id INT);")},
+			"1_create_things.down.sql": {Data: []byte("DROP TABLE things;")},
+		})
+		edited, _ := apps.LoadMigrations(fstest.MapFS{
+			"1_create_things.up.sql":   {Data: []byte("CREATE TABLE things (id BIGINT);")},
+			"1_create_things.down.sql": {Data: []byte("DROP TABLE things;")},
+		})
+		if original[0].Checksum == edited[0].Checksum {
+			t.Error("Expected edited migration to have a different checksum")
+		}
+	})
+
+	t.Run("Success - Checksum changes when down file is edited", func(t *testing.T) {
+		original, _ := apps.LoadMigrations(fstest.MapFS{
+			"1_create_things.up.sql":   {Data: []byte("CREATE TABLE things (id INT);")},
+			"1_create_things.down.sql": {Data: []byte("DROP TABLE things;")},
+		})
+		edited, _ := apps.LoadMigrations(fstest.MapFS{
+			"1_create_things.up.sql":   {Data: []byte("CREATE TABLE things (id INT);")},
+			"1_create_things.down.sql": {Data: []byte("DROP TABLE IF EXISTS things;")},
+		})
+		if original[0].Checksum == edited[0].Checksum {
+			t.Error("Expected edited down file to change the checksum")
+		}
+	})
+
+	cases := map[string]fstest.MapFS{
+		"Error - Empty down file": {
+			"1_create_things.up.sql":   {Data: []byte("CREATE TABLE things (id INT);")},
+			"1_create_things.down.sql": {Data: []byte("  \n")},
+		},
+		"Error - Missing down file": {
+			"1_create_things.up.sql": {Data: []byte("CREATE TABLE things (id INT);")},
+		},
+		"Error - Invalid file name": {
+			"create_things.sql": {Data: []byte("CREATE TABLE things (id INT);")},
+		},
+		"Error - Version used twice": {
+			"1_create_things.up.sql":   {Data: []byte("CREATE TABLE things (id INT);")},
+			"1_create_things.down.sql": {Data: []byte("DROP TABLE things;")},
+			"1_create_others.up.sql":   {Data: []byte("CREATE TABLE others (id INT);")},
+			"1_create_others.down.sql": {Data: []byte("DROP TABLE others;")},
+		},
+	}
+	for name, fsys := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := apps.LoadMigrations(fsys); !errors.Is(err, apps.ErrInvalidMigration) {
+				t.Errorf("Expected ErrInvalidMigration, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSQLiteMigrator(t *testing.T) {
+	cfg := &config.Config{Database: config.DatabaseConfig{
+		Driver: config.DriverSQLite,
+		Name:   filepath.Join(t.TempDir(), "migrator.db"),
+	}}
+	db, err := apps.Connect(cfg)
+	if err != nil {
+		t.Fatalf("Failed to open SQLite database: %v", err)
+	}
+	defer db.Close()
+
+	migrator, err := apps.NewMigrator(db, cfg.Database.Driver, migrations.ForDriver(cfg.Database.Driver), testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create migrator: %v", err)
+	}
+	ctx := context.Background()
+
+	countTables := func() int {
+		var tables int
+		db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('users', 'orders', 'product_credentials')").Scan(&tables)
+		return tables
+	}
+
+	t.Run("Success - Up applies every migration", func(t *testing.T) {
+		count, err := migrator.Up(ctx)
+		if err != nil {
+			t.Fatalf("Failed to migrate up: %v", err)
+		}
+		statuses, _ := migrator.Status(ctx)
+		if count != len(statuses) {
+			t.Errorf("Expected %d migrations to run, got %d", len(statuses), count)
+		}
+		for _, status := range statuses {
+			if !status.Applied || status.Dirty || status.Modified {
+				t.Errorf("Unexpected status for %d_%s: %+v", status.Version, status.Name, status)
+			}
+		}
+		if tables := countTables(); tables != 3 {
+			t.Errorf("Expected application tables to exist, got %d", tables)
+		}
+	})
+
+	t.Run("Success - Up again is a no-op", func(t *testing.T) {
+		if count, err := migrator.Up(ctx); err != nil || count != 0 {
+			t.Errorf("Expected no migrations to run, got %d (%v)", count, err)
+		}
+	})
+
+	t.Run("Success - Down all removes the schema", func(t *testing.T) {
+		if _, err := migrator.Down(ctx, 0); err != nil {
+			t.Fatalf("Failed to migrate down: %v", err)
+		}
+		if tables := countTables(); tables != 0 {
+			t.Errorf("Expected application tables to be dropped, got %d", tables)
+		}
+	})
+}
+
+func TestSQLiteMigratorTriggerBlocks(t *testing.T) {
+	cfg := &config.Config{Database: config.DatabaseConfig{
+		Driver: config.DriverSQLite,
+		Name:   filepath.Join(t.TempDir(), "trigger.db"),
+	}}
+	db, err := apps.Connect(cfg)
+	if err != nil {
+		t.Fatalf("Failed to open SQLite database: %v", err)
+	}
+	defer db.Close()
+
+	// The statements inside a multi-line BEGIN ... END; body must stay in one statement
+	fsys := fstest.MapFS{
+		"1_create_things.up.sql": {Data: []byte(`CREATE TABLE things (id INTEGER PRIMARY KEY, name TEXT, edits INTEGER NOT NULL DEFAULT 0);
+CREATE TRIGGER trg_things_edits AFTER UPDATE OF name ON things
+FOR EACH ROW
+BEGIN
+    UPDATE things SET edits = edits + 1 WHERE id = NEW.id;
+    UPDATE things SET edits = edits + 1 WHERE id = NEW.id;
+END;
+INSERT INTO things (id, name) VALUES (1, 'first');
+`)},
+		"1_create_things.down.sql": {Data: []byte("DROP TRIGGER trg_things_edits;\nDROP TABLE things;\n")},
+	}
+	migrator, err := apps.NewMigrator(db, cfg.Database.Driver, fsys, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create migrator: %v", err)
+	}
+
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("Failed to migrate up: %v", err)
+	}
+	if _, err := db.Exec("UPDATE things SET name = 'second' WHERE id = 1"); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+
+	var edits int
+	if err := db.QueryRow("SELECT edits FROM things WHERE id = 1").Scan(&edits); err != nil {
+		t.Fatalf("Failed to read edits: %v", err)
+	}
+	if edits != 2 {
+		t.Errorf("Expected the whole trigger body to run, got %d edits", edits)
+	}
+}
@@ -0,0 +1,203 @@
+package test
+
+import (
+	"contact-management/src/config"
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ageTestOrder moves the creation time of an order back by age
+func ageTestOrder(t *testing.T, orderID int, age time.Duration) {
+	t.Helper()
+
+	createdAt := time.Now().Add(-age).UTC().Format("2006-01-02 15:04:05")
+	if _, err := testApp().DB.Exec("UPDATE orders SET created_at = ? WHERE order_id = ?", createdAt, orderID); err != nil {
+		t.Fatalf("Failed to age order %d: %v", orderID, err)
+	}
+}
+
+// getPaymentStatus reads the stored status of a payment by its external ID
+func getPaymentStatus(t *testing.T, externalID string) string {
+	t.Helper()
+
+	var status string
+	if err := testApp().DB.QueryRow("SELECT status FROM payments WHERE external_id = ?", externalID).Scan(&status); err != nil {
+		t.Fatalf("Failed to read payment %s: %v", externalID, err)
+	}
+	return status
+}
+
+func TestOrderExpiryCancelsPayments(t *testing.T) {
+	router, gateway := testRouter(), testGateway()
+	ttl := testApp().Config.Order.ReservationTTL
+	token := getValidToken(t, "testuser_order_expiry")
+	defer cleanupTestUser(t, "testuser_order_expiry")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 5)
+	defer cleanupTestProduct(t, productID)
+	defer cleanupTestOrdersOfProduct(t, productID)
+
+	// expire ages the order past the reservation and runs one pass of the expiry worker
+	expire := func(orderID int) {
+		t.Helper()
+		ageTestOrder(t, orderID, ttl+time.Minute)
+		if _, err := testApp().OrderService.ExpireStaleOrders(context.Background(), ttl); err != nil {
+			t.Fatalf("Failed to expire orders: %v", err)
+		}
+		if status := getOrderStatus(t, router, orderID, token); status != models.OrderStatusExpired {
+			t.Fatalf("Expected order to be expired, got %s", status)
+		}
+	}
+
+	t.Run("Success - Invoice never outlives the reservation", func(t *testing.T) {
+		orderID := createTestOrder(t, productID)
+		defer cleanupTestPayments(t, orderID)
+		ageTestOrder(t, orderID, ttl-30*time.Minute)
+
+		externalID := createTestPayment(t, router, orderID)
+		invoice, err := gateway.GetInvoice(externalID)
+		if err != nil {
+			t.Fatalf("Failed to get invoice: %v", err)
+		}
+		if limit := time.Now().Add(31 * time.Minute); invoice.ExpiresAt.After(limit) {
+			t.Errorf("Expected invoice to expire within the reservation, got %s", invoice.ExpiresAt)
+		}
+	})
+
+	t.Run("Error - Order past its reservation is not payable", func(t *testing.T) {
+		orderID := createTestOrder(t, productID)
+		defer cleanupTestPayments(t, orderID)
+		ageTestOrder(t, orderID, ttl+time.Minute)
+
+		rr := makeRequest(t, router, "POST", fmt.Sprintf("/orders/%d/payments", orderID), nil, "")
+
+		assertStatusCode(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("Error - Rolled back cancellation keeps the invoice open", func(t *testing.T) {
+		orderID := createTestOrder(t, productID)
+		defer cleanupTestPayments(t, orderID)
+		externalID := createTestPayment(t, router, orderID)
+
+		// The invoice is only cancelled at the gateway once the outer transaction commits
+		db := repositories.NewDatabase(testApp().DB, repositories.Dialect(testConfig().Database.Driver), testConfig().Database.QueryTimeout)
+		errAbort := errors.New("abort")
+		err := db.WithinTx(context.Background(), func(ctx context.Context) error {
+			if _, err := testApp().OrderService.TransitionOrder(ctx, orderID, models.OrderStatusCancelled); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("Expected abort error, got %v", err)
+		}
+
+		if status := getPaymentStatus(t, externalID); status != models.PaymentStatusPending {
+			t.Errorf("Expected payment to stay pending, got %s", status)
+		}
+		if status, _ := gateway.GetInvoiceStatus(context.Background(), externalID); status != models.PaymentStatusPending {
+			t.Errorf("Expected invoice to stay pending, got %s", status)
+		}
+	})
+
+	t.Run("Success - Expiry cancels pending payments and invoices", func(t *testing.T) {
+		orderID := createTestOrder(t, productID)
+		defer cleanupTestPayments(t, orderID)
+		externalID := createTestPayment(t, router, orderID)
+
+		expire(orderID)
+
+		if status := getPaymentStatus(t, externalID); status != models.PaymentStatusCancelled {
+			t.Errorf("Expected payment to be cancelled, got %s", status)
+		}
+		if status, _ := gateway.GetInvoiceStatus(context.Background(), externalID); status != models.PaymentStatusCancelled {
+			t.Errorf("Expected invoice to be cancelled, got %s", status)
+		}
+	})
+
+	t.Run("Success - Payment after expiry re-reserves stock", func(t *testing.T) {
+		orderID := createTestOrder(t, productID)
+		defer cleanupTestPayments(t, orderID)
+		externalID := createTestPayment(t, router, orderID)
+		expire(orderID)
+		stock := getProductStock(t, productID)
+
+		body, signature := gateway.BuildWebhook("evt-late-paid-"+externalID, externalID, "paid")
+		assertStatusCode(t, http.StatusOK, sendWebhook(t, router, "fake", body, signature).Code)
+
+		if status := getOrderStatus(t, router, orderID, token); status != models.OrderStatusPaid && status != models.OrderStatusFulfilled {
+			t.Errorf("Expected order to be paid again, got %s", status)
+		}
+		if status := getPaymentStatus(t, externalID); status != models.PaymentStatusPaid {
+			t.Errorf("Expected payment to be paid, got %s", status)
+		}
+		if got := getProductStock(t, productID); got != stock-1 {
+			t.Errorf("Expected stock %d after re-reservation, got %d", stock-1, got)
+		}
+	})
+
+	t.Run("Error - Payment after expiry without stock is flagged for refund", func(t *testing.T) {
+		orderID := createTestOrder(t, productID)
+		defer cleanupTestPayments(t, orderID)
+		externalID := createTestPayment(t, router, orderID)
+		expire(orderID)
+
+		if _, err := testApp().DB.Exec("UPDATE products SET stock = 0 WHERE product_id = ?", productID); err != nil {
+			t.Fatalf("Failed to empty stock: %v", err)
+		}
+
+		body, signature := gateway.BuildWebhook("evt-late-refund-"+externalID, externalID, "paid")
+		assertStatusCode(t, http.StatusOK, sendWebhook(t, router, "fake", body, signature).Code)
+
+		if status := getPaymentStatus(t, externalID); status != models.PaymentStatusRefundRequired {
+			t.Errorf("Expected payment to need a refund, got %s", status)
+		}
+		if status := getOrderStatus(t, router, orderID, token); status != models.OrderStatusExpired {
+			t.Errorf("Expected order to stay expired, got %s", status)
+		}
+	})
+}
+
+func TestOrderTimestampsAreUTC(t *testing.T) {
+	token := getValidToken(t, "testuser_order_utc")
+	defer cleanupTestUser(t, "testuser_order_utc")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 1)
+	defer cleanupTestProduct(t, productID)
+	defer cleanupTestOrdersOfProduct(t, productID)
+
+	t.Run("Success - MySQL sessions use UTC", func(t *testing.T) {
+		cfg := config.DatabaseConfig{Driver: config.DriverMySQL, User: "root", Host: "127.0.0.1", Port: 3306, Name: "proapps"}
+		if dsn := cfg.DSN(); !strings.Contains(dsn, "loc=UTC") || !strings.Contains(dsn, "time_zone=%27%2B00%3A00%27") {
+			t.Errorf("Expected the DSN to pin the session time zone to UTC, got %s", dsn)
+		}
+	})
+
+	t.Run("Success - Reservation clock matches the database clock", func(t *testing.T) {
+		orderID := createTestOrder(t, productID)
+
+		// The invoice duration is derived from created_at, so it must not drift by a zone offset
+		order, err := testApp().OrderService.GetOrderByID(context.Background(), orderID)
+		if err != nil {
+			t.Fatalf("Failed to get order: %v", err)
+		}
+		if drift := time.Since(order.CreatedAt); drift < -time.Minute || drift > time.Minute {
+			t.Errorf("Expected created_at close to now, got %s (drift %s)", order.CreatedAt, drift)
+		}
+	})
+}
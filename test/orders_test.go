@@ -0,0 +1,224 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// Helper to create a pending test order and return its ID
+func createTestOrder(t *testing.T, productID int) int {
+	t.Helper()
+
+	router := testRouter()
+	body := map[string]interface{}{
+		"product_id": productID,
+		"name":       "Test Buyer",
+		"email":      "buyer@example.com",
+		"phone":      "08123456789",
+		"method":     "qris",
+	}
+
+	rr := makeRequest(t, router, "POST", "/orders", body, "")
+	response := parseResponse(t, rr)
+
+	var data map[string]interface{}
+	json.Unmarshal(response.Data, &data)
+	return int(data["order_id"].(float64))
+}
+
+func TestCreateOrder(t *testing.T) {
+	router := testRouter()
+	token := getValidToken(t, "testuser_create_order")
+	defer cleanupTestUser(t, "testuser_create_order")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 5)
+	defer cleanupTestProduct(t, productID)
+
+	t.Run("Success - Create pending order", func(t *testing.T) {
+		body := map[string]interface{}{
+			"product_id": productID,
+			"name":       "Test Buyer",
+			"email":      "buyer@example.com",
+			"method":     "qris",
+			"status":     "paid",
+		}
+
+		rr := makeRequest(t, router, "POST", "/orders", body, "")
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusCreated, rr.Code)
+		assertResponseStatus(t, "success", response)
+
+		var data map[string]interface{}
+		json.Unmarshal(response.Data, &data)
+		if orderID, ok := data["order_id"].(float64); ok {
+			defer cleanupTestOrder(t, int(orderID))
+		}
+		if data["status"] != "pending" {
+			t.Errorf("Expected new order to be pending, got %v", data["status"])
+		}
+	})
+
+	t.Run("Error - Invalid email", func(t *testing.T) {
+		body := map[string]interface{}{
+			"product_id": productID,
+			"name":       "Test Buyer",
+			"email":      "not-an-email",
+			"method":     "qris",
+		}
+
+		rr := makeRequest(t, router, "POST", "/orders", body, "")
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Error - Product not found", func(t *testing.T) {
+		body := map[string]interface{}{
+			"product_id": 99999,
+			"name":       "Test Buyer",
+			"email":      "buyer@example.com",
+			"method":     "qris",
+		}
+
+		rr := makeRequest(t, router, "POST", "/orders", body, "")
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+}
+
+func TestGetOrders(t *testing.T) {
+	router := testRouter()
+	token := getValidToken(t, "testuser_get_orders")
+	defer cleanupTestUser(t, "testuser_get_orders")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 5)
+	defer cleanupTestProduct(t, productID)
+
+	orderID := createTestOrder(t, productID)
+	defer cleanupTestOrder(t, orderID)
+
+	t.Run("Success - Filter orders by status", func(t *testing.T) {
+		rr := makeRequest(t, router, "GET", "/orders?status=pending", nil, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+		assertResponseStatus(t, "success", response)
+
+		var data struct {
+			Orders []map[string]interface{} `json:"orders"`
+		}
+		json.Unmarshal(response.Data, &data)
+		for _, order := range data.Orders {
+			if order["status"] != "pending" {
+				t.Errorf("Expected only pending orders, got %v", order["status"])
+			}
+		}
+	})
+
+	t.Run("Success - Get order by ID", func(t *testing.T) {
+		rr := makeRequest(t, router, "GET", fmt.Sprintf("/orders/%d", orderID), nil, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+		assertResponseStatus(t, "success", response)
+	})
+
+	t.Run("Error - Unknown status filter", func(t *testing.T) {
+		rr := makeRequest(t, router, "GET", "/orders?status=shipped", nil, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Error - Order not found", func(t *testing.T) {
+		rr := makeRequest(t, router, "GET", "/orders/99999", nil, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusNotFound, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Error - Unauthorized without token", func(t *testing.T) {
+		rr := makeRequest(t, router, "GET", "/orders", nil, "")
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+}
+
+func TestUpdateOrderStatus(t *testing.T) {
+	router := testRouter()
+	token := getValidToken(t, "testuser_order_status")
+	defer cleanupTestUser(t, "testuser_order_status")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 5)
+	defer cleanupTestProduct(t, productID)
+
+	t.Run("Success - pending to paid to fulfilled", func(t *testing.T) {
+		orderID := createTestOrder(t, productID)
+		defer cleanupTestOrder(t, orderID)
+
+		for _, status := range []string{"paid", "fulfilled"} {
+			rr := makeRequest(t, router, "PUT", fmt.Sprintf("/orders/%d/status", orderID), map[string]interface{}{"status": status}, token)
+			response := parseResponse(t, rr)
+
+			assertStatusCode(t, http.StatusOK, rr.Code)
+			assertResponseStatus(t, "success", response)
+		}
+	})
+
+	t.Run("Error - Illegal transition pending to refunded", func(t *testing.T) {
+		orderID := createTestOrder(t, productID)
+		defer cleanupTestOrder(t, orderID)
+
+		rr := makeRequest(t, router, "PUT", fmt.Sprintf("/orders/%d/status", orderID), map[string]interface{}{"status": "refunded"}, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusConflict, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Error - Terminal status cannot change", func(t *testing.T) {
+		orderID := createTestOrder(t, productID)
+		defer cleanupTestOrder(t, orderID)
+
+		makeRequest(t, router, "PUT", fmt.Sprintf("/orders/%d/status", orderID), map[string]interface{}{"status": "cancelled"}, token)
+
+		rr := makeRequest(t, router, "PUT", fmt.Sprintf("/orders/%d/status", orderID), map[string]interface{}{"status": "paid"}, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusConflict, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Error - Unknown status", func(t *testing.T) {
+		orderID := createTestOrder(t, productID)
+		defer cleanupTestOrder(t, orderID)
+
+		rr := makeRequest(t, router, "PUT", fmt.Sprintf("/orders/%d/status", orderID), map[string]interface{}{"status": "shipped"}, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+}
@@ -0,0 +1,313 @@
+package test
+
+import (
+	"contact-management/src/app"
+	"contact-management/src/helpers"
+	"contact-management/src/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// issueTestPasswordReset asks the API for a reset token for username as the holder of token
+func issueTestPasswordReset(t *testing.T, router *httprouter.Router, username, token string) string {
+	t.Helper()
+
+	rr := makeRequest(t, router, "POST", "/users/"+username+"/password-reset", nil, token)
+	response := parseResponse(t, rr)
+	assertStatusCode(t, http.StatusCreated, rr.Code)
+
+	var reset models.PasswordResetResponse
+	json.Unmarshal(response.Data, &reset)
+	if reset.ResetToken == "" {
+		t.Fatal("Expected a reset token in the response")
+	}
+	return reset.ResetToken
+}
+
+func TestChangePassword(t *testing.T) {
+	router := testRouter()
+
+	makeRequest(t, router, "POST", "/register", map[string]interface{}{
+		"username": "testuser_password",
+		"password": "password123",
+	}, "")
+	defer cleanupTestUser(t, "testuser_password")
+
+	token := loginTestUser(t, router, "testuser_password", "password123")
+
+	t.Run("Error - Wrong current password", func(t *testing.T) {
+		rr := makeRequest(t, router, "PUT", "/me/password", map[string]interface{}{
+			"current_password": "wrongpassword1",
+			"new_password":     "newpassword456",
+		}, token)
+
+		assertStatusCode(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+
+	t.Run("Error - New password violates the policy", func(t *testing.T) {
+		for _, password := range []string{"short1", "onlyletters", "12345678901"} {
+			rr := makeRequest(t, router, "PUT", "/me/password", map[string]interface{}{
+				"current_password": "password123",
+				"new_password":     password,
+			}, token)
+
+			assertStatusCode(t, http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+
+	t.Run("Error - Unauthenticated", func(t *testing.T) {
+		rr := makeRequest(t, router, "PUT", "/me/password", map[string]interface{}{
+			"current_password": "password123",
+			"new_password":     "newpassword456",
+		}, "")
+
+		assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("Success - Change revokes every session", func(t *testing.T) {
+		otherToken := loginTestUser(t, router, "testuser_password", "password123")
+
+		rr := makeRequest(t, router, "PUT", "/me/password", map[string]interface{}{
+			"current_password": "password123",
+			"new_password":     "newpassword456",
+		}, token)
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		for _, old := range []string{token, otherToken} {
+			assertStatusCode(t, http.StatusUnauthorized, makeRequest(t, router, "GET", "/me", nil, old).Code)
+		}
+
+		rr = makeRequest(t, router, "POST", "/login", map[string]interface{}{
+			"username": "testuser_password",
+			"password": "password123",
+		}, "")
+		assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+		loginTestUser(t, router, "testuser_password", "newpassword456")
+	})
+}
+
+func TestPasswordResetToken(t *testing.T) {
+	router := testRouter()
+	adminToken := getValidToken(t, "testuser_reset_admin")
+	defer cleanupTestUser(t, "testuser_reset_admin")
+
+	makeRequest(t, router, "POST", "/register", map[string]interface{}{
+		"username": "testuser_reset_token",
+		"password": "password123",
+	}, "")
+	defer cleanupTestUser(t, "testuser_reset_token")
+
+	t.Run("Success - Token resets the password once", func(t *testing.T) {
+		userToken := loginTestUser(t, router, "testuser_reset_token", "password123")
+		resetToken := issueTestPasswordReset(t, router, "testuser_reset_token", adminToken)
+
+		rr := makeRequest(t, router, "POST", "/password-reset", map[string]interface{}{
+			"token":        resetToken,
+			"new_password": "resetpassword789",
+		}, "")
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		assertStatusCode(t, http.StatusUnauthorized, makeRequest(t, router, "GET", "/me", nil, userToken).Code)
+		loginTestUser(t, router, "testuser_reset_token", "resetpassword789")
+
+		rr = makeRequest(t, router, "POST", "/password-reset", map[string]interface{}{
+			"token":        resetToken,
+			"new_password": "anotherpassword1",
+		}, "")
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Success - Policy violation keeps the token usable", func(t *testing.T) {
+		resetToken := issueTestPasswordReset(t, router, "testuser_reset_token", adminToken)
+
+		rr := makeRequest(t, router, "POST", "/password-reset", map[string]interface{}{
+			"token":        resetToken,
+			"new_password": "short",
+		}, "")
+		assertStatusCode(t, http.StatusUnprocessableEntity, rr.Code)
+
+		rr = makeRequest(t, router, "POST", "/password-reset", map[string]interface{}{
+			"token":        resetToken,
+			"new_password": "validpassword2",
+		}, "")
+		assertStatusCode(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Error - Issuing a new token cancels the previous one", func(t *testing.T) {
+		first := issueTestPasswordReset(t, router, "testuser_reset_token", adminToken)
+		issueTestPasswordReset(t, router, "testuser_reset_token", adminToken)
+
+		rr := makeRequest(t, router, "POST", "/password-reset", map[string]interface{}{
+			"token":        first,
+			"new_password": "validpassword3",
+		}, "")
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Error - Unknown token", func(t *testing.T) {
+		rr := makeRequest(t, router, "POST", "/password-reset", map[string]interface{}{
+			"token":        "not-a-real-token",
+			"new_password": "validpassword4",
+		}, "")
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Error - Staff cannot reset an admin", func(t *testing.T) {
+		staffToken := getValidTokenWithRole(t, "testuser_reset_staff", models.RoleStaff)
+		defer cleanupTestUser(t, "testuser_reset_staff")
+		makeRequest(t, router, "POST", "/users", map[string]interface{}{
+			"username": "testuser_reset_victim",
+			"password": "password123",
+			"role":     models.RoleAdmin,
+		}, adminToken)
+		defer cleanupTestUser(t, "testuser_reset_victim")
+
+		rr := makeRequest(t, router, "POST", "/users/testuser_reset_victim/password-reset", nil, staffToken)
+
+		assertStatusCode(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("Error - Unknown user", func(t *testing.T) {
+		rr := makeRequest(t, router, "POST", "/users/testuser_reset_nobody/password-reset", nil, adminToken)
+
+		assertStatusCode(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestPasswordResetTokenExpiry(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	application, err := app.New(testConfig(),
+		app.WithDB(testApp().DB),
+		app.WithLogger(testLogger()),
+		app.WithTokenStore(testTokenStore()),
+		app.WithGateway(testGateway()),
+		app.WithClock(clock.Now),
+	)
+	if err != nil {
+		t.Fatalf("Failed to build application: %v", err)
+	}
+	defer application.Close()
+	router := application.Router
+	defer cleanupTestUser(t, "testuser_reset_expiry")
+	defer cleanupTestUser(t, "testuser_reset_expiry_admin")
+
+	makeRequest(t, router, "POST", "/register", map[string]interface{}{
+		"username": "testuser_reset_expiry",
+		"password": "password123",
+	}, "")
+
+	tokens, err := application.TokenService.GenerateTokenPair(context.Background(), "testuser_reset_expiry_admin", models.RoleAdmin, models.SessionMeta{})
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	resetToken := issueTestPasswordReset(t, router, "testuser_reset_expiry", tokens.Token)
+
+	clock.Advance(application.Config.Password.ResetTokenTTL + time.Second)
+
+	rr := makeRequest(t, router, "POST", "/password-reset", map[string]interface{}{
+		"token":        resetToken,
+		"new_password": "expiredpassword1",
+	}, "")
+	assertStatusCode(t, http.StatusBadRequest, rr.Code)
+	loginTestUser(t, router, "testuser_reset_expiry", "password123")
+}
+
+func TestUpdateUserKeepsUsername(t *testing.T) {
+	router := testRouter()
+	token := getValidToken(t, "testuser_same_admin")
+	defer cleanupTestUser(t, "testuser_same_admin")
+
+	makeRequest(t, router, "POST", "/users", map[string]interface{}{
+		"username": "testuser_same",
+		"password": "password123",
+	}, token)
+	defer cleanupTestUser(t, "testuser_same")
+
+	rr := makeRequest(t, router, "PUT", "/users/testuser_same", map[string]interface{}{
+		"username": "testuser_same",
+		"password": "newpassword456",
+	}, token)
+	assertStatusCode(t, http.StatusOK, rr.Code)
+
+	makeRequest(t, router, "POST", "/users", map[string]interface{}{
+		"username": "testuser_same_other",
+		"password": "password123",
+	}, token)
+	defer cleanupTestUser(t, "testuser_same_other")
+
+	rr = makeRequest(t, router, "PUT", "/users/testuser_same", map[string]interface{}{
+		"username": "testuser_same_other",
+		"password": "newpassword456",
+	}, token)
+	assertStatusCode(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestPasswordPolicyOnEveryPath(t *testing.T) {
+	router := testRouter()
+	token := getValidToken(t, "testuser_policy_admin")
+	defer cleanupTestUser(t, "testuser_policy_admin")
+
+	t.Run("Error - Register with a weak password", func(t *testing.T) {
+		defer cleanupTestUser(t, "testuser_policy_register")
+
+		rr := makeRequest(t, router, "POST", "/register", map[string]interface{}{
+			"username": "testuser_policy_register",
+			"password": "a",
+		}, "")
+
+		assertStatusCode(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+
+	t.Run("Error - Admin creates a user with a weak password", func(t *testing.T) {
+		defer cleanupTestUser(t, "testuser_policy_create")
+
+		rr := makeRequest(t, router, "POST", "/users", map[string]interface{}{
+			"username": "testuser_policy_create",
+			"password": "onlyletters",
+		}, token)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Error - CLI creates an admin with a weak password", func(t *testing.T) {
+		defer cleanupTestUser(t, "testuser_policy_cli")
+
+		user := &models.User{Username: "testuser_policy_cli", Password: "a", Role: models.RoleAdmin}
+		err := testApp().UserService.CreateUser(context.Background(), user, models.RoleAdmin)
+		if _, ok := err.(helpers.ValidationErrors); !ok {
+			t.Errorf("Expected a validation error, got %v", err)
+		}
+	})
+
+	makeRequest(t, router, "POST", "/users", map[string]interface{}{
+		"username": "testuser_policy_update",
+		"password": "password123",
+	}, token)
+	defer cleanupTestUser(t, "testuser_policy_update")
+	defer cleanupTestUser(t, "testuser_policy_renamed")
+
+	t.Run("Error - Update with a weak password", func(t *testing.T) {
+		rr := makeRequest(t, router, "PUT", "/users/testuser_policy_update", map[string]interface{}{
+			"username": "testuser_policy_update",
+			"password": "short1",
+		}, token)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+		loginTestUser(t, router, "testuser_policy_update", "password123")
+	})
+
+	t.Run("Success - Update without a password keeps it", func(t *testing.T) {
+		rr := makeRequest(t, router, "PUT", "/users/testuser_policy_update", map[string]interface{}{
+			"username": "testuser_policy_renamed",
+		}, token)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+		loginTestUser(t, router, "testuser_policy_renamed", "password123")
+	})
+}
@@ -0,0 +1,364 @@
+package test
+
+import (
+	"bytes"
+	"contact-management/src/app"
+	"contact-management/src/config"
+	"contact-management/src/gateways"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestCreatePayment(t *testing.T) {
+	router, gateway := testRouter(), testGateway()
+	token := getValidToken(t, "testuser_create_payment")
+	defer cleanupTestUser(t, "testuser_create_payment")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 5)
+	defer cleanupTestProduct(t, productID)
+
+	orderID := createTestOrder(t, productID)
+	defer cleanupTestOrder(t, orderID)
+	defer cleanupTestPayments(t, orderID)
+
+	var paymentID int
+
+	t.Run("Success - Create payment for pending order", func(t *testing.T) {
+		rr := makeRequest(t, router, "POST", fmt.Sprintf("/orders/%d/payments", orderID), nil, "")
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusCreated, rr.Code)
+		assertResponseStatus(t, "success", response)
+
+		var data map[string]interface{}
+		json.Unmarshal(response.Data, &data)
+		if data["payment_url"] == "" || data["payment_url"] == nil {
+			t.Error("Expected payment_url in response data")
+		}
+		if data["amount"] != float64(50000) {
+			t.Errorf("Expected amount to match product price, got %v", data["amount"])
+		}
+		paymentID = int(data["payment_id"].(float64))
+
+		status, err := gateway.GetInvoiceStatus(context.Background(), data["external_id"].(string))
+		if err != nil || status != "pending" {
+			t.Errorf("Expected pending invoice in gateway, got %q (%v)", status, err)
+		}
+	})
+
+	t.Run("Success - Repeated request reuses pending payment", func(t *testing.T) {
+		rr := makeRequest(t, router, "POST", fmt.Sprintf("/orders/%d/payments", orderID), nil, "")
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusCreated, rr.Code)
+
+		var data map[string]interface{}
+		json.Unmarshal(response.Data, &data)
+		if int(data["payment_id"].(float64)) != paymentID {
+			t.Errorf("Expected payment %d to be reused, got %v", paymentID, data["payment_id"])
+		}
+	})
+
+	t.Run("Success - Cancel pending payment", func(t *testing.T) {
+		rr := makeRequest(t, router, "POST", fmt.Sprintf("/payments/%d/cancel", paymentID), nil, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+		assertResponseStatus(t, "success", response)
+	})
+
+	t.Run("Error - Order not found", func(t *testing.T) {
+		rr := makeRequest(t, router, "POST", "/orders/99999/payments", nil, "")
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusNotFound, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Error - Payment not found", func(t *testing.T) {
+		rr := makeRequest(t, router, "GET", "/payments/99999", nil, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusNotFound, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+}
+
+// sendWebhook posts a raw provider callback with its signature header
+func sendWebhook(t *testing.T, router *httprouter.Router, provider string, body []byte, signature string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest("POST", "/webhooks/payments/"+provider, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature", signature)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	return rr
+}
+
+// createTestPayment creates a pending payment for an order and returns its external ID
+func createTestPayment(t *testing.T, router *httprouter.Router, orderID int) string {
+	t.Helper()
+
+	rr := makeRequest(t, router, "POST", fmt.Sprintf("/orders/%d/payments", orderID), nil, "")
+	response := parseResponse(t, rr)
+
+	var data map[string]interface{}
+	json.Unmarshal(response.Data, &data)
+	return data["external_id"].(string)
+}
+
+// getOrderStatus fetches the current status of an order
+func getOrderStatus(t *testing.T, router *httprouter.Router, orderID int, token string) string {
+	t.Helper()
+
+	rr := makeRequest(t, router, "GET", fmt.Sprintf("/orders/%d", orderID), nil, token)
+	response := parseResponse(t, rr)
+
+	var data map[string]interface{}
+	json.Unmarshal(response.Data, &data)
+	status, _ := data["status"].(string)
+	return status
+}
+
+func TestPaymentWebhook(t *testing.T) {
+	router, gateway := testRouter(), testGateway()
+	token := getValidToken(t, "testuser_payment_webhook")
+	defer cleanupTestUser(t, "testuser_payment_webhook")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 5)
+	defer cleanupTestProduct(t, productID)
+
+	orderID := createTestOrder(t, productID)
+	defer cleanupTestOrder(t, orderID)
+	defer cleanupTestPayments(t, orderID)
+
+	externalID := createTestPayment(t, router, orderID)
+
+	t.Run("Error - Invalid signature", func(t *testing.T) {
+		body, _ := gateway.BuildWebhook("evt-invalid", externalID, "paid")
+
+		rr := sendWebhook(t, router, "fake", body, "deadbeef")
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Error - Unknown provider", func(t *testing.T) {
+		body, signature := gateway.BuildWebhook("evt-provider", externalID, "paid")
+
+		rr := sendWebhook(t, router, "unknown", body, signature)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusNotFound, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Success - Paid callback marks payment and order paid", func(t *testing.T) {
+		body, signature := gateway.BuildWebhook("evt-paid", externalID, "paid")
+
+		rr := sendWebhook(t, router, "fake", body, signature)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+		assertResponseStatus(t, "success", response)
+
+		if status := getOrderStatus(t, router, orderID, token); status != "paid" {
+			t.Errorf("Expected order to be paid, got %s", status)
+		}
+	})
+
+	t.Run("Success - Replayed callback is not applied twice", func(t *testing.T) {
+		body, signature := gateway.BuildWebhook("evt-paid", externalID, "paid")
+
+		rr := sendWebhook(t, router, "fake", body, signature)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		var data map[string]bool
+		json.Unmarshal(response.Data, &data)
+		if data["applied"] {
+			t.Error("Expected replayed event to be ignored")
+		}
+	})
+
+	t.Run("Success - Out-of-order expiry is ignored", func(t *testing.T) {
+		body, signature := gateway.BuildWebhook("evt-expired", externalID, "expired")
+
+		rr := sendWebhook(t, router, "fake", body, signature)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		var data map[string]bool
+		json.Unmarshal(response.Data, &data)
+		if data["applied"] {
+			t.Error("Expected late expiry event to be ignored")
+		}
+		if status := getOrderStatus(t, router, orderID, token); status != "paid" {
+			t.Errorf("Expected order to stay paid, got %s", status)
+		}
+	})
+}
+
+func TestNewPaymentGateway(t *testing.T) {
+	cfg := config.PaymentConfig{Provider: "fake", CallbackToken: "test-callback-token"}
+
+	t.Run("Error - Missing callback token", func(t *testing.T) {
+		for _, provider := range []string{"fake", "xendit"} {
+			_, err := gateways.NewPaymentGateway(config.PaymentConfig{Provider: provider}, "development")
+			if !errors.Is(err, gateways.ErrMissingCallbackToken) {
+				t.Errorf("Expected ErrMissingCallbackToken for %s, got %v", provider, err)
+			}
+		}
+	})
+
+	t.Run("Error - Fake provider outside development and test", func(t *testing.T) {
+		_, err := gateways.NewPaymentGateway(cfg, "production")
+		if !errors.Is(err, gateways.ErrFakeProviderNotAllowed) {
+			t.Errorf("Expected ErrFakeProviderNotAllowed, got %v", err)
+		}
+	})
+
+	t.Run("Success - Fake provider in test", func(t *testing.T) {
+		if _, err := gateways.NewPaymentGateway(cfg, "test"); err != nil {
+			t.Errorf("Expected fake provider to be allowed, got %v", err)
+		}
+	})
+
+	t.Run("Error - Fake gateway without secret rejects every webhook", func(t *testing.T) {
+		gateway := gateways.NewFakeGateway(config.PaymentConfig{})
+		body, signature := gateway.BuildWebhook("evt-unsigned", "order-1", "paid")
+
+		header := http.Header{}
+		header.Set("X-Signature", signature)
+		if _, err := gateway.ParseWebhook(header, body); !errors.Is(err, gateways.ErrInvalidSignature) {
+			t.Errorf("Expected ErrInvalidSignature, got %v", err)
+		}
+	})
+}
+
+// failingGateway is a fake provider whose invoice calls always fail
+type failingGateway struct {
+	*gateways.FakeGateway
+}
+
+func (f failingGateway) CreateInvoice(ctx context.Context, req gateways.CreateInvoiceRequest) (*gateways.Invoice, error) {
+	return nil, errors.New("provider unavailable")
+}
+
+func TestCreatePaymentErrors(t *testing.T) {
+	router := testRouter()
+	token := getValidToken(t, "testuser_payment_errors")
+	defer cleanupTestUser(t, "testuser_payment_errors")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 5)
+	defer cleanupTestProduct(t, productID)
+
+	orderID := createTestOrder(t, productID)
+	defer cleanupTestOrder(t, orderID)
+	defer cleanupTestPayments(t, orderID)
+
+	t.Run("Error - Gateway failure is a bad gateway", func(t *testing.T) {
+		application, err := app.New(testConfig(),
+			app.WithDB(testApp().DB),
+			app.WithLogger(testLogger()),
+			app.WithTokenStore(testTokenStore()),
+			app.WithGateway(failingGateway{testGateway()}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to build application: %v", err)
+		}
+		defer application.Close()
+
+		rr := makeRequest(t, application.Router, "POST", fmt.Sprintf("/orders/%d/payments", orderID), nil, "")
+
+		assertStatusCode(t, http.StatusBadGateway, rr.Code)
+	})
+
+	t.Run("Error - Cancelled request is not a bad gateway", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		req := httptest.NewRequest("POST", fmt.Sprintf("/orders/%d/payments", orderID), nil).WithContext(ctx)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assertStatusCode(t, http.StatusServiceUnavailable, rr.Code)
+	})
+}
+
+func TestCreatePaymentConcurrently(t *testing.T) {
+	token := getValidToken(t, "testuser_payment_concurrent")
+	defer cleanupTestUser(t, "testuser_payment_concurrent")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 5)
+	defer cleanupTestProduct(t, productID)
+	defer cleanupTestOrdersOfProduct(t, productID)
+
+	orderID := createTestOrder(t, productID)
+	defer cleanupTestPayments(t, orderID)
+
+	const requests = 8
+	var wg sync.WaitGroup
+	externalIDs := make([]string, requests)
+	errs := make([]error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			payment, err := testApp().PaymentService.CreatePayment(context.Background(), orderID)
+			errs[i] = err
+			if err == nil {
+				externalIDs[i] = payment.ExternalID
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Every request must get the one payment instead of opening its own invoice
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Expected request %d to succeed, got %v", i, err)
+		}
+		if externalIDs[i] != externalIDs[0] {
+			t.Errorf("Expected every request to get invoice %s, got %s", externalIDs[0], externalIDs[i])
+		}
+	}
+
+	var count int
+	if err := testApp().DB.QueryRow("SELECT COUNT(*) FROM payments WHERE order_id = ?", orderID).Scan(&count); err != nil {
+		t.Fatalf("Failed to count payments: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected a single payment, got %d", count)
+	}
+}
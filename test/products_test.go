@@ -0,0 +1,274 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// Helper to create a test category and brand product for products
+func createTestBrandProductForProduct(t *testing.T, token string) (int, int) {
+	t.Helper()
+
+	categoryID := createTestCategoryForBrand(t, token)
+
+	router := testRouter()
+	body := map[string]interface{}{
+		"name":        "Test Brand For Products",
+		"category_id": categoryID,
+	}
+
+	rr := makeRequest(t, router, "POST", "/brand-products", body, token)
+	response := parseResponse(t, rr)
+
+	var data map[string]interface{}
+	json.Unmarshal(response.Data, &data)
+	return categoryID, int(data["brand_product_id"].(float64))
+}
+
+// Helper to create a test product and return its ID
+func createTestProduct(t *testing.T, token string, brandProductID, stock int) int {
+	t.Helper()
+
+	router := testRouter()
+	body := map[string]interface{}{
+		"name":             "Test Product",
+		"brand_product_id": brandProductID,
+		"price":            50000,
+		"description":      "Test product description",
+		"duration":         "1 bulan",
+		"stock":            stock,
+	}
+
+	rr := makeRequest(t, router, "POST", "/products", body, token)
+	response := parseResponse(t, rr)
+
+	var data map[string]interface{}
+	json.Unmarshal(response.Data, &data)
+	return int(data["product_id"].(float64))
+}
+
+func TestGetAllProducts(t *testing.T) {
+	router := testRouter()
+	token := getValidToken(t, "testuser_product")
+	defer cleanupTestUser(t, "testuser_product")
+
+	t.Run("Success - Get all products", func(t *testing.T) {
+		rr := makeRequest(t, router, "GET", "/products", nil, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+		assertResponseStatus(t, "success", response)
+	})
+
+	t.Run("Error - Unauthorized without token", func(t *testing.T) {
+		rr := makeRequest(t, router, "GET", "/products", nil, "")
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+}
+
+func TestCreateProduct(t *testing.T) {
+	router := testRouter()
+	token := getValidToken(t, "testuser_create_product")
+	defer cleanupTestUser(t, "testuser_create_product")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	t.Run("Success - Create new product", func(t *testing.T) {
+		body := map[string]interface{}{
+			"name":             "Test Product Create",
+			"brand_product_id": brandProductID,
+			"price":            75000,
+			"description":      "Langganan premium",
+			"duration":         "1 bulan",
+			"stock":            10,
+		}
+
+		rr := makeRequest(t, router, "POST", "/products", body, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusCreated, rr.Code)
+		assertResponseStatus(t, "success", response)
+
+		var data map[string]interface{}
+		json.Unmarshal(response.Data, &data)
+		if productID, ok := data["product_id"].(float64); ok {
+			defer cleanupTestProduct(t, int(productID))
+		}
+	})
+
+	t.Run("Error - Invalid brand_product_id", func(t *testing.T) {
+		body := map[string]interface{}{
+			"name":             "Test Product Invalid Brand",
+			"brand_product_id": 99999,
+			"price":            75000,
+		}
+
+		rr := makeRequest(t, router, "POST", "/products", body, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Error - Missing required fields", func(t *testing.T) {
+		body := map[string]interface{}{
+			"price": 75000,
+		}
+
+		rr := makeRequest(t, router, "POST", "/products", body, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Error - Negative stock", func(t *testing.T) {
+		body := map[string]interface{}{
+			"name":             "Test Product Negative Stock",
+			"brand_product_id": brandProductID,
+			"stock":            -1,
+		}
+
+		rr := makeRequest(t, router, "POST", "/products", body, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+}
+
+func TestGetProductByID(t *testing.T) {
+	router := testRouter()
+	token := getValidToken(t, "testuser_get_product")
+	defer cleanupTestUser(t, "testuser_get_product")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 5)
+	defer cleanupTestProduct(t, productID)
+
+	t.Run("Success - Get product by ID", func(t *testing.T) {
+		rr := makeRequest(t, router, "GET", fmt.Sprintf("/products/%d", productID), nil, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+		assertResponseStatus(t, "success", response)
+	})
+
+	t.Run("Error - Product not found", func(t *testing.T) {
+		rr := makeRequest(t, router, "GET", "/products/99999", nil, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusNotFound, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Error - Invalid ID format", func(t *testing.T) {
+		rr := makeRequest(t, router, "GET", "/products/invalid", nil, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+}
+
+func TestUpdateProduct(t *testing.T) {
+	router := testRouter()
+	token := getValidToken(t, "testuser_update_product")
+	defer cleanupTestUser(t, "testuser_update_product")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 5)
+	defer cleanupTestProduct(t, productID)
+
+	t.Run("Success - Update product", func(t *testing.T) {
+		body := map[string]interface{}{
+			"name":             "Test Product Updated",
+			"brand_product_id": brandProductID,
+			"price":            60000,
+			"stock":            3,
+		}
+
+		rr := makeRequest(t, router, "PUT", fmt.Sprintf("/products/%d", productID), body, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+		assertResponseStatus(t, "success", response)
+
+		// Stock only changes through reservations and credential uploads
+		if stock := getProductStock(t, productID); stock != 5 {
+			t.Errorf("Expected update to leave stock at 5, got %d", stock)
+		}
+	})
+
+	t.Run("Error - Update non-existent product", func(t *testing.T) {
+		body := map[string]interface{}{
+			"name":             "Updated Name",
+			"brand_product_id": brandProductID,
+		}
+
+		rr := makeRequest(t, router, "PUT", "/products/99999", body, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusNotFound, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Error - Invalid brand_product_id", func(t *testing.T) {
+		body := map[string]interface{}{
+			"name":             "Updated Name",
+			"brand_product_id": 99999,
+		}
+
+		rr := makeRequest(t, router, "PUT", fmt.Sprintf("/products/%d", productID), body, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+}
+
+func TestDeleteProduct(t *testing.T) {
+	router := testRouter()
+	token := getValidToken(t, "testuser_delete_product")
+	defer cleanupTestUser(t, "testuser_delete_product")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	t.Run("Success - Soft delete product", func(t *testing.T) {
+		productID := createTestProduct(t, token, brandProductID, 1)
+		defer cleanupTestProduct(t, productID)
+
+		rr := makeRequest(t, router, "DELETE", fmt.Sprintf("/products/%d", productID), nil, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+		assertResponseStatus(t, "success", response)
+
+		// Deleted product is no longer visible
+		rr = makeRequest(t, router, "GET", fmt.Sprintf("/products/%d", productID), nil, token)
+		assertStatusCode(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("Error - Delete non-existent product", func(t *testing.T) {
+		rr := makeRequest(t, router, "DELETE", "/products/99999", nil, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusNotFound, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+}
@@ -0,0 +1,224 @@
+package test
+
+import (
+	"bytes"
+	"contact-management/src/app"
+	"contact-management/src/apps"
+	"contact-management/src/config"
+	"contact-management/src/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	cfg := testConfig()
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.Auth = config.RateLimitGroup{
+		IP:       config.RateLimitRule{Limit: 4, Window: time.Minute},
+		Username: config.RateLimitRule{Limit: 2, Window: time.Minute},
+	}
+	cfg.RateLimit.API = config.RateLimitGroup{
+		Username: config.RateLimitRule{Limit: 2, Window: time.Minute},
+	}
+
+	clock := &fakeClock{now: time.Now()}
+	application, err := app.New(cfg,
+		app.WithDB(testApp().DB),
+		app.WithLogger(testLogger()),
+		app.WithTokenStore(testTokenStore()),
+		app.WithGateway(testGateway()),
+		app.WithRateLimiter(utils.NewMemoryRateLimiter().WithClock(clock.Now)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to build application: %v", err)
+	}
+	defer application.Close()
+	router := application.Router
+	defer cleanupTestUser(t, "testuser_ratelimit")
+
+	login := func(username, password string) int {
+		rr := makeRequest(t, router, "POST", "/login", map[string]interface{}{
+			"username": username,
+			"password": password,
+		}, "")
+		return rr.Code
+	}
+
+	t.Run("Success - Limited request still reaches the handler with headers", func(t *testing.T) {
+		rr := makeRequest(t, router, "POST", "/register", map[string]interface{}{
+			"username": "testuser_ratelimit",
+			"password": "password123",
+		}, "")
+
+		assertStatusCode(t, http.StatusCreated, rr.Code)
+		if got := rr.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("Expected RateLimit-Limit of the username rule 2, got %q", got)
+		}
+		if got := rr.Header().Get("RateLimit-Remaining"); got != "1" {
+			t.Errorf("Expected RateLimit-Remaining 1, got %q", got)
+		}
+		if got := rr.Header().Get("RateLimit-Reset"); got != "60" {
+			t.Errorf("Expected RateLimit-Reset 60, got %q", got)
+		}
+	})
+
+	t.Run("Error - Username limit exceeded", func(t *testing.T) {
+		assertStatusCode(t, http.StatusUnauthorized, login("testuser_ratelimit", "wrongpassword"))
+
+		rr := makeRequest(t, router, "POST", "/login", map[string]interface{}{
+			"username": "testuser_ratelimit",
+			"password": "password123",
+		}, "")
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusTooManyRequests, rr.Code)
+		assertResponseStatus(t, "error", response)
+		if got := rr.Header().Get("Retry-After"); got != "60" {
+			t.Errorf("Expected Retry-After 60, got %q", got)
+		}
+		if got := rr.Header().Get("RateLimit-Remaining"); got != "0" {
+			t.Errorf("Expected RateLimit-Remaining 0, got %q", got)
+		}
+	})
+
+	t.Run("Error - IP limit applies across usernames", func(t *testing.T) {
+		if code := login("testuser_ratelimit_other", "password123"); code == http.StatusTooManyRequests {
+			t.Fatalf("Expected the fourth request from this IP to be allowed")
+		}
+		assertStatusCode(t, http.StatusTooManyRequests, login("testuser_ratelimit_other", "password123"))
+	})
+
+	t.Run("Success - Requests allowed again once the window slides", func(t *testing.T) {
+		clock.Advance(time.Minute + time.Second)
+
+		assertStatusCode(t, http.StatusOK, login("testuser_ratelimit", "password123"))
+	})
+
+	t.Run("Error - API limit per authenticated user", func(t *testing.T) {
+		token := getValidToken(t, "testuser_ratelimit_api")
+		defer testTokenService().RevokeAllSessions(context.Background(), "testuser_ratelimit_api")
+
+		for i := 0; i < 2; i++ {
+			rr := makeRequest(t, router, "GET", "/me/sessions", nil, token)
+			assertStatusCode(t, http.StatusOK, rr.Code)
+		}
+		rr := makeRequest(t, router, "GET", "/me/sessions", nil, token)
+		assertStatusCode(t, http.StatusTooManyRequests, rr.Code)
+	})
+
+	t.Run("Error - Spoofed X-Forwarded-For does not reset the IP limit", func(t *testing.T) {
+		// loginFrom sends through the full handler from an address that is not a trusted proxy
+		loginFrom := func(i int) int {
+			body, _ := json.Marshal(map[string]interface{}{
+				"username": fmt.Sprintf("testuser_ratelimit_spoof_%d", i),
+				"password": "password123",
+			})
+			req := httptest.NewRequest("POST", "/login", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+			req.RemoteAddr = "203.0.113.50:1234"
+
+			rr := httptest.NewRecorder()
+			application.Handler.ServeHTTP(rr, req)
+			return rr.Code
+		}
+
+		for i := 0; i < 4; i++ {
+			if code := loginFrom(i); code == http.StatusTooManyRequests {
+				t.Fatalf("Expected request %d to be allowed", i+1)
+			}
+		}
+		assertStatusCode(t, http.StatusTooManyRequests, loginFrom(4))
+	})
+
+	t.Run("Success - Disabled rate limit sends no headers", func(t *testing.T) {
+		rr := makeRequest(t, testRouter(), "POST", "/login", map[string]interface{}{
+			"username": "testuser_ratelimit",
+			"password": "wrongpassword",
+		}, "")
+
+		if got := rr.Header().Get("RateLimit-Limit"); got != "" {
+			t.Errorf("Expected no RateLimit-Limit header, got %q", got)
+		}
+	})
+}
+
+func TestRateLimiterFallback(t *testing.T) {
+	client := apps.NewRedisClient(config.RedisConfig{
+		Host:        "127.0.0.1",
+		Port:        1,
+		DialTimeout: 200 * time.Millisecond,
+	})
+	defer client.Close()
+
+	limiter := utils.NewFallbackRateLimiter(utils.NewRedisRateLimiter(client), utils.NewMemoryRateLimiter(), testLogger())
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		result, err := limiter.Allow(ctx, "test:fallback", 2, time.Minute)
+		if err != nil {
+			t.Fatalf("Expected in-memory fallback while Redis is unreachable, got %v", err)
+		}
+		if !result.Allowed {
+			t.Fatalf("Expected request %d to be allowed", i+1)
+		}
+	}
+
+	result, err := limiter.Allow(ctx, "test:fallback", 2, time.Minute)
+	if err != nil {
+		t.Fatalf("Expected in-memory fallback while Redis is unreachable, got %v", err)
+	}
+	if result.Allowed {
+		t.Error("Expected the fallback limiter to enforce the limit")
+	}
+}
+
+func TestRedisRateLimiter(t *testing.T) {
+	client := apps.NewRedisClient(testConfig().Redis)
+	defer client.Close()
+	if err := apps.PingRedis(client, time.Second); err != nil {
+		t.Skip("Redis is not reachable: ", err)
+	}
+
+	key := "test:redis:" + time.Now().Format(time.RFC3339Nano)
+	defer client.Del(context.Background(), "rate_limit:"+key)
+
+	limiter := utils.NewRedisRateLimiter(client)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		result, err := limiter.Allow(ctx, key, 2, time.Minute)
+		if err != nil {
+			t.Fatalf("Failed to check rate limit: %v", err)
+		}
+		if !result.Allowed || result.Remaining != 1-i {
+			t.Fatalf("Expected request %d to be allowed with %d remaining, got %+v", i+1, 1-i, result)
+		}
+	}
+
+	result, err := limiter.Allow(ctx, key, 2, time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to check rate limit: %v", err)
+	}
+	if result.Allowed || result.Reset <= 0 || result.Reset > time.Minute {
+		t.Errorf("Expected request to be denied with a reset within the window, got %+v", result)
+	}
+}
+
+func TestParseRateLimitRule(t *testing.T) {
+	rule, err := config.ParseRateLimitRule("20/1m")
+	if err != nil || rule.Limit != 20 || rule.Window != time.Minute {
+		t.Errorf("Expected 20 per minute, got %+v (%v)", rule, err)
+	}
+
+	for _, value := range []string{"20", "x/1m", "-1/1m", "20/0s", "20/soon"} {
+		if _, err := config.ParseRateLimitRule(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package test
+
+import (
+	"contact-management/src/apps"
+	"contact-management/src/config"
+	"testing"
+	"time"
+)
+
+func TestRedisConfig(t *testing.T) {
+	t.Setenv("REDIS_HOST", "redis.internal")
+	t.Setenv("REDIS_PORT", "6380")
+	t.Setenv("REDIS_POOL_SIZE", "42")
+	t.Setenv("REDIS_READ_TIMEOUT", "750ms")
+	t.Setenv("REDIS_TLS", "true")
+	t.Setenv("REDIS_SENTINEL_MASTER", "mymaster")
+	t.Setenv("REDIS_SENTINEL_ADDRS", "10.0.0.1:26379, 10.0.0.2:26379,")
+
+	cfg := config.LoadConfig().Redis
+
+	if cfg.Addr() != "redis.internal:6380" {
+		t.Errorf("Expected addr redis.internal:6380, got %s", cfg.Addr())
+	}
+	if cfg.PoolSize != 42 || cfg.ReadTimeout != 750*time.Millisecond || !cfg.TLS {
+		t.Errorf("Unexpected pool/timeout/TLS settings: %+v", cfg)
+	}
+	if len(cfg.SentinelAddrs) != 2 || cfg.SentinelAddrs[1] != "10.0.0.2:26379" {
+		t.Errorf("Expected 2 sentinel addrs, got %v", cfg.SentinelAddrs)
+	}
+}
+
+func TestPingRedisUnreachable(t *testing.T) {
+	client := apps.NewRedisClient(config.RedisConfig{
+		Host:        "127.0.0.1",
+		Port:        1,
+		DialTimeout: 200 * time.Millisecond,
+	})
+	defer client.Close()
+
+	if err := apps.PingRedis(client, time.Second); err == nil {
+		t.Error("Expected ping to unreachable Redis to fail")
+	}
+}
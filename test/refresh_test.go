@@ -0,0 +1,101 @@
+package test
+
+import (
+	"contact-management/src/models"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// refreshTestTokens calls the refresh endpoint and returns the response recorder and new token pair
+func refreshTestTokens(t *testing.T, router *httprouter.Router, refreshToken string) (int, models.TokenPair) {
+	t.Helper()
+
+	body := map[string]interface{}{
+		"refresh_token": refreshToken,
+	}
+	rr := makeRequest(t, router, "POST", "/auth/refresh", body, "")
+	response := parseResponse(t, rr)
+
+	var tokens models.TokenPair
+	json.Unmarshal(response.Data, &tokens)
+	return rr.Code, tokens
+}
+
+func TestRefreshToken(t *testing.T) {
+	router := testRouter()
+
+	registerBody := map[string]interface{}{
+		"username": "testuser_refresh",
+		"password": "password123",
+		"name":     "Test User Refresh",
+	}
+	makeRequest(t, router, "POST", "/register", registerBody, "")
+	defer cleanupTestUser(t, "testuser_refresh")
+
+	t.Run("Success - Login returns access and refresh token", func(t *testing.T) {
+		tokens := loginTestUserTokens(t, router, "testuser_refresh", "password123")
+
+		if tokens.Token == "" || tokens.RefreshToken == "" {
+			t.Fatal("Expected both access and refresh token in login response")
+		}
+		if tokens.ExpiresIn <= 0 {
+			t.Errorf("Expected positive expires_in, got %d", tokens.ExpiresIn)
+		}
+	})
+
+	t.Run("Success - Refresh rotates refresh token", func(t *testing.T) {
+		tokens := loginTestUserTokens(t, router, "testuser_refresh", "password123")
+
+		code, refreshed := refreshTestTokens(t, router, tokens.RefreshToken)
+		assertStatusCode(t, http.StatusOK, code)
+
+		if refreshed.RefreshToken == "" || refreshed.RefreshToken == tokens.RefreshToken {
+			t.Error("Expected a new refresh token after refresh")
+		}
+
+		rr := makeRequest(t, router, "GET", "/me", nil, refreshed.Token)
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		code, _ = refreshTestTokens(t, router, refreshed.RefreshToken)
+		assertStatusCode(t, http.StatusOK, code)
+	})
+
+	t.Run("Error - Reused refresh token revokes the family", func(t *testing.T) {
+		tokens := loginTestUserTokens(t, router, "testuser_refresh", "password123")
+
+		code, refreshed := refreshTestTokens(t, router, tokens.RefreshToken)
+		assertStatusCode(t, http.StatusOK, code)
+
+		code, _ = refreshTestTokens(t, router, tokens.RefreshToken)
+		assertStatusCode(t, http.StatusUnauthorized, code)
+
+		code, _ = refreshTestTokens(t, router, refreshed.RefreshToken)
+		assertStatusCode(t, http.StatusUnauthorized, code)
+
+		rr := makeRequest(t, router, "GET", "/me", nil, refreshed.Token)
+		assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("Error - Refresh after logout", func(t *testing.T) {
+		tokens := loginTestUserTokens(t, router, "testuser_refresh", "password123")
+
+		rr := makeRequest(t, router, "POST", "/logout", nil, tokens.Token)
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		code, _ := refreshTestTokens(t, router, tokens.RefreshToken)
+		assertStatusCode(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("Error - Unknown refresh token", func(t *testing.T) {
+		code, _ := refreshTestTokens(t, router, "not-a-refresh-token")
+		assertStatusCode(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("Error - Missing refresh token", func(t *testing.T) {
+		rr := makeRequest(t, router, "POST", "/auth/refresh", map[string]interface{}{}, "")
+		assertStatusCode(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+}
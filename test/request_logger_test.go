@@ -0,0 +1,157 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"contact-management/src/helpers"
+	"contact-management/src/middlewares"
+	"contact-management/src/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/sirupsen/logrus"
+)
+
+// setupRequestLoggerHandler wraps a small router in RequestLogger and captures
+// every log line as JSON, including the ones written by helpers.ErrorResponse
+func setupRequestLoggerHandler(t *testing.T) (http.Handler, *bytes.Buffer) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.AddHook(helpers.RequestIDHook{})
+
+	helpers.SetLogger(logger)
+	t.Cleanup(func() { helpers.SetLogger(testLogger()) })
+
+	authMiddleware := middlewares.AuthMiddleware(testTokenService())
+
+	router := httprouter.New()
+	router.GET("/items/:id/status", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		logger.WithContext(r.Context()).Info("service log")
+		helpers.SuccessResponse(w, http.StatusOK, "ok", ps.ByName("id"))
+	})
+	router.GET("/private", authMiddleware(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		helpers.ErrorResponse(w, http.StatusBadRequest, "gagal", "boom")
+	}))
+
+	resolver, err := helpers.NewClientIPResolver(testConfig().App.TrustedProxies)
+	if err != nil {
+		t.Fatalf("Failed to create client IP resolver: %v", err)
+	}
+
+	return middlewares.RequestLogger(logger, resolver, router), &buf
+}
+
+// readLogLines parses the captured JSON log lines
+func readLogLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("Failed to parse log line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestRequestLogger(t *testing.T) {
+	t.Run("Success - Incoming request id is echoed and logged", func(t *testing.T) {
+		handler, buf := setupRequestLoggerHandler(t)
+
+		req := httptest.NewRequest("GET", "/items/42/status", nil)
+		req.Header.Set(helpers.RequestIDHeader, "req-abc-123")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assertStatusCode(t, http.StatusOK, rr.Code)
+		if got := rr.Header().Get(helpers.RequestIDHeader); got != "req-abc-123" {
+			t.Errorf("Expected echoed request id, got %q", got)
+		}
+
+		lines := readLogLines(t, buf)
+		if len(lines) != 2 {
+			t.Fatalf("Expected service log and access log, got %d lines", len(lines))
+		}
+		for _, line := range lines {
+			if line["request_id"] != "req-abc-123" {
+				t.Errorf("Expected request_id on every line, got %v", line)
+			}
+		}
+
+		access := lines[1]
+		if access["route"] != "/items/:id/status" || access["method"] != "GET" {
+			t.Errorf("Unexpected route or method: %v", access)
+		}
+		if access["status"] != float64(http.StatusOK) || access["bytes"] != float64(rr.Body.Len()) {
+			t.Errorf("Unexpected status or bytes: %v", access)
+		}
+		if _, ok := access["latency_ms"]; !ok {
+			t.Error("Expected latency_ms in access log")
+		}
+	})
+
+	t.Run("Success - Missing or invalid request id is generated", func(t *testing.T) {
+		handler, _ := setupRequestLoggerHandler(t)
+
+		for _, incoming := range []string{"", "bad id\nwith newline"} {
+			req := httptest.NewRequest("GET", "/items/1/status", nil)
+			req.Header.Set(helpers.RequestIDHeader, incoming)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			got := rr.Header().Get(helpers.RequestIDHeader)
+			if got == "" || got == incoming {
+				t.Errorf("Expected generated request id for %q, got %q", incoming, got)
+			}
+		}
+	})
+
+	t.Run("Success - Error response and username share the request id", func(t *testing.T) {
+		handler, buf := setupRequestLoggerHandler(t)
+		token := getValidTokenWithRole(t, "testuser_reqlog", models.RoleCustomer)
+		defer testTokenService().RevokeAllSessions(context.Background(), "testuser_reqlog")
+
+		req := httptest.NewRequest("GET", "/private", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+		requestID := rr.Header().Get(helpers.RequestIDHeader)
+
+		lines := readLogLines(t, buf)
+		if len(lines) != 2 {
+			t.Fatalf("Expected error log and access log, got %d lines", len(lines))
+		}
+		if lines[0]["msg"] != "Error response" || lines[0]["request_id"] != requestID {
+			t.Errorf("Expected error response log with request id, got %v", lines[0])
+		}
+		if lines[1]["username"] != "testuser_reqlog" || lines[1]["status"] != float64(http.StatusBadRequest) {
+			t.Errorf("Expected username and status in access log, got %v", lines[1])
+		}
+	})
+
+	t.Run("Success - Unknown route is still logged", func(t *testing.T) {
+		handler, buf := setupRequestLoggerHandler(t)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", "/nope", nil))
+
+		assertStatusCode(t, http.StatusNotFound, rr.Code)
+		lines := readLogLines(t, buf)
+		if len(lines) != 1 || lines[0]["route"] != "-" {
+			t.Errorf("Expected one access log for unknown route, got %v", lines)
+		}
+	})
+}
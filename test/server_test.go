@@ -0,0 +1,133 @@
+package test
+
+import (
+	"contact-management/src/apps"
+	"contact-management/src/config"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// freePort asks the OS for a port that is free right now
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to find free port: %v", err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// waitForServer polls until the server accepts connections
+func waitForServer(t *testing.T, addr string) {
+	t.Helper()
+
+	for i := 0; i < 50; i++ {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Server on %s did not start", addr)
+}
+
+func TestNewServer(t *testing.T) {
+	cfg := config.AppConfig{
+		Port:              9090,
+		ReadTimeout:       time.Second,
+		ReadHeaderTimeout: 2 * time.Second,
+		WriteTimeout:      3 * time.Second,
+		IdleTimeout:       4 * time.Second,
+		MaxHeaderBytes:    4096,
+	}
+
+	server := apps.NewServer(cfg, http.NotFoundHandler())
+
+	if server.Addr != ":9090" {
+		t.Errorf("Expected addr :9090, got %s", server.Addr)
+	}
+	if server.ReadTimeout != time.Second || server.ReadHeaderTimeout != 2*time.Second ||
+		server.WriteTimeout != 3*time.Second || server.IdleTimeout != 4*time.Second {
+		t.Errorf("Timeouts not taken from config: %+v", server)
+	}
+	if server.MaxHeaderBytes != 4096 {
+		t.Errorf("Expected max header bytes 4096, got %d", server.MaxHeaderBytes)
+	}
+}
+
+// slowHandler responds after a delay and signals once the request is in flight
+func slowHandler() (http.Handler, chan struct{}) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(300 * time.Millisecond)
+		w.Write([]byte("done"))
+	})
+	return handler, started
+}
+
+func TestRunServerGracefulShutdown(t *testing.T) {
+	port := freePort(t)
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+
+	t.Run("Success - In-flight request drains before shutdown", func(t *testing.T) {
+		handler, started := slowHandler()
+		server := apps.NewServer(config.AppConfig{Port: port}, handler)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		result := make(chan error, 1)
+		go func() { result <- apps.RunServer(ctx, server, 2*time.Second, testLogger()) }()
+		waitForServer(t, addr)
+
+		body := make(chan string, 1)
+		go func() {
+			resp, err := http.Get("http://" + addr + "/slow")
+			if err != nil {
+				body <- "error: " + err.Error()
+				return
+			}
+			defer resp.Body.Close()
+			b, _ := io.ReadAll(resp.Body)
+			body <- string(b)
+		}()
+
+		<-started
+		cancel()
+
+		if got := <-body; got != "done" {
+			t.Errorf("Expected in-flight request to complete, got %q", got)
+		}
+		if err := <-result; err != nil {
+			t.Errorf("Expected clean shutdown, got %v", err)
+		}
+		if _, err := net.Dial("tcp", addr); err == nil {
+			t.Error("Expected server to stop accepting connections")
+		}
+	})
+
+	t.Run("Error - Shutdown deadline exceeded", func(t *testing.T) {
+		handler, started := slowHandler()
+		server := apps.NewServer(config.AppConfig{Port: port}, handler)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		result := make(chan error, 1)
+		go func() { result <- apps.RunServer(ctx, server, 10*time.Millisecond, testLogger()) }()
+		waitForServer(t, addr)
+
+		go http.Get("http://" + addr + "/slow")
+		<-started
+		cancel()
+
+		if err := <-result; err == nil {
+			t.Error("Expected deadline error when requests outlive the shutdown timeout")
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package test
+
+import (
+	"contact-management/src/config"
+	"contact-management/src/models"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// loginTestUser logs in through the API and returns the issued access token
+func loginTestUser(t *testing.T, router *httprouter.Router, username, password string) string {
+	t.Helper()
+
+	return loginTestUserTokens(t, router, username, password).Token
+}
+
+// loginTestUserTokens logs in through the API and returns the issued token pair
+func loginTestUserTokens(t *testing.T, router *httprouter.Router, username, password string) models.TokenPair {
+	t.Helper()
+
+	body := map[string]interface{}{
+		"username": username,
+		"password": password,
+	}
+	rr := makeRequest(t, router, "POST", "/login", body, "")
+	response := parseResponse(t, rr)
+	assertStatusCode(t, http.StatusOK, rr.Code)
+
+	var tokens models.TokenPair
+	json.Unmarshal(response.Data, &tokens)
+	return tokens
+}
+
+func getTestSessions(t *testing.T, router *httprouter.Router, token string) []models.Session {
+	t.Helper()
+
+	rr := makeRequest(t, router, "GET", "/me/sessions", nil, token)
+	response := parseResponse(t, rr)
+	assertStatusCode(t, http.StatusOK, rr.Code)
+
+	var sessions []models.Session
+	json.Unmarshal(response.Data, &sessions)
+	return sessions
+}
+
+func TestMultipleSessions(t *testing.T) {
+	router := testRouter()
+
+	registerBody := map[string]interface{}{
+		"username": "testuser_sessions",
+		"password": "password123",
+		"name":     "Test User Sessions",
+	}
+	makeRequest(t, router, "POST", "/register", registerBody, "")
+	defer cleanupTestUser(t, "testuser_sessions")
+
+	t.Run("Success - Two logins stay valid", func(t *testing.T) {
+		first := loginTestUser(t, router, "testuser_sessions", "password123")
+		second := loginTestUser(t, router, "testuser_sessions", "password123")
+
+		rr := makeRequest(t, router, "GET", "/me", nil, first)
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		rr = makeRequest(t, router, "GET", "/me", nil, second)
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		sessions := getTestSessions(t, router, second)
+		if len(sessions) != 2 {
+			t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+		}
+
+		current := 0
+		for _, session := range sessions {
+			if session.Current {
+				current++
+			}
+		}
+		if current != 1 {
+			t.Errorf("Expected exactly 1 current session, got %d", current)
+		}
+	})
+
+	t.Run("Success - Revoke one session", func(t *testing.T) {
+		token := loginTestUser(t, router, "testuser_sessions", "password123")
+		other := loginTestUser(t, router, "testuser_sessions", "password123")
+
+		var otherID string
+		for _, session := range getTestSessions(t, router, other) {
+			if session.Current {
+				otherID = session.SessionID
+			}
+		}
+
+		rr := makeRequest(t, router, "DELETE", "/me/sessions/"+otherID, nil, token)
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		rr = makeRequest(t, router, "GET", "/me", nil, other)
+		assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+
+		rr = makeRequest(t, router, "GET", "/me", nil, token)
+		assertStatusCode(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Error - Revoke unknown session", func(t *testing.T) {
+		token := loginTestUser(t, router, "testuser_sessions", "password123")
+
+		rr := makeRequest(t, router, "DELETE", "/me/sessions/unknown", nil, token)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusNotFound, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Success - Logout everywhere", func(t *testing.T) {
+		first := loginTestUser(t, router, "testuser_sessions", "password123")
+		second := loginTestUser(t, router, "testuser_sessions", "password123")
+
+		rr := makeRequest(t, router, "DELETE", "/me/sessions", nil, first)
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		rr = makeRequest(t, router, "GET", "/me", nil, first)
+		assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+
+		rr = makeRequest(t, router, "GET", "/me", nil, second)
+		assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestSessionLimit(t *testing.T) {
+	router := testRouter()
+	maxSessions := config.LoadConfig().Session.MaxPerUser
+	if maxSessions <= 0 {
+		t.Skip("Session limit disabled")
+	}
+
+	registerBody := map[string]interface{}{
+		"username": "testuser_session_limit",
+		"password": "password123",
+		"name":     "Test User Session Limit",
+	}
+	makeRequest(t, router, "POST", "/register", registerBody, "")
+	defer cleanupTestUser(t, "testuser_session_limit")
+
+	oldest := loginTestUser(t, router, "testuser_session_limit", "password123")
+	var latest string
+	for i := 0; i < maxSessions; i++ {
+		latest = loginTestUser(t, router, "testuser_session_limit", "password123")
+	}
+
+	rr := makeRequest(t, router, "GET", "/me", nil, oldest)
+	assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+
+	sessions := getTestSessions(t, router, latest)
+	if len(sessions) != maxSessions {
+		t.Errorf("Expected %d sessions, got %d", maxSessions, len(sessions))
+	}
+}
@@ -0,0 +1,163 @@
+package test
+
+import (
+	"contact-management/src/app"
+	"contact-management/src/apps"
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// getProductStock reads the current stock of a product directly from the database
+func getProductStock(t *testing.T, productID int) int {
+	t.Helper()
+
+	db := testApp().DB
+
+	var stock int
+	if err := db.QueryRow("SELECT stock FROM products WHERE product_id = ?", productID).Scan(&stock); err != nil {
+		t.Fatalf("Failed to read stock of product %d: %v", productID, err)
+	}
+	return stock
+}
+
+// cleanupTestOrdersOfProduct removes all orders of a test product from database
+func cleanupTestOrdersOfProduct(t *testing.T, productID int) {
+	t.Helper()
+
+	db := testApp().DB
+
+	_, err := db.Exec("DELETE FROM orders WHERE product_id = ?", productID)
+	if err != nil {
+		t.Logf("Warning: Failed to cleanup orders of product %d: %v", productID, err)
+	}
+}
+
+func TestStockReservationUnderConcurrency(t *testing.T) {
+	token := getValidToken(t, "testuser_stock")
+	defer cleanupTestUser(t, "testuser_stock")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	const stock = 5
+	const buyers = 40
+
+	productID := createTestProduct(t, token, brandProductID, stock)
+	defer cleanupTestProduct(t, productID)
+	defer cleanupTestOrdersOfProduct(t, productID)
+
+	// A dedicated pool large enough for every buyer to hold a connection at once
+	db, err := apps.Connect(testConfig())
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(buyers)
+
+	application, err := app.New(testConfig(),
+		app.WithDB(db),
+		app.WithLogger(testLogger()),
+		app.WithTokenStore(testTokenStore()),
+		app.WithGateway(testGateway()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to build application: %v", err)
+	}
+	defer application.Close()
+	orderService := application.OrderService
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded, outOfStock := 0, 0
+	var unexpected []error
+
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			err := orderService.CreateOrder(context.Background(), &models.Order{
+				ProductID: productID,
+				Name:      "Concurrent Buyer",
+				Email:     fmt.Sprintf("buyer%d@example.com", i),
+				Method:    "qris",
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, repositories.ErrorOutOfStock):
+				outOfStock++
+			default:
+				unexpected = append(unexpected, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range unexpected {
+		t.Errorf("Unexpected checkout error: %v", err)
+	}
+	if succeeded != stock {
+		t.Errorf("Expected exactly %d successful orders, got %d", stock, succeeded)
+	}
+	if outOfStock != buyers-succeeded-len(unexpected) {
+		t.Errorf("Expected remaining %d buyers to be rejected, got %d", buyers-stock, outOfStock)
+	}
+	if remaining := getProductStock(t, productID); remaining != 0 {
+		t.Errorf("Expected stock to be 0, got %d", remaining)
+	}
+}
+
+func TestStockReleasedOnCancel(t *testing.T) {
+	router := testRouter()
+	token := getValidToken(t, "testuser_stock_release")
+	defer cleanupTestUser(t, "testuser_stock_release")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 1)
+	defer cleanupTestProduct(t, productID)
+	defer cleanupTestOrdersOfProduct(t, productID)
+
+	orderID := createTestOrder(t, productID)
+
+	t.Run("Error - Last unit already reserved", func(t *testing.T) {
+		body := map[string]interface{}{
+			"product_id": productID,
+			"name":       "Second Buyer",
+			"email":      "second@example.com",
+			"method":     "qris",
+		}
+
+		rr := makeRequest(t, router, "POST", "/orders", body, "")
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusConflict, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Success - Cancel releases reservation once", func(t *testing.T) {
+		rr := makeRequest(t, router, "PUT", fmt.Sprintf("/orders/%d/status", orderID), map[string]interface{}{"status": "cancelled"}, token)
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		// Repeating the cancel must not release the unit again
+		rr = makeRequest(t, router, "PUT", fmt.Sprintf("/orders/%d/status", orderID), map[string]interface{}{"status": "cancelled"}, token)
+		assertStatusCode(t, http.StatusConflict, rr.Code)
+
+		if stock := getProductStock(t, productID); stock != 1 {
+			t.Errorf("Expected stock to be released back to 1, got %d", stock)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package test
+
+import (
+	"contact-management/src/apps"
+	"contact-management/src/models"
+	"contact-management/src/utils"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced time source for the in-memory token store
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestMemoryTokenStoreTTL(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Now()}
+	store := utils.NewMemoryTokenStore().WithClock(clock.Now)
+
+	session := &models.Session{SessionID: "sess-1", Username: "testuser_store"}
+	if err := store.SaveSession(ctx, session, time.Hour); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+	if err := store.SaveRefreshToken(ctx, "sess-1", "hash-1", time.Hour); err != nil {
+		t.Fatalf("Failed to save refresh token: %v", err)
+	}
+
+	t.Run("Success - Entries readable before TTL", func(t *testing.T) {
+		if _, err := store.GetSession(ctx, "sess-1"); err != nil {
+			t.Errorf("Expected session to exist, got %v", err)
+		}
+		if sessionID, err := store.FindRefreshToken(ctx, "hash-1"); err != nil || sessionID != "sess-1" {
+			t.Errorf("Expected refresh token of sess-1, got %q, %v", sessionID, err)
+		}
+	})
+
+	t.Run("Success - Entries gone after TTL", func(t *testing.T) {
+		clock.Advance(2 * time.Hour)
+
+		if _, err := store.GetSession(ctx, "sess-1"); !errors.Is(err, utils.ErrSessionNotFound) {
+			t.Errorf("Expected ErrSessionNotFound, got %v", err)
+		}
+		if _, err := store.FindRefreshToken(ctx, "hash-1"); !errors.Is(err, utils.ErrInvalidRefreshToken) {
+			t.Errorf("Expected ErrInvalidRefreshToken, got %v", err)
+		}
+		if ids, _ := store.ListSessionIDs(ctx, "testuser_store"); len(ids) != 0 {
+			t.Errorf("Expected no session ids, got %v", ids)
+		}
+	})
+}
+
+func TestMemoryTokenStoreRotate(t *testing.T) {
+	ctx := context.Background()
+	store := utils.NewMemoryTokenStore()
+
+	session := &models.Session{SessionID: "sess-2", Username: "testuser_store"}
+	store.SaveSession(ctx, session, time.Hour)
+	store.SaveRefreshToken(ctx, "sess-2", "hash-a", time.Hour)
+
+	if err := store.RotateRefreshToken(ctx, session, "hash-a", "hash-b", time.Hour); err != nil {
+		t.Fatalf("Expected rotation to succeed, got %v", err)
+	}
+	if err := store.RotateRefreshToken(ctx, session, "hash-a", "hash-c", time.Hour); !errors.Is(err, utils.ErrRefreshTokenReused) {
+		t.Errorf("Expected ErrRefreshTokenReused, got %v", err)
+	}
+
+	if err := store.DeleteSession(ctx, "testuser_store", "sess-2"); err != nil {
+		t.Fatalf("Failed to delete session: %v", err)
+	}
+	if err := store.RotateRefreshToken(ctx, session, "hash-b", "hash-d", time.Hour); !errors.Is(err, utils.ErrInvalidRefreshToken) {
+		t.Errorf("Expected ErrInvalidRefreshToken after delete, got %v", err)
+	}
+	if err := store.DeleteSession(ctx, "testuser_store", "sess-2"); !errors.Is(err, utils.ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound on second delete, got %v", err)
+	}
+}
+
+func TestTokenServiceWithMemoryStore(t *testing.T) {
+	cfg := newTokenConfig("", "")
+	cfg.Session.MaxPerUser = 2
+	tokenService, err := utils.NewTokenService(cfg, utils.NewMemoryTokenStore(), testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create token service: %v", err)
+	}
+
+	t.Run("Success - Login, refresh and logout", func(t *testing.T) {
+		tokens, err := tokenService.GenerateTokenPair(context.Background(), "testuser_flow", models.RoleCustomer, models.SessionMeta{})
+		if err != nil {
+			t.Fatalf("Failed to generate tokens: %v", err)
+		}
+		if _, err := tokenService.VerifyToken(context.Background(), tokens.Token); err != nil {
+			t.Fatalf("Expected token to be valid, got %v", err)
+		}
+
+		refreshed, err := tokenService.RefreshTokens(context.Background(), tokens.RefreshToken)
+		if err != nil {
+			t.Fatalf("Expected refresh to succeed, got %v", err)
+		}
+
+		if err := tokenService.RevokeToken(context.Background(), refreshed.Token, "testuser_flow"); err != nil {
+			t.Fatalf("Failed to revoke token: %v", err)
+		}
+		if _, err := tokenService.VerifyToken(context.Background(), refreshed.Token); err == nil {
+			t.Error("Expected revoked token to be rejected")
+		}
+	})
+
+	t.Run("Error - Reused refresh token revokes the session", func(t *testing.T) {
+		tokens, _ := tokenService.GenerateTokenPair(context.Background(), "testuser_flow", models.RoleCustomer, models.SessionMeta{})
+		refreshed, _ := tokenService.RefreshTokens(context.Background(), tokens.RefreshToken)
+
+		if _, err := tokenService.RefreshTokens(context.Background(), tokens.RefreshToken); !errors.Is(err, utils.ErrRefreshTokenReused) {
+			t.Fatalf("Expected ErrRefreshTokenReused, got %v", err)
+		}
+		if _, err := tokenService.VerifyToken(context.Background(), refreshed.Token); err == nil {
+			t.Error("Expected access token of revoked family to be rejected")
+		}
+	})
+
+	t.Run("Success - Oldest session evicted at limit", func(t *testing.T) {
+		tokenService.RevokeAllSessions(context.Background(), "testuser_limit")
+
+		oldest, _ := tokenService.GenerateTokenPair(context.Background(), "testuser_limit", models.RoleCustomer, models.SessionMeta{})
+		tokenService.GenerateTokenPair(context.Background(), "testuser_limit", models.RoleCustomer, models.SessionMeta{})
+		tokenService.GenerateTokenPair(context.Background(), "testuser_limit", models.RoleCustomer, models.SessionMeta{})
+
+		if _, err := tokenService.VerifyToken(context.Background(), oldest.Token); err == nil {
+			t.Error("Expected oldest session to be evicted")
+		}
+		if sessions, _ := tokenService.ListSessions(context.Background(), "testuser_limit"); len(sessions) != 2 {
+			t.Errorf("Expected 2 sessions, got %d", len(sessions))
+		}
+	})
+}
+
+func TestTokenServiceUsesRequestContext(t *testing.T) {
+	client := apps.NewRedisClient(testConfig().Redis)
+	defer client.Close()
+	if err := apps.PingRedis(client, time.Second); err != nil {
+		t.Skip("Redis is not reachable: ", err)
+	}
+
+	tokenService, err := utils.NewTokenService(newTokenConfig("", ""), utils.NewRedisTokenStore(client), testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create token service: %v", err)
+	}
+	defer tokenService.RevokeAllSessions(context.Background(), "testuser_token_ctx")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A cancelled request must not reach the store
+	if _, err := tokenService.GenerateTokenPair(ctx, "testuser_token_ctx", models.RoleCustomer, models.SessionMeta{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from GenerateTokenPair, got %v", err)
+	}
+	if err := tokenService.RevokeAllSessions(ctx, "testuser_token_ctx"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from RevokeAllSessions, got %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package test
+
+import (
+	"contact-management/src/config"
+	"contact-management/src/models"
+	"contact-management/src/utils"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTokenConfig(keys, activeKeyID string) *config.Config {
+	return &config.Config{
+		JWT: config.JWTConfig{
+			Secret:            "test-secret",
+			Expiration:        "1h",
+			RefreshExpiration: "720h",
+			Issuer:            "test-issuer",
+			Audience:          "test-audience",
+			Keys:              keys,
+			ActiveKeyID:       activeKeyID,
+		},
+	}
+}
+
+// signTestToken signs arbitrary claims with the given kid and secret
+func signTestToken(t *testing.T, claims utils.Claims, kid, secret string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func validTestClaims() utils.Claims {
+	now := time.Now()
+	return utils.Claims{
+		Username: "testuser_token",
+		Role:     models.RoleCustomer,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "test-session",
+			Issuer:    "test-issuer",
+			Audience:  jwt.ClaimStrings{"test-audience"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	tokenService, err := utils.NewTokenService(newTokenConfig("k1:secret-one,k2:secret-two", "k2"), utils.NewMemoryTokenStore(), testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create token service: %v", err)
+	}
+
+	t.Run("Success - Token signed with any active key", func(t *testing.T) {
+		for kid, secret := range map[string]string{"k1": "secret-one", "k2": "secret-two"} {
+			claims, err := tokenService.ParseToken(signTestToken(t, validTestClaims(), kid, secret))
+			if err != nil {
+				t.Errorf("Expected token signed with %s to be valid, got %v", kid, err)
+				continue
+			}
+			if claims.Username != "testuser_token" {
+				t.Errorf("Expected username testuser_token, got %s", claims.Username)
+			}
+		}
+	})
+
+	cases := map[string]func() string{
+		"Error - Wrong signature": func() string {
+			return signTestToken(t, validTestClaims(), "k1", "not-the-secret")
+		},
+		"Error - Unknown kid": func() string {
+			return signTestToken(t, validTestClaims(), "k3", "secret-one")
+		},
+		"Error - Expired": func() string {
+			claims := validTestClaims()
+			claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+			return signTestToken(t, claims, "k1", "secret-one")
+		},
+		"Error - Missing exp": func() string {
+			claims := validTestClaims()
+			claims.ExpiresAt = nil
+			return signTestToken(t, claims, "k1", "secret-one")
+		},
+		"Error - Issued in the future": func() string {
+			claims := validTestClaims()
+			claims.IssuedAt = jwt.NewNumericDate(time.Now().Add(time.Hour))
+			return signTestToken(t, claims, "k1", "secret-one")
+		},
+		"Error - Wrong issuer": func() string {
+			claims := validTestClaims()
+			claims.Issuer = "someone-else"
+			return signTestToken(t, claims, "k1", "secret-one")
+		},
+		"Error - Wrong audience": func() string {
+			claims := validTestClaims()
+			claims.Audience = jwt.ClaimStrings{"another-api"}
+			return signTestToken(t, claims, "k1", "secret-one")
+		},
+		"Error - Unknown role": func() string {
+			claims := validTestClaims()
+			claims.Role = "superuser"
+			return signTestToken(t, claims, "k1", "secret-one")
+		},
+		"Error - Missing session id": func() string {
+			claims := validTestClaims()
+			claims.ID = ""
+			return signTestToken(t, claims, "k1", "secret-one")
+		},
+	}
+
+	for name, token := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := tokenService.ParseToken(token()); err == nil {
+				t.Error("Expected token to be rejected")
+			}
+		})
+	}
+
+	t.Run("Error - Algorithm none", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodNone, validTestClaims())
+		token.Header["kid"] = "k1"
+		signed, _ := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+		if _, err := tokenService.ParseToken(signed); err == nil {
+			t.Error("Expected unsigned token to be rejected")
+		}
+	})
+}
+
+func TestNewTokenServiceConfig(t *testing.T) {
+	t.Run("Error - Active key not in key set", func(t *testing.T) {
+		if _, err := utils.NewTokenService(newTokenConfig("k1:secret-one", "k2"), utils.NewMemoryTokenStore(), testLogger()); err == nil {
+			t.Error("Expected missing active key to be rejected")
+		}
+	})
+
+	t.Run("Error - Invalid expiration", func(t *testing.T) {
+		cfg := newTokenConfig("", "")
+		cfg.JWT.Expiration = "forever"
+		if _, err := utils.NewTokenService(cfg, utils.NewMemoryTokenStore(), testLogger()); err == nil {
+			t.Error("Expected invalid expiration to be rejected")
+		}
+	})
+
+	t.Run("Error - Refresh expiration shorter than access expiration", func(t *testing.T) {
+		cfg := newTokenConfig("", "")
+		cfg.JWT.RefreshExpiration = "30m"
+		if _, err := utils.NewTokenService(cfg, utils.NewMemoryTokenStore(), testLogger()); err == nil {
+			t.Error("Expected refresh expiration shorter than access expiration to be rejected")
+		}
+	})
+
+	t.Run("Error - Empty secret", func(t *testing.T) {
+		cfg := newTokenConfig("", "")
+		cfg.JWT.Secret = ""
+		if _, err := utils.NewTokenService(cfg, utils.NewMemoryTokenStore(), testLogger()); err == nil {
+			t.Error("Expected empty secret to be rejected")
+		}
+	})
+
+	t.Run("Error - Example secret outside development", func(t *testing.T) {
+		for _, cfg := range []*config.Config{newTokenConfig("", ""), newTokenConfig("k1:default-secret-key", "k1")} {
+			cfg.JWT.Secret = "default-secret-key"
+			cfg.App.Env = "production"
+			if _, err := utils.NewTokenService(cfg, utils.NewMemoryTokenStore(), testLogger()); err == nil {
+				t.Error("Expected example secret to be rejected in production")
+			}
+		}
+	})
+
+	t.Run("Success - Example secret in development", func(t *testing.T) {
+		cfg := newTokenConfig("", "")
+		cfg.JWT.Secret = "default-secret-key"
+		cfg.App.Env = "development"
+		if _, err := utils.NewTokenService(cfg, utils.NewMemoryTokenStore(), testLogger()); err != nil {
+			t.Errorf("Expected example secret to be accepted in development, got %v", err)
+		}
+	})
+}
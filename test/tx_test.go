@@ -0,0 +1,177 @@
+package test
+
+import (
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// countOrdersOfProduct counts the orders of a product directly from the database
+func countOrdersOfProduct(t *testing.T, productID int) int {
+	t.Helper()
+
+	var count int
+	if err := testApp().DB.QueryRow("SELECT COUNT(*) FROM orders WHERE product_id = ?", productID).Scan(&count); err != nil {
+		t.Fatalf("Failed to count orders of product %d: %v", productID, err)
+	}
+	return count
+}
+
+func TestWithinTx(t *testing.T) {
+	token := getValidToken(t, "testuser_tx")
+	defer cleanupTestUser(t, "testuser_tx")
+
+	categoryID, brandProductID := createTestBrandProductForProduct(t, token)
+	defer cleanupTestCategory(t, categoryID)
+	defer cleanupTestBrandProduct(t, brandProductID)
+
+	productID := createTestProduct(t, token, brandProductID, 5)
+	defer cleanupTestProduct(t, productID)
+	defer cleanupTestOrdersOfProduct(t, productID)
+
+	db := repositories.NewDatabase(testApp().DB, repositories.Dialect(testConfig().Database.Driver), testConfig().Database.QueryTimeout)
+	orderRepo := repositories.NewOrderRepository(db)
+
+	newOrder := func(code string) *models.Order {
+		return &models.Order{
+			OrderCode: code,
+			ProductID: productID,
+			Name:      "Tx Buyer",
+			Email:     "tx@example.com",
+			Method:    "qris",
+			Status:    models.OrderStatusPending,
+		}
+	}
+
+	t.Run("Success - Repositories join the transaction and commit together", func(t *testing.T) {
+		err := db.WithinTx(context.Background(), func(ctx context.Context) error {
+			if err := orderRepo.CreateOrder(ctx, newOrder("ORD-TX-COMMIT-1")); err != nil {
+				return err
+			}
+			return orderRepo.CreateOrder(ctx, newOrder("ORD-TX-COMMIT-2"))
+		})
+		if err != nil {
+			t.Fatalf("Expected transaction to commit, got %v", err)
+		}
+
+		if stock := getProductStock(t, productID); stock != 3 {
+			t.Errorf("Expected stock 3 after two orders, got %d", stock)
+		}
+		if count := countOrdersOfProduct(t, productID); count != 2 {
+			t.Errorf("Expected 2 orders, got %d", count)
+		}
+	})
+
+	t.Run("Error - Failure rolls back every repository call", func(t *testing.T) {
+		errAbort := errors.New("abort")
+		err := db.WithinTx(context.Background(), func(ctx context.Context) error {
+			if err := orderRepo.CreateOrder(ctx, newOrder("ORD-TX-ROLLBACK")); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("Expected abort error, got %v", err)
+		}
+
+		if stock := getProductStock(t, productID); stock != 3 {
+			t.Errorf("Expected reserved stock to be rolled back to 3, got %d", stock)
+		}
+		if count := countOrdersOfProduct(t, productID); count != 2 {
+			t.Errorf("Expected rolled back order to be gone, got %d orders", count)
+		}
+	})
+
+	t.Run("Success - Deadlock is retried", func(t *testing.T) {
+		// The dialect only decides which driver errors count as a deadlock
+		mysqlDB := repositories.NewDatabase(testApp().DB, repositories.DialectMySQL, 0)
+
+		attempts := 0
+		err := mysqlDB.WithinTx(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Expected retried transaction to succeed, got %v", err)
+		}
+		if attempts != 2 {
+			t.Errorf("Expected 2 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("Error - Deadlock retries are bounded", func(t *testing.T) {
+		mysqlDB := repositories.NewDatabase(testApp().DB, repositories.DialectMySQL, 0)
+
+		attempts := 0
+		err := mysqlDB.WithinTx(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return &mysql.MySQLError{Number: 1213, Message: fmt.Sprintf("Deadlock %d", attempts)}
+		})
+		var mysqlErr *mysql.MySQLError
+		if !errors.As(err, &mysqlErr) {
+			t.Fatalf("Expected deadlock error after retries, got %v", err)
+		}
+		if attempts < 2 {
+			t.Errorf("Expected deadlock to be retried, got %d attempts", attempts)
+		}
+	})
+
+	t.Run("Success - After commit hooks run once for the committed attempt", func(t *testing.T) {
+		mysqlDB := repositories.NewDatabase(testApp().DB, repositories.DialectMySQL, 0)
+
+		attempts, calls := 0, 0
+		err := mysqlDB.WithinTx(context.Background(), func(ctx context.Context) error {
+			attempts++
+			repositories.AfterCommit(ctx, func(ctx context.Context) { calls++ })
+			if attempts == 1 {
+				return &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+			}
+			if calls != 0 {
+				t.Error("Expected hook to wait for the commit")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Expected retried transaction to succeed, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("Expected hook to run once, got %d", calls)
+		}
+	})
+
+	t.Run("Error - Rollback drops after commit hooks", func(t *testing.T) {
+		calls := 0
+		err := db.WithinTx(context.Background(), func(ctx context.Context) error {
+			repositories.AfterCommit(ctx, func(ctx context.Context) { calls++ })
+			return repositories.ErrorOutOfStock
+		})
+		if !errors.Is(err, repositories.ErrorOutOfStock) {
+			t.Fatalf("Expected out of stock error, got %v", err)
+		}
+		if calls != 0 {
+			t.Errorf("Expected hook to be dropped, got %d calls", calls)
+		}
+	})
+
+	t.Run("Error - Other errors are not retried", func(t *testing.T) {
+		attempts := 0
+		err := db.WithinTx(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return repositories.ErrorOutOfStock
+		})
+		if !errors.Is(err, repositories.ErrorOutOfStock) {
+			t.Fatalf("Expected out of stock error, got %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("Expected a single attempt, got %d", attempts)
+		}
+	})
+}
@@ -1 +1,183 @@
 package test
+
+import (
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestUserPermissions(t *testing.T) {
+	router := testRouter()
+	adminToken := getValidTokenWithRole(t, "testuser_rbac_admin", models.RoleAdmin)
+	staffToken := getValidTokenWithRole(t, "testuser_rbac_staff", models.RoleStaff)
+	customerToken := getValidTokenWithRole(t, "testuser_rbac_customer", models.RoleCustomer)
+	defer cleanupTestUser(t, "testuser_rbac_admin")
+	defer cleanupTestUser(t, "testuser_rbac_staff")
+	defer cleanupTestUser(t, "testuser_rbac_customer")
+
+	t.Run("Error - Customer cannot list users", func(t *testing.T) {
+		rr := makeRequest(t, router, "GET", "/users", nil, customerToken)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusForbidden, rr.Code)
+		assertResponseStatus(t, "error", response)
+	})
+
+	t.Run("Error - Customer cannot delete users", func(t *testing.T) {
+		rr := makeRequest(t, router, "DELETE", "/users/testuser_rbac_admin", nil, customerToken)
+
+		assertStatusCode(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("Success - Staff can create customer", func(t *testing.T) {
+		body := map[string]interface{}{
+			"username": "testuser_rbac_new_customer",
+			"password": "password123",
+		}
+		defer cleanupTestUser(t, "testuser_rbac_new_customer")
+
+		rr := makeRequest(t, router, "POST", "/users", body, staffToken)
+		response := parseResponse(t, rr)
+
+		assertStatusCode(t, http.StatusCreated, rr.Code)
+
+		var data map[string]interface{}
+		json.Unmarshal(response.Data, &data)
+		if data["role"] != string(models.RoleCustomer) {
+			t.Errorf("Expected role customer, got %v", data["role"])
+		}
+	})
+
+	t.Run("Error - Staff cannot create admin", func(t *testing.T) {
+		body := map[string]interface{}{
+			"username": "testuser_rbac_new_admin",
+			"password": "password123",
+			"role":     "admin",
+		}
+		defer cleanupTestUser(t, "testuser_rbac_new_admin")
+
+		rr := makeRequest(t, router, "POST", "/users", body, staffToken)
+
+		assertStatusCode(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("Success - Admin can create staff", func(t *testing.T) {
+		body := map[string]interface{}{
+			"username": "testuser_rbac_new_staff",
+			"password": "password123",
+			"role":     "staff",
+		}
+		defer cleanupTestUser(t, "testuser_rbac_new_staff")
+
+		rr := makeRequest(t, router, "POST", "/users", body, adminToken)
+
+		assertStatusCode(t, http.StatusCreated, rr.Code)
+
+		rr = makeRequest(t, router, "DELETE", "/users/testuser_rbac_new_staff", nil, staffToken)
+		assertStatusCode(t, http.StatusForbidden, rr.Code)
+
+		rr = makeRequest(t, router, "DELETE", "/users/testuser_rbac_new_staff", nil, adminToken)
+		assertStatusCode(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Error - Invalid role", func(t *testing.T) {
+		body := map[string]interface{}{
+			"username": "testuser_rbac_invalid_role",
+			"password": "password123",
+			"role":     "superuser",
+		}
+		defer cleanupTestUser(t, "testuser_rbac_invalid_role")
+
+		rr := makeRequest(t, router, "POST", "/users", body, adminToken)
+
+		assertStatusCode(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestRegisterAlwaysCustomer(t *testing.T) {
+	router := testRouter()
+
+	body := map[string]interface{}{
+		"username": "testuser_register_role",
+		"password": "password123",
+		"role":     "admin",
+	}
+	makeRequest(t, router, "POST", "/register", body, "")
+	defer cleanupTestUser(t, "testuser_register_role")
+
+	token := loginTestUser(t, router, "testuser_register_role", "password123")
+
+	rr := makeRequest(t, router, "GET", "/me", nil, token)
+	response := parseResponse(t, rr)
+	assertStatusCode(t, http.StatusOK, rr.Code)
+
+	var data map[string]interface{}
+	json.Unmarshal(response.Data, &data)
+	if data["role"] != string(models.RoleCustomer) {
+		t.Errorf("Expected self-registered user to be customer, got %v", data["role"])
+	}
+
+	rr = makeRequest(t, router, "GET", "/users", nil, token)
+	assertStatusCode(t, http.StatusForbidden, rr.Code)
+}
+
+func TestResetPassword(t *testing.T) {
+	router := testRouter()
+	userService := testApp().UserService
+
+	user := &models.User{Username: "testuser_reset", Password: "password123", Role: models.RoleStaff}
+	if err := userService.CreateUser(context.Background(), user, models.RoleAdmin); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	defer cleanupTestUser(t, "testuser_reset")
+
+	oldToken := loginTestUser(t, router, "testuser_reset", "password123")
+
+	t.Run("Success - Password changed and sessions revoked", func(t *testing.T) {
+		if err := userService.ResetPassword(context.Background(), "testuser_reset", "newpassword456"); err != nil {
+			t.Fatalf("Failed to reset password: %v", err)
+		}
+
+		rr := makeRequest(t, router, "GET", "/me", nil, oldToken)
+		assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+
+		body := map[string]interface{}{"username": "testuser_reset", "password": "password123"}
+		rr = makeRequest(t, router, "POST", "/login", body, "")
+		assertStatusCode(t, http.StatusUnauthorized, rr.Code)
+
+		token := loginTestUser(t, router, "testuser_reset", "newpassword456")
+		rr = makeRequest(t, router, "GET", "/me", nil, token)
+		response := parseResponse(t, rr)
+
+		var data map[string]interface{}
+		json.Unmarshal(response.Data, &data)
+		if data["role"] != string(models.RoleStaff) {
+			t.Errorf("Expected role to be kept, got %v", data["role"])
+		}
+	})
+
+	t.Run("Error - Unknown user", func(t *testing.T) {
+		err := userService.ResetPassword(context.Background(), "testuser_reset_missing", "newpassword456")
+		if !errors.Is(err, repositories.ErrUserNotFound) {
+			t.Errorf("Expected ErrUserNotFound, got %v", err)
+		}
+	})
+}
+
+func TestDeleteUserQueryError(t *testing.T) {
+	db := repositories.NewDatabase(testApp().DB, repositories.Dialect(testConfig().Database.Driver), testConfig().Database.QueryTimeout)
+	userRepo := repositories.NewUserRepository(db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A failed query must surface as an error instead of panicking on a nil result
+	row, err := userRepo.DeleteUser(ctx, "testuser_delete_cancelled")
+	if !errors.Is(err, context.Canceled) || row != 0 {
+		t.Errorf("Expected context.Canceled and no rows, got %d, %v", row, err)
+	}
+}
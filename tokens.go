@@ -0,0 +1,80 @@
+package main
+
+import (
+	"contact-management/src/config"
+	"contact-management/src/repositories"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+const tokensUsage = "usage: tokens revoke-all --username U | tokens revoke-all --all"
+
+// revokeAllPageSize adalah jumlah user yang dibaca per halaman saat mencabut sesi semua user.
+const revokeAllPageSize = 100
+
+// runTokens mencabut sesi dan refresh token, misalnya setelah kebocoran kredensial.
+func runTokens(cfg *config.Config, logger *logrus.Logger, args []string) error {
+	if len(args) == 0 || args[0] != "revoke-all" {
+		return errors.New(tokensUsage)
+	}
+
+	flags := newFlagSet("tokens revoke-all")
+	username := flags.String("username", "", "username yang sesinya dicabut")
+	all := flags.Bool("all", false, "cabut sesi semua user")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if (*username == "") == !*all {
+		return errors.New(tokensUsage)
+	}
+	if err := requireSharedStore(cfg); err != nil {
+		return err
+	}
+
+	tokenService, redisClient, err := openTokenService(cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer closeRedis(redisClient, logger)
+
+	ctx := context.Background()
+	if *username != "" {
+		if err := tokenService.RevokeAllSessions(ctx, *username); err != nil {
+			return err
+		}
+		logger.WithField("username", *username).Warn("Semua sesi user dicabut lewat CLI")
+		fmt.Printf("Semua sesi %s dicabut\n", *username)
+		return nil
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db, logger)
+
+	userRepo := repositories.NewUserRepository(repositoryDatabase(cfg, db))
+	revoked := 0
+	for page := 1; ; page++ {
+		users, total, err := userRepo.GetUsers(ctx, page, revokeAllPageSize)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if err := tokenService.RevokeAllSessions(ctx, user.Username); err != nil {
+				return err
+			}
+			revoked++
+		}
+		if len(users) == 0 || page*revokeAllPageSize >= total {
+			break
+		}
+	}
+
+	logger.WithField("users", revoked).Warn("Semua sesi semua user dicabut lewat CLI")
+	fmt.Printf("Sesi %d user dicabut\n", revoked)
+	return nil
+}
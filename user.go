@@ -0,0 +1,100 @@
+package main
+
+import (
+	"contact-management/src/config"
+	"contact-management/src/models"
+	"contact-management/src/repositories"
+	"contact-management/src/services"
+	"contact-management/src/utils"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+const userUsage = "usage: user create --username U [--role admin|staff|customer] [--password P] | user reset-password --username U [--password P] | user unlock --username U"
+
+// runUser mengelola akun langsung dari server, misalnya membuat admin pertama yang
+// tidak bisa dibuat lewat /register karena endpoint itu selalu memberi role customer.
+func runUser(cfg *config.Config, logger *logrus.Logger, args []string) error {
+	if len(args) == 0 {
+		return errors.New(userUsage)
+	}
+
+	flags := newFlagSet("user " + args[0])
+	username := flags.String("username", "", "username")
+	password := flags.String("password", "", "password; dibaca dari stdin bila kosong")
+	role := flags.String("role", string(models.RoleCustomer), "role user baru")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *username == "" {
+		return errors.New(userUsage)
+	}
+
+	if args[0] != "create" && args[0] != "reset-password" && args[0] != "unlock" {
+		return errors.New(userUsage)
+	}
+	// reset-password mencabut sesi dan unlock membuka kunci login milik server
+	if args[0] != "create" {
+		if err := requireSharedStore(cfg); err != nil {
+			return err
+		}
+	}
+
+	if *password == "" && args[0] != "unlock" {
+		var err error
+		if *password, err = readPassword(os.Stdin); err != nil {
+			return err
+		}
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db, logger)
+
+	tokenService, redisClient, err := openTokenService(cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer closeRedis(redisClient, logger)
+
+	attemptStore, err := utils.NewLoginAttemptStore(cfg.Session, redisClient)
+	if err != nil {
+		return err
+	}
+	database := repositoryDatabase(cfg, db)
+	auditService := services.NewAuditService(repositories.NewAuditLogRepository(database))
+	loginGuard := services.NewLoginGuard(attemptStore, cfg.Login, auditService, logger)
+
+	userService := services.NewUserService(repositories.NewUserRepository(database), repositories.NewPasswordResetRepository(database), tokenService, loginGuard, database, auditService, cfg.Password)
+
+	ctx := context.Background()
+	switch args[0] {
+	case "create":
+		user := &models.User{Username: *username, Password: *password, Role: models.Role(*role)}
+		// Operator CLI punya akses penuh ke server, jadi bertindak sebagai admin.
+		if err := userService.CreateUser(ctx, user, models.RoleAdmin); err != nil {
+			return err
+		}
+		logger.WithFields(logrus.Fields{"username": user.Username, "role": user.Role}).Warn("User dibuat lewat CLI")
+		fmt.Printf("User %s dibuat dengan role %s\n", user.Username, user.Role)
+	case "reset-password":
+		if err := userService.ResetPassword(ctx, *username, *password); err != nil {
+			return err
+		}
+		logger.WithField("username", *username).Warn("Password direset lewat CLI")
+		fmt.Printf("Password %s direset dan semua sesinya dicabut\n", *username)
+	case "unlock":
+		if err := userService.UnlockUser(ctx, *username, models.RoleAdmin); err != nil {
+			return err
+		}
+		logger.WithField("username", *username).Warn("Kunci login dibuka lewat CLI")
+		fmt.Printf("Kunci login %s dibuka\n", *username)
+	}
+	return nil
+}